@@ -5,17 +5,20 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/antigravity/backend/internal/ai"
 	"github.com/antigravity/backend/internal/config"
 	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/eventbus/subscribers"
 	"github.com/antigravity/backend/internal/handler"
 	"github.com/antigravity/backend/internal/middleware"
 	"github.com/antigravity/backend/internal/repository"
+	"github.com/antigravity/backend/internal/room"
+	"github.com/antigravity/backend/internal/scheduler"
 	"github.com/antigravity/backend/internal/service"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 )
 
@@ -65,20 +68,61 @@ func main() {
 	focusRepo := repository.NewFocusRepository(db)
 	streakRepo := repository.NewStreakRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	studyRoomRepo := repository.NewStudyRoomRepository(db)
+	calendarRepo := repository.NewCalendarRepository(db)
 
 	// Service Layer
 	profileService := service.NewProfileService(profileRepo)
-	squadService := service.NewSquadService(squadRepo)
-	focusService := service.NewFocusService(focusRepo, publisher)
-	streakService := service.NewStreakService(streakRepo, publisher)
+	squadService := service.NewSquadService(squadRepo, publisher)
+	focusService := service.NewFocusService(focusRepo, publisher,
+		time.Duration(cfg.FocusAutoStopGraceMinutes)*time.Minute,
+		time.Duration(cfg.FocusHeartbeatTimeoutMinutes)*time.Minute,
+		cfg.FocusBackfillDailyCapMinutes,
+	)
+	streakService := service.NewStreakService(streakRepo, publisher, cfg.StreakMilestones)
 	nudgeService := service.NewNudgeService(notificationRepo, groqClient, natsBus)
+	squadStreamService := service.NewSquadStreamService(natsBus, squadRepo)
+	studyRoomService := service.NewStudyRoomService(studyRoomRepo, squadRepo, focusService, publisher,
+		time.Duration(cfg.StudyRoomReminderMinutes)*time.Minute,
+	)
+	calendarService := service.NewCalendarService(calendarRepo, cfg.PublicAPIURL)
+
+	// Live squad rooms
+	roomHub := room.NewHub(natsBus, squadRepo, focusService)
+	if err := roomHub.Start(); err != nil {
+		log.Printf("Failed to start squad room hub: %v", err)
+	}
+
+	// Background Jobs
+	jobScheduler := scheduler.New(jobRepo)
+	if err := jobScheduler.Register("streak_risk_detection", cfg.StreakRiskSchedule, streakService.PublishStreakAtRiskEvents); err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	if err := jobScheduler.Register("streak_recalculation", cfg.StreakRecalcSchedule, streakService.TriggerRecalculation); err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	if err := jobScheduler.Register("squad_streak_risk_detection", cfg.StreakRiskSchedule, streakService.PublishSquadStreakRiskEvents); err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	if err := jobScheduler.Register("focus_auto_stop", cfg.FocusAutoStopSchedule, focusService.AutoStopSessions); err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	if err := jobScheduler.Register("study_room_reminders", cfg.StudyRoomReminderSchedule, studyRoomService.SendReminders); err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
 
 	// Handler Layer
 	profileHandler := handler.NewProfileHandler(profileService)
 	squadHandler := handler.NewSquadHandler(squadService)
 	focusHandler := handler.NewFocusHandler(focusService)
+	streamHandler := handler.NewStreamHandler(squadStreamService, cfg.SupabaseJWTSecret)
+	studyRoomHandler := handler.NewStudyRoomHandler(studyRoomService)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	roomHandler := handler.NewRoomHandler(roomHub, cfg.AllowedOrigins)
 	streakHandler := handler.NewStreakHandler(streakService)
 	notificationHandler := handler.NewNotificationHandler(nudgeService)
+	jobHandler := handler.NewJobHandler(jobScheduler)
 	healthHandler := handler.NewHealthHandler()
 
 	// Start Background Consumers
@@ -88,6 +132,54 @@ func main() {
 				log.Printf("Failed to start Nudge Consumer: %v", err)
 			}
 		}()
+
+		activitySubscriber := subscribers.NewActivitySubscriber(natsBus, streakService, cfg.FocusStreakMinMinutes)
+		go func() {
+			if err := activitySubscriber.Start(context.Background()); err != nil {
+				log.Printf("Failed to start Activity Subscriber: %v", err)
+			}
+		}()
+
+		streakBrokenSubscriber := subscribers.NewStreakBrokenSubscriber(natsBus, notificationRepo)
+		go func() {
+			if err := streakBrokenSubscriber.Start(context.Background()); err != nil {
+				log.Printf("Failed to start Streak Broken Subscriber: %v", err)
+			}
+		}()
+
+		milestoneSubscriber := subscribers.NewMilestoneSubscriber(natsBus, groqClient, notificationRepo)
+		go func() {
+			if err := milestoneSubscriber.Start(context.Background()); err != nil {
+				log.Printf("Failed to start Milestone Subscriber: %v", err)
+			}
+		}()
+
+		squadStreakSubscriber := subscribers.NewSquadStreakSubscriber(natsBus, notificationRepo)
+		go func() {
+			if err := squadStreakSubscriber.Start(context.Background()); err != nil {
+				log.Printf("Failed to start Squad Streak Subscriber: %v", err)
+			}
+		}()
+
+		squadOwnerSubscriber := subscribers.NewSquadOwnerSubscriber(natsBus, notificationRepo)
+		go func() {
+			if err := squadOwnerSubscriber.Start(context.Background()); err != nil {
+				log.Printf("Failed to start Squad Owner Subscriber: %v", err)
+			}
+		}()
+
+		studyRoomSubscriber := subscribers.NewStudyRoomSubscriber(natsBus, notificationRepo)
+		go func() {
+			if err := studyRoomSubscriber.Start(context.Background()); err != nil {
+				log.Printf("Failed to start Study Room Subscriber: %v", err)
+			}
+		}()
+	}
+
+	if cfg.SchedulerEnabled {
+		if err := jobScheduler.Start(context.Background()); err != nil {
+			log.Printf("Failed to start scheduler: %v", err)
+		}
 	}
 
 	// Setup router
@@ -97,18 +189,42 @@ func main() {
 	r.Use(chimiddleware.Logger)
 	r.Use(chimiddleware.Recoverer)
 	r.Use(chimiddleware.RequestID)
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   cfg.AllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	r.Use(middleware.CORSMiddleware(cfg.AllowedOrigins))
 
 	// Routes
 	r.Get("/api/v1/health", healthHandler.Health)
 
+	// Calendar feeds (the secret token in the URL is the credential)
+	r.Get("/api/v1/calendar/feeds/{token}.ics", calendarHandler.ServeFeed)
+
+	// Admin routes (static API key, used by operators and external cron)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AdminKeyMiddleware(cfg.AdminAPIKey))
+
+		r.Get("/api/v1/admin/jobs", jobHandler.ListJobs)
+		r.Get("/api/v1/admin/jobs/{name}/runs", jobHandler.GetJobRuns)
+		r.Post("/api/v1/admin/jobs/{name}/pause", jobHandler.PauseJob)
+		r.Post("/api/v1/admin/jobs/{name}/resume", jobHandler.ResumeJob)
+		r.Post("/api/v1/admin/jobs/{name}/trigger", jobHandler.TriggerJob)
+
+		r.Post("/api/v1/admin/streaks/{userID}/freezes", streakHandler.GrantFreezeTokens)
+	})
+
+	// WebSocket routes (token may come as a subprotocol)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.WebSocketAuthMiddleware(cfg.SupabaseJWTSecret))
+
+		r.Get("/api/v1/squads/{squadID}/room", roomHandler.SquadRoom)
+	})
+
+	// Event streams (token may come as a stream ticket)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.StreamAuthMiddleware(cfg.SupabaseJWTSecret))
+
+		r.Get("/api/v1/profile/me/stream", streamHandler.MyStream)
+		r.Get("/api/v1/squads/{squadID}/stream", streamHandler.SquadStream)
+	})
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware(cfg.SupabaseJWTSecret))
@@ -122,24 +238,54 @@ func main() {
 		r.Post("/api/v1/squads", squadHandler.CreateSquad)
 		r.Get("/api/v1/squads", squadHandler.ListMySquads)
 		r.Post("/api/v1/squads/join", squadHandler.JoinSquad)
+		r.Get("/api/v1/squads/leaderboard", squadHandler.GetSquadLeaderboard)
 		r.Get("/api/v1/squads/{squadID}", squadHandler.GetSquadDetail)
 		r.Patch("/api/v1/squads/{squadID}", squadHandler.UpdateSquad)
 		r.Delete("/api/v1/squads/{squadID}", squadHandler.DeleteSquad)
 		r.Delete("/api/v1/squads/{squadID}/members/{userID}", squadHandler.RemoveMember)
+		r.Put("/api/v1/squads/{squadID}/members/{userID}/role", squadHandler.UpdateMemberRole)
+		r.Post("/api/v1/squads/{squadID}/transfer-ownership", squadHandler.TransferOwnership)
 		r.Post("/api/v1/squads/{squadID}/regenerate-code", squadHandler.RegenerateCode)
 
+		// Stream tickets (for EventSource clients)
+		r.Post("/api/v1/stream/tickets", streamHandler.CreateTicket)
+
 		// Focus routes (Body Doubling / Real-time Presence)
 		r.Post("/api/v1/focus/start", focusHandler.StartFocus)
 		r.Post("/api/v1/focus/stop", focusHandler.StopFocus)
+		r.Post("/api/v1/focus/heartbeat", focusHandler.Heartbeat)
+		r.Post("/api/v1/focus/pause", focusHandler.PauseFocus)
+		r.Post("/api/v1/focus/resume", focusHandler.ResumeFocus)
+		r.Put("/api/v1/focus/broadcast", focusHandler.SetBroadcast)
 		r.Get("/api/v1/focus/active/{squadID}", focusHandler.GetActiveInSquad)
+		r.Get("/api/v1/focus/history", focusHandler.GetMyHistory)
 		r.Get("/api/v1/focus/history/{squadID}", focusHandler.GetFocusHistory)
+		r.Get("/api/v1/focus/stats/me", focusHandler.GetMyStats)
+		r.Get("/api/v1/focus/stats/subjects", focusHandler.GetSubjectBreakdown)
+		r.Get("/api/v1/focus/tags", focusHandler.GetMyTags)
+		r.Put("/api/v1/focus/tags/favourites", focusHandler.SetFavouriteTags)
+		r.Post("/api/v1/focus/sessions", focusHandler.LogSession)
+		r.Post("/api/v1/focus/sessions/{sessionID}/outcome", focusHandler.SetOutcome)
+
+		// Study room routes (scheduled group focus)
+		r.Post("/api/v1/squads/{squadID}/study-rooms", studyRoomHandler.CreateRoom)
+		r.Get("/api/v1/squads/{squadID}/study-rooms", studyRoomHandler.ListRooms)
+		r.Get("/api/v1/study-rooms/{roomID}", studyRoomHandler.GetRoom)
+		r.Put("/api/v1/study-rooms/{roomID}/rsvp", studyRoomHandler.RSVP)
+		r.Post("/api/v1/study-rooms/{roomID}/join", studyRoomHandler.JoinRoom)
+		r.Delete("/api/v1/study-rooms/{roomID}", studyRoomHandler.CancelRoom)
+
+		// Calendar feed routes
+		r.Get("/api/v1/calendar/feed", calendarHandler.GetFeed)
+		r.Post("/api/v1/calendar/feed", calendarHandler.CreateFeed)
+		r.Delete("/api/v1/calendar/feed", calendarHandler.RevokeFeed)
 
 		// Streak routes (The Streak Engine)
 		r.Post("/api/v1/streaks/log", streakHandler.LogActivity)
 		r.Get("/api/v1/streaks/me", streakHandler.GetMyStreak)
+		r.Put("/api/v1/streaks/me/mode", streakHandler.SetStreakMode)
 		r.Get("/api/v1/streaks/{userID}", streakHandler.GetUserStreak)
 		r.Get("/api/v1/streaks/leaderboard", streakHandler.GetLeaderboard)
-		r.Post("/api/v1/streaks/calculate", streakHandler.TriggerRecalculation)
 
 		// Notification routes (The Nudge System)
 		r.Get("/api/v1/notifications", notificationHandler.ListNotifications)
@@ -40,6 +40,51 @@ Constraint: Maximum 15 words. Lowercase only. No emojis. Punchy and motivating.
 Message:
 `, userName, streakDays, riskFactor)
 
+	message, err := c.complete(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	if message == "" {
+		return "keep going!", nil
+	}
+	return message, nil
+}
+
+// GenerateCongratulation writes a short message celebrating a streak
+// milestone or personal record. unit is "days" or "weeks".
+func (c *GroqClient) GenerateCongratulation(ctx context.Context, userName string, streak int, unit string, personalRecord bool) (string, error) {
+	achievement := "streak milestone"
+	if personalRecord {
+		achievement = "new personal best streak"
+	}
+
+	if c.apiKey == "" {
+		return fmt.Sprintf("%s, %d %s in a row! That's a %s. Proud of you!", userName, streak, unit, achievement), nil
+	}
+
+	prompt := fmt.Sprintf(`
+You are a supportive, chill gym buddy.
+User: %s
+Achievement: %s
+Current Streak: %d %s
+Goal: Celebrate the consistency, not perfection.
+Constraint: Maximum 20 words. Lowercase only. No emojis. Warm and genuine.
+Message:
+`, userName, achievement, streak, unit)
+
+	message, err := c.complete(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	if message == "" {
+		return "huge milestone. keep showing up!", nil
+	}
+	return message, nil
+}
+
+// complete sends a single-prompt chat completion and returns the reply
+// ("" if the model returned no choices)
+func (c *GroqClient) complete(ctx context.Context, prompt string) (string, error) {
 	reqBody := map[string]interface{}{
 		"model": "llama3-70b-8192",
 		"messages": []map[string]string{
@@ -85,5 +130,5 @@ Message:
 		return result.Choices[0].Message.Content, nil
 	}
 
-	return "keep going!", nil
+	return "", nil
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -13,8 +14,32 @@ type Config struct {
 	SupabaseJWTSecret string
 	AllowedOrigins    []string
 	Port              string
+	PublicAPIURL      string // Base URL of this API in links handed to other apps
 	NatsURL           string
 	GroqAPIKey        string
+	AdminAPIKey       string
+
+	// Background job scheduler
+	SchedulerEnabled     bool
+	StreakRiskSchedule   string
+	StreakRecalcSchedule string
+
+	// Shortest focus session (minutes) that counts toward the streak
+	FocusStreakMinMinutes int
+	// Streak lengths that trigger a milestone celebration
+	StreakMilestones []int
+
+	// Focus sessions running this long past their plan are auto-stopped
+	FocusAutoStopGraceMinutes int
+	FocusAutoStopSchedule     string
+	// Focus sessions without a heartbeat for this long are closed
+	FocusHeartbeatTimeoutMinutes int
+	// Backfilled focus minutes allowed per local day
+	FocusBackfillDailyCapMinutes int
+
+	// Members who RSVP'd are reminded this long before a study room starts
+	StudyRoomReminderMinutes  int
+	StudyRoomReminderSchedule string
 }
 
 // Load reads configuration from environment variables
@@ -31,8 +56,26 @@ func Load() *Config {
 		SupabaseJWTSecret: getEnvOrPanic("SUPABASE_JWT_SECRET"),
 		AllowedOrigins:    strings.Split(allowedOrigins, ","),
 		Port:              getEnvOrDefault("PORT", "8080"),
+		PublicAPIURL:      getEnvOrDefault("PUBLIC_API_URL", "http://localhost:8080"),
 		NatsURL:           getEnvOrDefault("NATS_URL", "nats://localhost:4222"),
-		GroqAPIKey:        getEnvOrDefault("GROQ_API_KEY", ""), // Optional for local dev/mocking
+		GroqAPIKey:        getEnvOrDefault("GROQ_API_KEY", ""),  // Optional for local dev/mocking
+		AdminAPIKey:       getEnvOrDefault("ADMIN_API_KEY", ""), // Admin routes are disabled when empty
+
+		SchedulerEnabled:     getEnvOrDefault("SCHEDULER_ENABLED", "true") == "true",
+		StreakRiskSchedule:   getEnvOrDefault("STREAK_RISK_SCHEDULE", "*/15 * * * *"),
+		StreakRecalcSchedule: getEnvOrDefault("STREAK_RECALC_SCHEDULE", "5 0 * * *"),
+
+		FocusStreakMinMinutes: getEnvIntOrDefault("FOCUS_STREAK_MIN_MINUTES", 5),
+		StreakMilestones:      getEnvIntsOrDefault("STREAK_MILESTONES", []int{7, 30, 100}),
+
+		FocusAutoStopGraceMinutes: getEnvIntOrDefault("FOCUS_AUTO_STOP_GRACE_MINUTES", 15),
+		FocusAutoStopSchedule:     getEnvOrDefault("FOCUS_AUTO_STOP_SCHEDULE", "*/5 * * * *"),
+
+		FocusHeartbeatTimeoutMinutes: getEnvIntOrDefault("FOCUS_HEARTBEAT_TIMEOUT_MINUTES", 3),
+		FocusBackfillDailyCapMinutes: getEnvIntOrDefault("FOCUS_BACKFILL_DAILY_CAP_MINUTES", 240),
+
+		StudyRoomReminderMinutes:  getEnvIntOrDefault("STUDY_ROOM_REMINDER_MINUTES", 15),
+		StudyRoomReminderSchedule: getEnvOrDefault("STUDY_ROOM_REMINDER_SCHEDULE", "* * * * *"),
 	}
 }
 
@@ -51,3 +94,25 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvIntsOrDefault parses a comma-separated list of positive integers
+func getEnvIntsOrDefault(key string, defaultValue []int) []int {
+	var values []int
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && n > 0 {
+			values = append(values, n)
+		}
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
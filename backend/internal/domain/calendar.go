@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed is the state of a user's calendar feed. The URL itself is
+// only returned when the token is generated.
+type CalendarFeed struct {
+	Enabled       bool       `json:"enabled"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+}
+
+// CalendarFeedURL is a freshly generated feed URL. Older URLs stop working.
+type CalendarFeedURL struct {
+	URL       string    `json:"url"`
+	WebcalURL string    `json:"webcal_url"` // Opens the subscribe dialog of calendar apps
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalendarOwner is the user a feed token belongs to
+type CalendarOwner struct {
+	UserID     uuid.UUID
+	Timezone   string
+	StreakMode StreakMode
+}
+
+// CalendarStudyRoom is a study room in a feed owner's squads
+type CalendarStudyRoom struct {
+	ID              uuid.UUID
+	SquadName       string
+	HostName        string
+	Topic           string
+	StartsAt        time.Time
+	DurationMinutes int
+	Cancelled       bool
+	RSVP            *string // Owner's answer, nil = none
+}
+
+// CalendarFocusSession is one of a feed owner's ended focus sessions
+type CalendarFocusSession struct {
+	ID              uuid.UUID
+	SquadName       *string // nil = solo
+	StartedAt       time.Time
+	EndedAt         time.Time
+	DurationMinutes int // Net of pauses
+	Goal            *string
+}
@@ -6,6 +6,7 @@ import "errors"
 var (
 	// Profile errors
 	ErrProfileNotFound = errors.New("profile not found")
+	ErrInvalidTimezone = errors.New("invalid timezone")
 	
 	// Squad errors
 	ErrSquadNotFound          = errors.New("squad not found")
@@ -18,7 +19,37 @@ var (
 	ErrInvalidInviteCode      = errors.New("invalid invite code")
 	ErrNotSquadMember         = errors.New("not a member of this squad")
 	ErrCannotKickOwner        = errors.New("cannot kick squad owner")
+	ErrInvalidStreakQuorum    = errors.New("streak quorum must be between 0 (every member) and the squad's max members")
+	ErrSquadRoleForbidden     = errors.New("your squad role does not allow this action")
+	ErrInvalidSquadRole       = errors.New("role must be admin or member")
+	ErrCannotChangeOwnerRole  = errors.New("the owner's role cannot be changed")
+	ErrAlreadySquadOwner      = errors.New("already the owner of this squad")
 	
+	// Streak errors
+	ErrInvalidFreezeCount = errors.New("freeze count must be between 1 and 2")
+	ErrInvalidStreakMode  = errors.New("streak mode must be \"daily\" or \"weekly:N\" with N between 1 and 7")
+
+	// Study room errors
+	ErrStudyRoomNotFound  = errors.New("study room not found")
+	ErrInvalidStudyRoom   = errors.New("study room needs a topic of 1-120 characters, a start in the next 30 days and a duration of 5-720 minutes")
+	ErrInvalidRSVP        = errors.New("rsvp status must be going, maybe or declined")
+	ErrNotStudyRoomHost   = errors.New("only the host can perform this action")
+	ErrStudyRoomCancelled = errors.New("study room was cancelled")
+	ErrStudyRoomStarted   = errors.New("study room has already started")
+	ErrStudyRoomEnded     = errors.New("study room has ended")
+	ErrStudyRoomNotOpen   = errors.New("study room is not open yet")
+
+	// Focus backfill errors
+	ErrSessionOverlap     = errors.New("session overlaps another focus session")
+	ErrBackfillCapReached = errors.New("daily backfill limit reached")
+
+	// Calendar errors
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	// Job scheduler errors
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobAlreadyRunning = errors.New("job is already running")
+
 	// Auth errors
 	ErrUnauthorized = errors.New("unauthorized")
 )
@@ -8,49 +8,177 @@ import (
 
 // FocusSession represents a focus/study session
 type FocusSession struct {
-	ID              uuid.UUID  `json:"id"`
-	UserID          uuid.UUID  `json:"user_id"`
-	SquadID         uuid.UUID  `json:"squad_id"`
-	StartedAt       time.Time  `json:"started_at"`
-	EndedAt         *time.Time `json:"ended_at"`
-	DurationMinutes *int       `json:"duration_minutes"`
+	ID                 uuid.UUID       `json:"id"`
+	UserID             uuid.UUID       `json:"user_id"`
+	SquadID            *uuid.UUID      `json:"squad_id"`  // nil = solo
+	Broadcast          bool            `json:"broadcast"` // Solo, shown to all the user's squads
+	StartedAt          time.Time       `json:"started_at"`
+	EndedAt            *time.Time      `json:"ended_at"`
+	DurationMinutes    *int            `json:"duration_minutes"`   // Net of pauses, nil while active
+	PausedAt           *time.Time      `json:"paused_at"`          // Start of the current pause
+	PausedSeconds      int             `json:"paused_seconds"`     // Total of finished pauses
+	Pomodoro           *PomodoroConfig `json:"pomodoro,omitempty"` // nil = plain session
+	CompletedPomodoros int             `json:"completed_pomodoros"`
+	PlannedMinutes     *int            `json:"planned_minutes,omitempty"`
+	Goal               *string         `json:"goal,omitempty"`
+	GoalMet            *bool           `json:"goal_met,omitempty"` // nil until the user answers
+	AutoEnded          bool            `json:"auto_ended"`         // Ended by the sweeper, not the user
+	LastHeartbeatAt    time.Time       `json:"last_heartbeat_at"`
+	StudyRoomID        *uuid.UUID      `json:"study_room_id,omitempty"` // Set when started from a study room
+	Tags               []string        `json:"tags"`                    // Subjects, normalized
+	Note               *string         `json:"note,omitempty"`          // Written at stop, private
+	Manual             bool            `json:"manual"`                  // Backfilled, not tracked live
+}
+
+// PomodoroConfig is a work/break schedule for a focus session. Sets of
+// Cycles work blocks are separated by short breaks and end in a long break.
+type PomodoroConfig struct {
+	WorkMinutes       int `json:"work_minutes"`
+	ShortBreakMinutes int `json:"short_break_minutes"`
+	LongBreakMinutes  int `json:"long_break_minutes"`
+	Cycles            int `json:"cycles"` // Work blocks before the long break
+}
+
+// PomodoroPhase is the part of the cycle a pomodoro session is in
+type PomodoroPhase string
+
+const (
+	PomodoroPhaseWork       PomodoroPhase = "work"
+	PomodoroPhaseShortBreak PomodoroPhase = "short_break"
+	PomodoroPhaseLongBreak  PomodoroPhase = "long_break"
+)
+
+// PomodoroState is where a pomodoro session stands, computed by the server
+type PomodoroState struct {
+	Config             PomodoroConfig `json:"config"`
+	Phase              PomodoroPhase  `json:"phase"`
+	Cycle              int            `json:"cycle"` // 1-based work block within the set
+	CompletedPomodoros int            `json:"completed_pomodoros"`
+	PhaseEndsAt        time.Time      `json:"phase_ends_at"`
+	SecondsLeft        int            `json:"seconds_left"`
 }
 
 // ActiveSession represents an active focus session with user info
 type ActiveSession struct {
-	UserID          uuid.UUID `json:"user_id"`
-	DisplayName     string    `json:"display_name"`
-	AvatarURL       *string   `json:"avatar_url"`
-	StartedAt       time.Time `json:"started_at"`
-	DurationMinutes int       `json:"duration_minutes"`
+	UserID          uuid.UUID      `json:"user_id"`
+	DisplayName     string         `json:"display_name"`
+	AvatarURL       *string        `json:"avatar_url"`
+	StartedAt       time.Time      `json:"started_at"`
+	DurationMinutes int            `json:"duration_minutes"` // Net focused time so far
+	FocusedSeconds  int            `json:"focused_seconds"`
+	Paused          bool           `json:"paused"`
+	PausedAt        *time.Time     `json:"paused_at,omitempty"`
+	Pomodoro        *PomodoroState `json:"pomodoro,omitempty"` // Frozen while paused
+	PlannedMinutes  *int           `json:"planned_minutes,omitempty"`
+	Goal            *string        `json:"goal,omitempty"`
+	Solo            bool           `json:"solo"` // Broadcast solo session, not started in this squad
+	LastHeartbeatAt time.Time      `json:"last_heartbeat_at"`
 }
 
 // FocusHistory represents a completed focus session
 type FocusHistory struct {
-	ID              uuid.UUID  `json:"id"`
-	UserID          uuid.UUID  `json:"user_id"`
-	DisplayName     string     `json:"display_name"`
-	AvatarURL       *string    `json:"avatar_url"`
-	StartedAt       time.Time  `json:"started_at"`
-	EndedAt         *time.Time `json:"ended_at"`
-	DurationMinutes int        `json:"duration_minutes"`
+	ID                 uuid.UUID       `json:"id"`
+	SquadID            *uuid.UUID      `json:"squad_id"` // nil = solo
+	SquadName          *string         `json:"squad_name"`
+	UserID             uuid.UUID       `json:"user_id"`
+	DisplayName        string          `json:"display_name"`
+	AvatarURL          *string         `json:"avatar_url"`
+	StartedAt          time.Time       `json:"started_at"`
+	EndedAt            *time.Time      `json:"ended_at"`
+	DurationMinutes    int             `json:"duration_minutes"` // Net of pauses
+	PausedMinutes      int             `json:"paused_minutes"`
+	Pomodoro           *PomodoroConfig `json:"pomodoro,omitempty"`
+	CompletedPomodoros int             `json:"completed_pomodoros"`
+	PlannedMinutes     *int            `json:"planned_minutes,omitempty"`
+	Goal               *string         `json:"goal,omitempty"`
+	GoalMet            *bool           `json:"goal_met,omitempty"`
+	AutoEnded          bool            `json:"auto_ended"`
+	Tags               []string        `json:"tags"`
+	Note               *string         `json:"note,omitempty"` // Only on the viewer's own sessions
+	Manual             bool            `json:"manual"`         // Backfilled, not tracked live
 }
 
 // StartFocusRequest is the request body for starting a focus session
 type StartFocusRequest struct {
-	SquadID uuid.UUID `json:"squad_id"`
+	SquadID        *uuid.UUID      `json:"squad_id,omitempty"`        // nil = solo
+	Broadcast      bool            `json:"broadcast,omitempty"`       // Solo only: show in all the user's squads
+	Pomodoro       *PomodoroConfig `json:"pomodoro,omitempty"`        // Zero fields use the 25/5/15 x4 defaults
+	PlannedMinutes int             `json:"planned_minutes,omitempty"` // 0 = open-ended, never auto-stopped
+	Goal           string          `json:"goal,omitempty"`
+	Tags           []string        `json:"tags,omitempty"` // Normalized; at most 5
+	StudyRoomID    *uuid.UUID      `json:"-"`              // Set by the study room service only
 }
 
 // StartFocusResponse is the response after starting a focus session
 type StartFocusResponse struct {
-	SessionID uuid.UUID `json:"session_id"`
-	StartedAt time.Time `json:"started_at"`
+	SessionID      uuid.UUID      `json:"session_id"`
+	SquadID        *uuid.UUID     `json:"squad_id"`
+	Broadcast      bool           `json:"broadcast"`
+	StartedAt      time.Time      `json:"started_at"`
+	Pomodoro       *PomodoroState `json:"pomodoro,omitempty"`
+	PlannedMinutes *int           `json:"planned_minutes,omitempty"`
+	Goal           *string        `json:"goal,omitempty"`
+	Tags           []string       `json:"tags"`
+	StudyRoomID    *uuid.UUID     `json:"study_room_id,omitempty"`
+}
+
+// LogSessionRequest is the request body for backfilling a session that
+// happened offline
+type LogSessionRequest struct {
+	SquadID   *uuid.UUID `json:"squad_id,omitempty"` // nil = solo
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   time.Time  `json:"ended_at"`
+	Goal      string     `json:"goal,omitempty"`
+	GoalMet   *bool      `json:"goal_met,omitempty"` // Needs a goal
+	Tags      []string   `json:"tags,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// StopFocusRequest is the optional request body for stopping a focus session
+type StopFocusRequest struct {
+	GoalMet *bool    `json:"goal_met,omitempty"` // Outcome, if the session had a goal
+	Tags    []string `json:"tags,omitempty"`     // Replaces the tags set at start
+	Note    string   `json:"note,omitempty"`     // What was done, at most 1000 characters
 }
 
-// StopFocusResponse is the response after stopping a focus session
+// StopFocusResponse is the response after stopping a focus session.
+// OutcomePending asks the client whether the goal was met; the answer
+// goes to POST /api/v1/focus/sessions/{sessionID}/outcome.
 type StopFocusResponse struct {
+	SessionID          uuid.UUID `json:"session_id"`
+	DurationMinutes    int       `json:"duration_minutes"`
+	CompletedPomodoros int       `json:"completed_pomodoros,omitempty"`
+	Goal               *string   `json:"goal,omitempty"`
+	GoalMet            *bool     `json:"goal_met,omitempty"`
+	OutcomePending     bool      `json:"outcome_pending"`
+	Tags               []string  `json:"tags"`
+	Note               *string   `json:"note,omitempty"`
+}
+
+// BroadcastRequest shows or hides the user's active solo session in their
+// squads
+type BroadcastRequest struct {
+	Broadcast *bool `json:"broadcast"`
+}
+
+// FocusPauseResponse is the response after pausing or resuming a session
+type FocusPauseResponse struct {
+	SessionID     uuid.UUID  `json:"session_id"`
+	Paused        bool       `json:"paused"`
+	PausedAt      *time.Time `json:"paused_at,omitempty"`
+	PausedMinutes int        `json:"paused_minutes"` // Total of finished pauses
+}
+
+// HeartbeatResponse is the response after a focus heartbeat
+type HeartbeatResponse struct {
 	SessionID       uuid.UUID `json:"session_id"`
-	DurationMinutes int       `json:"duration_minutes"`
+	LastHeartbeatAt time.Time `json:"last_heartbeat_at"`
+	TimeoutSeconds  int       `json:"timeout_seconds"` // Session closes if no heartbeat arrives within this
+}
+
+// FocusOutcomeRequest records whether a session's goal was met
+type FocusOutcomeRequest struct {
+	GoalMet *bool `json:"goal_met"`
 }
 
 // ActiveSessionsResponse is the response for active sessions query
@@ -58,7 +186,106 @@ type ActiveSessionsResponse struct {
 	ActiveSessions []ActiveSession `json:"active_sessions"`
 }
 
-// FocusHistoryResponse is the response for focus history query
+// FocusHistoryResponse is one page of focus history, newest first
 type FocusHistoryResponse struct {
-	History []FocusHistory `json:"history"`
+	History    []FocusHistory `json:"history"`
+	NextCursor *string        `json:"next_cursor"` // nil on the last page
+}
+
+// FocusHistoryFilter narrows a focus history query. Nil and zero fields
+// don't filter.
+type FocusHistoryFilter struct {
+	SquadID    *uuid.UUID
+	UserID     *uuid.UUID
+	From       *time.Time // Started at or after
+	To         *time.Time // Started before
+	MinMinutes int        // Net of pauses
+	Tags       []string   // Sessions having all of them
+}
+
+// FocusHistoryCursor is the (started_at, id) of the last session of a page;
+// the next page starts right after it
+type FocusHistoryCursor struct {
+	StartedAt time.Time
+	ID        uuid.UUID
+}
+
+// FocusStatsResponse is a user's focus analytics over the last few days,
+// weeks or months, in their timezone
+type FocusStatsResponse struct {
+	Period      string             `json:"period"` // day, week, month
+	Timezone    string             `json:"timezone"`
+	From        string             `json:"from"` // YYYY-MM-DD, first day of the window
+	To          string             `json:"to"`   // YYYY-MM-DD, last day of the window
+	Totals      FocusTotals        `json:"totals"`
+	Previous    FocusTotals        `json:"previous"` // The same number of periods just before
+	Trend       FocusTrend         `json:"trend"`
+	Buckets     []FocusStatsBucket `json:"buckets"`      // Oldest first, empty periods included
+	Heatmap     [7][24]int         `json:"heatmap"`      // Minutes by weekday (Monday first) and local hour
+	BestHours   []int              `json:"best_hours"`   // Local hours with the most focus, most first
+	BestWeekday *string            `json:"best_weekday"` // e.g. "tuesday", nil without sessions
+	Insight     string             `json:"insight,omitempty"`
+}
+
+// FocusTotals aggregates the ended sessions of a span of time
+type FocusTotals struct {
+	TotalMinutes   int     `json:"total_minutes"`
+	Sessions       int     `json:"sessions"`
+	AverageMinutes float64 `json:"average_minutes"`
+	LongestMinutes int     `json:"longest_minutes"`
+}
+
+// FocusStatsBucket is the totals of one day, week or month
+type FocusStatsBucket struct {
+	Start string `json:"start"` // YYYY-MM-DD, first day of the period
+	FocusTotals
+}
+
+// FocusTrend compares the window with the previous one. Percentages are
+// nil when the previous window had no focus.
+type FocusTrend struct {
+	MinutesChangePercent  *float64 `json:"minutes_change_percent"`
+	SessionsChangePercent *float64 `json:"sessions_change_percent"`
+	Direction             string   `json:"direction"` // up, down, flat
+}
+
+// FocusTagsResponse is what the tag picker offers: the user's favourites,
+// then the tags they used most recently
+type FocusTagsResponse struct {
+	Favourites []string        `json:"favourites"`
+	Recent     []FocusTagUsage `json:"recent"`
+}
+
+// FocusTagUsage is how often and when a user last used a tag
+type FocusTagUsage struct {
+	Tag        string    `json:"tag"`
+	Sessions   int       `json:"sessions"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// SetFavouriteTagsRequest replaces a user's favourite tags, in display order
+type SetFavouriteTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// SubjectBreakdownResponse shows where a user's focus time went between
+// From and To. A session with several tags counts fully toward each, so
+// subject minutes can add up to more than TotalMinutes.
+type SubjectBreakdownResponse struct {
+	From            time.Time        `json:"from"`
+	To              time.Time        `json:"to"`
+	TotalMinutes    int              `json:"total_minutes"`
+	UntaggedMinutes int              `json:"untagged_minutes"`
+	Subjects        []SubjectMinutes `json:"subjects"` // Most minutes first
+}
+
+// SubjectMinutes is the focus time spent on one tag. A session with
+// several tags counts in full for each of them, so SharePercent is taken
+// of the summed tag minutes, not of TotalMinutes: the shares add up to
+// 100 and leave untagged time out.
+type SubjectMinutes struct {
+	Tag          string  `json:"tag"`
+	Sessions     int     `json:"sessions"`
+	Minutes      int     `json:"minutes"`
+	SharePercent float64 `json:"share_percent"` // Of all tags' Minutes, one decimal
 }
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,18 +21,63 @@ type SquadRepository interface {
 	GetDetailByID(ctx context.Context, squadID uuid.UUID) (*SquadDetail, error)
 	IsMember(ctx context.Context, squadID, userID uuid.UUID) (bool, error)
 	Update(ctx context.Context, squadID uuid.UUID, req *UpdateSquadRequest) (*Squad, error)
+	GetStreakLeaderboard(ctx context.Context, limit int) ([]SquadLeaderboardEntry, error)
 	Delete(ctx context.Context, squadID uuid.UUID) error
 	JoinByInviteCode(ctx context.Context, inviteCode string, userID uuid.UUID) (uuid.UUID, error)
 	RemoveMember(ctx context.Context, squadID, userID uuid.UUID) error
 	RegenerateInviteCode(ctx context.Context, squadID uuid.UUID) (string, error)
+	GetMemberRole(ctx context.Context, squadID, userID uuid.UUID) (SquadRole, error)
+	SetMemberRole(ctx context.Context, squadID, userID uuid.UUID, role SquadRole) error
+	TransferOwnership(ctx context.Context, squadID, fromUserID, toUserID uuid.UUID) error
+	LeaveSquad(ctx context.Context, squadID, userID uuid.UUID) (*SquadDeparture, error)
 }
 
 type FocusRepository interface {
 	IsMemberOfSquad(ctx context.Context, userID, squadID uuid.UUID) (bool, error)
-	StartSession(ctx context.Context, userID, squadID uuid.UUID) (*FocusSession, error)
+	StartSession(ctx context.Context, userID uuid.UUID, req *StartFocusRequest) (*FocusSession, error)
 	EndSession(ctx context.Context, userID uuid.UUID) (*FocusSession, error)
-	GetActiveBySquad(ctx context.Context, squadID uuid.UUID) ([]ActiveSession, error)
-	GetHistoryBySquad(ctx context.Context, squadID uuid.UUID, limit int) ([]FocusHistory, error)
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*FocusSession, error)
+	Heartbeat(ctx context.Context, userID uuid.UUID) (*FocusSession, error)
+	GetActiveSession(ctx context.Context, userID uuid.UUID) (*FocusSession, error)
+	PauseSession(ctx context.Context, sessionID uuid.UUID) (*FocusSession, error)
+	ResumeSession(ctx context.Context, sessionID uuid.UUID) (*FocusSession, error)
+	EndAbandoned(ctx context.Context, timeout time.Duration) ([]FocusSession, error)
+	AutoEndOverdue(ctx context.Context, grace time.Duration) ([]FocusSession, error)
+	SetGoalMet(ctx context.Context, sessionID uuid.UUID, goalMet bool) error
+	SetCompletedPomodoros(ctx context.Context, sessionID uuid.UUID, completed int) error
+	GetActiveBySquad(ctx context.Context, squadID uuid.UUID, timeout time.Duration) ([]ActiveSession, error)
+	ListHistory(ctx context.Context, filter FocusHistoryFilter, after *FocusHistoryCursor, limit int) ([]FocusHistory, error)
+	GetEndedByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]FocusSession, error)
+	GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error)
+	CreateManualSession(ctx context.Context, userID uuid.UUID, req *LogSessionRequest, capFrom, capTo time.Time, dailyCapMinutes int) (*FocusSession, error)
+	SetBroadcast(ctx context.Context, userID uuid.UUID, broadcast bool) (*FocusSession, error)
+	GetUserSquadIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	SetTags(ctx context.Context, sessionID uuid.UUID, tags []string) error
+	SetNote(ctx context.Context, sessionID uuid.UUID, note string) error
+	GetFavouriteTags(ctx context.Context, userID uuid.UUID) ([]string, error)
+	SetFavouriteTags(ctx context.Context, userID uuid.UUID, tags []string) error
+	GetRecentTags(ctx context.Context, userID uuid.UUID, limit int) ([]FocusTagUsage, error)
+	GetSubjectMinutes(ctx context.Context, userID uuid.UUID, from, to time.Time) (subjects []SubjectMinutes, untagged, total int, err error)
+}
+
+type StudyRoomRepository interface {
+	Create(ctx context.Context, squadID, hostID uuid.UUID, req *CreateStudyRoomRequest) (uuid.UUID, error)
+	GetByID(ctx context.Context, roomID, userID uuid.UUID) (*StudyRoom, error)
+	ListBySquad(ctx context.Context, squadID, userID uuid.UUID, endingAfter time.Time) ([]StudyRoom, error)
+	GetMembers(ctx context.Context, roomID uuid.UUID) ([]StudyRoomMember, error)
+	SetRSVP(ctx context.Context, roomID, userID uuid.UUID, status string) error
+	Cancel(ctx context.Context, roomID uuid.UUID) error
+	ClaimDueReminders(ctx context.Context, lead time.Duration) ([]StudyRoomReminder, error)
+	ReleaseReminder(ctx context.Context, roomID uuid.UUID) error
+}
+
+type CalendarRepository interface {
+	SetFeedToken(ctx context.Context, userID uuid.UUID, tokenHash string) (time.Time, error)
+	GetFeed(ctx context.Context, userID uuid.UUID) (*CalendarFeed, error)
+	DeleteFeed(ctx context.Context, userID uuid.UUID) error
+	GetOwnerByToken(ctx context.Context, tokenHash string) (*CalendarOwner, error)
+	GetStudyRooms(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]CalendarStudyRoom, error)
+	GetFocusSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]CalendarFocusSession, error)
 }
 
 // Service Interfaces
@@ -49,31 +95,107 @@ type SquadService interface {
 	UpdateSquad(ctx context.Context, squadID, userID uuid.UUID, req *UpdateSquadRequest) (*Squad, error)
 	DeleteSquad(ctx context.Context, squadID, userID uuid.UUID) error
 	JoinSquad(ctx context.Context, userID uuid.UUID, inviteCode string) (*Squad, error)
+	GetSquadLeaderboard(ctx context.Context, limit int) ([]SquadLeaderboardEntry, error)
 	RemoveMember(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID) error
 	RegenerateInviteCode(ctx context.Context, squadID, userID uuid.UUID) (string, error)
+	UpdateMemberRole(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID, role SquadRole) error
+	TransferOwnership(ctx context.Context, squadID, callerUserID, newOwnerID uuid.UUID) (*Squad, error)
 }
 
 type FocusService interface {
-	StartFocus(ctx context.Context, userID uuid.UUID, squadID uuid.UUID) (*StartFocusResponse, error)
-	StopFocus(ctx context.Context, userID uuid.UUID) (*StopFocusResponse, error)
+	StartFocus(ctx context.Context, userID uuid.UUID, req *StartFocusRequest) (*StartFocusResponse, error)
+	StopFocus(ctx context.Context, userID uuid.UUID, req *StopFocusRequest) (*StopFocusResponse, error)
+	SetOutcome(ctx context.Context, userID, sessionID uuid.UUID, goalMet bool) (*FocusSession, error)
+	Heartbeat(ctx context.Context, userID uuid.UUID) (*HeartbeatResponse, error)
+	PauseFocus(ctx context.Context, userID uuid.UUID) (*FocusPauseResponse, error)
+	ResumeFocus(ctx context.Context, userID uuid.UUID) (*FocusPauseResponse, error)
+	SetBroadcast(ctx context.Context, userID uuid.UUID, broadcast bool) (*FocusSession, error)
 	GetActiveInSquad(ctx context.Context, userID, squadID uuid.UUID) (*ActiveSessionsResponse, error)
-	GetFocusHistory(ctx context.Context, userID, squadID uuid.UUID) (*FocusHistoryResponse, error)
+	GetFocusHistory(ctx context.Context, userID, squadID uuid.UUID, filter FocusHistoryFilter, cursor string, limit int) (*FocusHistoryResponse, error)
+	GetMyHistory(ctx context.Context, userID uuid.UUID, filter FocusHistoryFilter, cursor string, limit int) (*FocusHistoryResponse, error)
+	GetMyStats(ctx context.Context, userID uuid.UUID, period string, count int) (*FocusStatsResponse, error)
+	LogSession(ctx context.Context, userID uuid.UUID, req *LogSessionRequest) (*FocusSession, error)
+	GetMyTags(ctx context.Context, userID uuid.UUID) (*FocusTagsResponse, error)
+	SetFavouriteTags(ctx context.Context, userID uuid.UUID, req *SetFavouriteTagsRequest) (*FocusTagsResponse, error)
+	GetSubjectBreakdown(ctx context.Context, userID uuid.UUID, from, to *time.Time) (*SubjectBreakdownResponse, error)
+}
+
+type StudyRoomService interface {
+	CreateRoom(ctx context.Context, userID, squadID uuid.UUID, req *CreateStudyRoomRequest) (*StudyRoom, error)
+	ListRooms(ctx context.Context, userID, squadID uuid.UUID) ([]StudyRoom, error)
+	GetRoom(ctx context.Context, userID, roomID uuid.UUID) (*StudyRoomDetail, error)
+	RSVP(ctx context.Context, userID, roomID uuid.UUID, status string) (*StudyRoom, error)
+	JoinRoom(ctx context.Context, userID, roomID uuid.UUID) (*JoinStudyRoomResponse, error)
+	CancelRoom(ctx context.Context, userID, roomID uuid.UUID) error
+}
+
+type CalendarService interface {
+	GetFeed(ctx context.Context, userID uuid.UUID) (*CalendarFeed, error)
+	CreateFeed(ctx context.Context, userID uuid.UUID) (*CalendarFeedURL, error)
+	RevokeFeed(ctx context.Context, userID uuid.UUID) error
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+}
+
+type SquadStreamService interface {
+	Subscribe(ctx context.Context, userID, squadID uuid.UUID, lastEventID string) (<-chan SquadStreamEvent, error)
+	SubscribeUser(ctx context.Context, userID uuid.UUID, lastEventID string) (<-chan SquadStreamEvent, error)
 }
 
 type StreakRepository interface {
-	LogActivity(ctx context.Context, userID string, activityType ActivityType, metadata map[string]interface{}) (*LogActivityResponse, error)
-	GetUserStreak(ctx context.Context, userID string, includeHistory bool, historyDays int) (*StreakData, error)
-	GetActivityHistory(ctx context.Context, userID string, days int) ([]ActivityDay, error)
-	GetLeaderboard(ctx context.Context, limit int) ([]LeaderboardEntry, error)
-	RecalculateAllStreaks(ctx context.Context) error
+	LogActivity(ctx context.Context, userID string, activityType ActivityType, metadata map[string]interface{}, activityDate *time.Time) (*LogActivityResponse, error)
+	GetStreakInputs(ctx context.Context, userID string) (*StreakInputs, error)
+	ListStreakInputs(ctx context.Context, afterID string, limit int) ([]StreakInputs, error)
+	GetDailyActivity(ctx context.Context, userID string, from time.Time) ([]DailyActivity, error)
+	SaveStreaks(ctx context.Context, updates []StreakUpdate) error
+	GetLeaderboard(ctx context.Context, limit int, weekly bool) ([]LeaderboardEntry, error)
+	SetStreakMode(ctx context.Context, userID string, mode StreakMode) error
+	GetAtRiskUsers(ctx context.Context, within time.Duration) ([]AtRiskUser, error)
+	RecordRiskAlert(ctx context.Context, userID string, localDate time.Time, threshold string) (bool, error)
+	ReleaseRiskAlert(ctx context.Context, userID string, localDate time.Time, threshold string) error
+	GetFreezeLedger(ctx context.Context, userID string, limit int) ([]StreakFreeze, error)
+	GrantFreezeTokens(ctx context.Context, userID string, count int, note string) (int, error)
+	UseFreezeTokens(ctx context.Context, userID string, dates []time.Time) (int, error)
+	EarnFreezeToken(ctx context.Context, userID string, date time.Time, note string) (bool, error)
+	RecordAchievement(ctx context.Context, userID string, achievement *StreakAchievement) (bool, error)
+	GetAchievements(ctx context.Context, userID string, limit int) ([]StreakAchievement, error)
+	GetSquadmateIDs(ctx context.Context, userID string) ([]uuid.UUID, error)
+	GetUserSquadIDs(ctx context.Context, userID string) ([]uuid.UUID, error)
+	GetSquadStreakInputs(ctx context.Context, squadID uuid.UUID) (*SquadStreakInputs, error)
+	ListSquadStreakInputs(ctx context.Context, afterID uuid.UUID, limit int) ([]SquadStreakInputs, error)
+	ListActiveSquadStreaks(ctx context.Context) ([]SquadStreakInputs, error)
+	SaveSquadStreaks(ctx context.Context, updates []SquadStreakUpdate) error
+	RecordSquadRiskAlert(ctx context.Context, squadID, userID uuid.UUID, localDate time.Time, threshold string) (bool, error)
+	ReleaseSquadRiskAlert(ctx context.Context, squadID, userID uuid.UUID, localDate time.Time, threshold string) error
 }
 
 type StreakService interface {
 	LogManualCheckin(ctx context.Context, userID string) (*LogActivityResponse, error)
-	LogFocusSession(ctx context.Context, userID string, sessionID string, duration int) (*LogActivityResponse, error)
+	LogFocusSession(ctx context.Context, userID string, sessionID string, duration int, activityDate *time.Time) (*LogActivityResponse, error)
 	GetMyStreak(ctx context.Context, userID string) (*StreakData, error)
 	GetUserPublicStreak(ctx context.Context, userID string) (*StreakData, error)
-	GetLeaderboard(ctx context.Context, limit int) ([]LeaderboardEntry, error)
-	TriggerRecalculation(ctx context.Context) error
+	GetLeaderboard(ctx context.Context, limit int, mode string) ([]LeaderboardEntry, error)
+	SetStreakMode(ctx context.Context, userID string, req *SetStreakModeRequest) (*StreakData, error)
+	RecalculateStreaks(ctx context.Context) (*RecalculationReport, error)
+	GrantFreezeTokens(ctx context.Context, userID string, req *GrantFreezeRequest) (*GrantFreezeResponse, error)
 	ValidateActivityType(activityType ActivityType) error
 }
+
+type JobRepository interface {
+	EnsureJob(ctx context.Context, name, schedule string) error
+	IsPaused(ctx context.Context, name string) (bool, error)
+	SetPaused(ctx context.Context, name string, paused bool) error
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+	HasRunSince(ctx context.Context, name string, since time.Time) (bool, error)
+	StartRun(ctx context.Context, name, trigger, instance string) (*JobRun, error)
+	FinishRun(ctx context.Context, runID uuid.UUID, runErr error) error
+	GetLastRun(ctx context.Context, name string) (*JobRun, error)
+	ListRuns(ctx context.Context, name string, limit int) ([]JobRun, error)
+}
+
+type SchedulerService interface {
+	ListJobs(ctx context.Context) ([]JobInfo, error)
+	GetJobRuns(ctx context.Context, name string, limit int) ([]JobRun, error)
+	PauseJob(ctx context.Context, name string) error
+	ResumeJob(ctx context.Context, name string) error
+	TriggerJob(ctx context.Context, name string) (*JobRun, error)
+}
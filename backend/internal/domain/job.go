@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobRunStatus represents the outcome of a scheduled job run
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobFunc is the unit of work executed by the scheduler
+type JobFunc func(ctx context.Context) error
+
+// JobInfo describes a registered background job and its current state
+type JobInfo struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Paused   bool       `json:"paused"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	LastRun  *JobRun    `json:"last_run,omitempty"`
+}
+
+// JobRun is a single execution of a job, persisted in job_runs
+type JobRun struct {
+	ID         uuid.UUID    `json:"id"`
+	JobName    string       `json:"job_name"`
+	Status     JobRunStatus `json:"status"`
+	Trigger    string       `json:"trigger"` // schedule, manual
+	Instance   string       `json:"instance"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	Error      *string      `json:"error,omitempty"`
+}
+
+// JobRunsResponse is the response for a job's run history
+type JobRunsResponse struct {
+	Runs []JobRun `json:"runs"`
+}
+
+// JobsResponse is the response for listing registered jobs
+type JobsResponse struct {
+	Jobs []JobInfo `json:"jobs"`
+}
@@ -11,7 +11,7 @@ import (
 type Notification struct {
 	ID        uuid.UUID       `json:"id"`
 	UserID    uuid.UUID       `json:"user_id"`
-	Type      string          `json:"type"` // nudge, streak_alert, squad_invite
+	Type      string          `json:"type"` // nudge, streak_alert, streak_broken, streak_milestone, squad_invite, squad_streak_alert, study_room_reminder, squad_owner_changed
 	Title     string          `json:"title"`
 	Message   string          `json:"message"`
 	IsRead    bool            `json:"is_read"`
@@ -25,7 +25,11 @@ type NudgeEvent struct {
 	UserName     string    `json:"user_name"`
 	StreakDays   int       `json:"streak_days"`
 	LastActivity time.Time `json:"last_activity"`
-	RiskFactor   string    `json:"risk_factor"` // e.g., "inactive_24h", "broken_streak"
+	RiskFactor   string    `json:"risk_factor"` // e.g., "approaching_deadline", "weekly_target", "broken_streak"
+	StreakMode   string    `json:"streak_mode,omitempty"`
+	Threshold    string    `json:"threshold,omitempty"`
+	Deadline     time.Time `json:"deadline,omitempty"`
+	MinutesLeft  int       `json:"minutes_left,omitempty"`
 }
 
 // NotificationResponse is the DTO for list responses
@@ -14,6 +14,7 @@ type Profile struct {
 	AvatarURL        *string    `json:"avatar_url"`
 	IsEduVerified    bool       `json:"is_edu_verified"`
 	Timezone         string     `json:"timezone"`
+	StreakMode       StreakMode `json:"streak_mode"`
 	ConsistencyScore int        `json:"consistency_score"`
 	CurrentStreak    int        `json:"current_streak"`
 	LongestStreak    int        `json:"longest_streak"`
@@ -27,16 +27,29 @@ type SquadDetail struct {
 	OwnerID     uuid.UUID     `json:"owner_id"`
 	MaxMembers  int           `json:"max_members"`
 	CreatedAt   time.Time     `json:"created_at"`
+	Streak      SquadStreak   `json:"streak"`
 	Members     []SquadMember `json:"members"`
 }
 
+// SquadStreak is a squad's shared streak: days on which every member (or
+// the configured quorum) logged activity
+type SquadStreak struct {
+	CurrentStreak   int        `json:"current_streak"`
+	LongestStreak   int        `json:"longest_streak"`
+	LastStreakDate  *time.Time `json:"last_streak_date,omitempty"`
+	Quorum          *int       `json:"quorum"`           // null = every member
+	MembersRequired int        `json:"members_required"` // Active members needed per day
+	ActiveToday     int        `json:"active_today"`     // Members active on their current local day
+}
+
 // SquadMember represents a member in a squad
 type SquadMember struct {
 	UserID      uuid.UUID  `json:"user_id"`
 	DisplayName string     `json:"display_name"`
 	AvatarURL   *string    `json:"avatar_url"`
-	Role        string     `json:"role"`
+	Role        SquadRole  `json:"role"`
 	JoinedAt    time.Time  `json:"joined_at"`
+	ActiveToday bool       `json:"active_today"` // Logged activity on their local today
 }
 
 // CreateSquadRequest is the request body for creating a squad
@@ -47,8 +60,55 @@ type CreateSquadRequest struct {
 
 // UpdateSquadRequest is the request body for updating a squad
 type UpdateSquadRequest struct {
-	Name        *string `json:"name,omitempty"`
-	Description *string `json:"description,omitempty"`
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	StreakQuorum *int    `json:"streak_quorum,omitempty"` // 0 = every member
+}
+
+// SquadLeaderboardEntry is a squad's position in the squad streak leaderboard
+type SquadLeaderboardEntry struct {
+	SquadID       uuid.UUID `json:"squad_id"`
+	Name          string    `json:"name"`
+	MemberCount   int       `json:"member_count"`
+	CurrentStreak int       `json:"current_streak"`
+	LongestStreak int       `json:"longest_streak"`
+	Rank          int       `json:"rank"`
+}
+
+// SquadStreakInputs is the raw data needed to compute one squad's streak
+type SquadStreakInputs struct {
+	SquadID       uuid.UUID
+	Name          string
+	Quorum        *int
+	CurrentStreak int // Stored value from the last calculation
+	LongestStreak int
+	Members       []SquadMemberActivity
+}
+
+// SquadMemberActivity is one member's activity, for squad streak evaluation
+type SquadMemberActivity struct {
+	UserID      uuid.UUID
+	DisplayName string
+	Timezone    string
+	JoinedAt    time.Time
+	ActiveDates []time.Time // Distinct activity days (member timezone)
+	ActiveToday bool
+}
+
+// SquadStreakUpdate is a computed squad streak to persist
+type SquadStreakUpdate struct {
+	SquadID        uuid.UUID
+	CurrentStreak  int
+	LongestStreak  int
+	LastStreakDate *time.Time
+}
+
+// EffectiveQuorum returns how many active members a squad needs per day
+func EffectiveQuorum(quorum *int, members int) int {
+	if quorum != nil && *quorum > 0 && *quorum < members {
+		return *quorum
+	}
+	return members
 }
 
 // JoinSquadRequest is the request body for joining a squad
@@ -69,3 +129,104 @@ func (r *CreateSquadRequest) Validate() error {
 	}
 	return nil
 }
+
+// SquadRole is a member's role in a squad
+type SquadRole string
+
+// Squad roles, most privileged first
+const (
+	SquadRoleOwner  SquadRole = "owner"
+	SquadRoleAdmin  SquadRole = "admin" // Co-lead, manages the squad alongside the owner
+	SquadRoleMember SquadRole = "member"
+)
+
+// Valid reports whether r is a known role
+func (r SquadRole) Valid() bool {
+	switch r {
+	case SquadRoleOwner, SquadRoleAdmin, SquadRoleMember:
+		return true
+	}
+	return false
+}
+
+// The permission matrix. Services ask the caller's role instead of
+// comparing against the squad owner; the empty role (not a member) can do
+// nothing.
+//
+//	                      owner  admin  member
+//	edit squad              x      x
+//	regenerate code         x      x
+//	kick members            x      x
+//	kick admins             x
+//	schedule study rooms    x      x      x
+//	cancel others' rooms    x      x
+//	change roles            x
+//	transfer ownership      x
+//	delete squad            x
+
+// CanEditSquad reports whether the role may change the squad's details
+func (r SquadRole) CanEditSquad() bool {
+	return r == SquadRoleOwner || r == SquadRoleAdmin
+}
+
+// CanRegenerateCode reports whether the role may replace the invite code
+func (r SquadRole) CanRegenerateCode() bool {
+	return r == SquadRoleOwner || r == SquadRoleAdmin
+}
+
+// CanKick reports whether the role may remove a member with the target
+// role. Nobody can kick the owner.
+func (r SquadRole) CanKick(target SquadRole) bool {
+	switch r {
+	case SquadRoleOwner:
+		return target == SquadRoleAdmin || target == SquadRoleMember
+	case SquadRoleAdmin:
+		return target == SquadRoleMember
+	}
+	return false
+}
+
+// CanSchedule reports whether the role may schedule study rooms
+func (r SquadRole) CanSchedule() bool {
+	return r.Valid()
+}
+
+// CanCancelRooms reports whether the role may cancel study rooms hosted by
+// someone else
+func (r SquadRole) CanCancelRooms() bool {
+	return r == SquadRoleOwner || r == SquadRoleAdmin
+}
+
+// CanManageRoles reports whether the role may promote and demote members
+func (r SquadRole) CanManageRoles() bool {
+	return r == SquadRoleOwner
+}
+
+// CanTransferOwnership reports whether the role may hand the squad over to
+// another member
+func (r SquadRole) CanTransferOwnership() bool {
+	return r == SquadRoleOwner
+}
+
+// CanDeleteSquad reports whether the role may delete the squad
+func (r SquadRole) CanDeleteSquad() bool {
+	return r == SquadRoleOwner
+}
+
+// UpdateMemberRoleRequest is the request body for promoting or demoting a
+// member
+type UpdateMemberRoleRequest struct {
+	Role SquadRole `json:"role"` // admin or member
+}
+
+// TransferOwnershipRequest is the request body for handing a squad over
+type TransferOwnershipRequest struct {
+	UserID uuid.UUID `json:"user_id"` // Must be a member; the previous owner becomes an admin
+}
+
+// SquadDeparture is what happened to a squad when a member left
+type SquadDeparture struct {
+	PreviousOwnerID uuid.UUID
+	NewOwnerID      *uuid.UUID // Set if the owner left and someone took over
+	Archived        bool       // The last member left
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -32,31 +35,186 @@ func (a ActivityType) IsValid() bool {
 	return false
 }
 
+// StreakMode is how a user's streak is counted: "daily" or "weekly:N"
+// (consecutive ISO weeks with at least N active days)
+type StreakMode string
+
+const StreakModeDaily StreakMode = "daily"
+
+// Streak units reported alongside streak counts
+const (
+	StreakUnitDays  = "days"
+	StreakUnitWeeks = "weeks"
+)
+
+// ParseStreakMode validates a streak mode string
+func ParseStreakMode(s string) (StreakMode, error) {
+	if s == string(StreakModeDaily) {
+		return StreakModeDaily, nil
+	}
+	target, ok := strings.CutPrefix(s, "weekly:")
+	if !ok {
+		return "", ErrInvalidStreakMode
+	}
+	n, err := strconv.Atoi(target)
+	if err != nil || n < 1 || n > 7 {
+		return "", ErrInvalidStreakMode
+	}
+	return WeeklyStreakMode(n), nil
+}
+
+// WeeklyStreakMode returns the weekly mode with a target of n days
+func WeeklyStreakMode(n int) StreakMode {
+	return StreakMode(fmt.Sprintf("weekly:%d", n))
+}
+
+// WeeklyTarget returns N for weekly:N modes, 0 for daily (or unknown) modes
+func (m StreakMode) WeeklyTarget() int {
+	target, ok := strings.CutPrefix(string(m), "weekly:")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(target)
+	if err != nil || n < 1 || n > 7 {
+		return 0
+	}
+	return n
+}
+
+// Unit returns the unit streak counts are measured in for this mode
+func (m StreakMode) Unit() string {
+	if m.WeeklyTarget() > 0 {
+		return StreakUnitWeeks
+	}
+	return StreakUnitDays
+}
+
 // StreakData represents comprehensive streak information for a user
 type StreakData struct {
-	UserID           string             `json:"user_id"`
-	CurrentStreak    int                `json:"current_streak"`
-	LongestStreak    int                `json:"longest_streak"`
-	LastActiveDate   *time.Time         `json:"last_active_date,omitempty"`
-	ConsistencyScore int                `json:"consistency_score"`
-	TotalActiveDays  int                `json:"total_active_days"`
-	StreakHistory    []ActivityDay      `json:"streak_history,omitempty"`
+	UserID           string              `json:"user_id"`
+	StreakMode       StreakMode          `json:"streak_mode"`
+	StreakUnit       string              `json:"streak_unit"` // days, weeks
+	WeeklyTarget     int                 `json:"weekly_target,omitempty"`
+	WeekActiveDays   *int                `json:"week_active_days,omitempty"` // Progress toward this week's target
+	CurrentStreak    int                 `json:"current_streak"`
+	LongestStreak    int                 `json:"longest_streak"`
+	LastActiveDate   *time.Time          `json:"last_active_date,omitempty"`
+	ConsistencyScore int                 `json:"consistency_score"`
+	TotalActiveDays  int                 `json:"total_active_days"`
+	FreezeTokens     int                 `json:"freeze_tokens"`
+	FrozenDays       int                 `json:"frozen_days"`
+	FreezeLedger     []StreakFreeze      `json:"freeze_ledger,omitempty"`
+	Achievements     []StreakAchievement `json:"achievements,omitempty"` // Most recent first
+	StreakHistory    []ActivityDay       `json:"streak_history,omitempty"`
 }
 
+// DayStatus describes how a day counts toward the streak
+type DayStatus string
+
+const (
+	DayStatusActive DayStatus = "active"
+	DayStatusFrozen DayStatus = "frozen" // Missed, but covered by a freeze token
+	DayStatusMissed DayStatus = "missed"
+)
+
 // ActivityDay represents a single day's activity status
 type ActivityDay struct {
-	Date   time.Time `json:"date"`
-	Active bool      `json:"active"`
+	Date          time.Time      `json:"date"`
+	Active        bool           `json:"active"`
+	Frozen        bool           `json:"frozen"`
+	Status        DayStatus      `json:"status"`
+	ActivityCount int            `json:"activity_count"`
+	FocusMinutes  int            `json:"focus_minutes"`
+	ActivityTypes []ActivityType `json:"activity_types,omitempty"`
+}
+
+// DailyActivity aggregates every activity a user logged on one local day
+type DailyActivity struct {
+	Date          time.Time
+	ActivityCount int
+	FocusMinutes  int
+	ActivityTypes []ActivityType
+}
+
+// AchievementKind is the kind of a streak achievement
+type AchievementKind string
+
+const (
+	AchievementMilestone      AchievementKind = "milestone"       // Streak reached a configured length
+	AchievementPersonalRecord AchievementKind = "personal_record" // Streak passed the longest streak
+)
+
+// StreakAchievement is a milestone or personal record reached by a streak.
+// Each is celebrated once per streak (identified by the day it started).
+type StreakAchievement struct {
+	Kind            AchievementKind `json:"kind"`
+	StreakMode      StreakMode      `json:"streak_mode"`
+	StreakUnit      string          `json:"streak_unit"`               // days, weeks
+	Value           int             `json:"value"`                     // Milestone, or the new record
+	PreviousRecord  int             `json:"previous_record,omitempty"` // Record that was beaten
+	StreakStartedOn time.Time       `json:"streak_started_on"`
+	AchievedAt      time.Time       `json:"achieved_at"`
+}
+
+// FreezeEvent is the kind of a freeze ledger entry
+type FreezeEvent string
+
+const (
+	FreezeEventEarned  FreezeEvent = "earned"
+	FreezeEventGranted FreezeEvent = "granted"
+	FreezeEventUsed    FreezeEvent = "used"
+)
+
+// MaxFreezeTokens is the most freeze tokens a user can hold
+const MaxFreezeTokens = 2
+
+// StreakFreeze is an entry in the freeze token ledger
+type StreakFreeze struct {
+	ID         string      `json:"id"`
+	Event      FreezeEvent `json:"event"`
+	FreezeDate time.Time   `json:"freeze_date"` // Day the event applies to (user timezone)
+	Count      int         `json:"count"`       // Tokens added or spent; only grants can exceed 1
+	Note       *string     `json:"note,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// GrantFreezeRequest is the admin request body for granting freeze tokens
+type GrantFreezeRequest struct {
+	Count int    `json:"count"`
+	Note  string `json:"note,omitempty"`
+}
+
+// GrantFreezeResponse returns the user's balance after a grant
+type GrantFreezeResponse struct {
+	UserID       string `json:"user_id"`
+	FreezeTokens int    `json:"freeze_tokens"`
+}
+
+// RecalculationReport summarizes a streak recalculation run
+type RecalculationReport struct {
+	UsersProcessed  int       `json:"users_processed"`
+	StreaksBroken   int       `json:"streaks_broken"` // Streaks that dropped to zero
+	NewRecords      int       `json:"new_records"`    // Users who beat their longest streak
+	SquadsProcessed int       `json:"squads_processed"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationMs      int64     `json:"duration_ms"`
 }
 
 // LeaderboardEntry represents a user's position in the streak leaderboard
 type LeaderboardEntry struct {
-	UserID           string  `json:"user_id"`
-	DisplayName      string  `json:"display_name"`
-	AvatarURL        *string `json:"avatar_url,omitempty"`
-	CurrentStreak    int     `json:"current_streak"`
-	ConsistencyScore int     `json:"consistency_score"`
-	Rank             int     `json:"rank"`
+	UserID           string     `json:"user_id"`
+	DisplayName      string     `json:"display_name"`
+	AvatarURL        *string    `json:"avatar_url,omitempty"`
+	StreakMode       StreakMode `json:"streak_mode"`
+	StreakUnit       string     `json:"streak_unit"`
+	CurrentStreak    int        `json:"current_streak"`
+	ConsistencyScore int        `json:"consistency_score"`
+	Rank             int        `json:"rank"`
+}
+
+// SetStreakModeRequest is the request body for switching streak modes
+type SetStreakModeRequest struct {
+	Mode string `json:"mode"`
 }
 
 // LogActivityRequest represents the request to log a daily activity
@@ -67,16 +225,53 @@ type LogActivityRequest struct {
 
 // LogActivityResponse represents the response after logging activity
 type LogActivityResponse struct {
-	Success        bool      `json:"success"`
-	ActivityID     string    `json:"activity_id"`
-	ActivityDate   time.Time `json:"activity_date"`
-	CurrentStreak  int       `json:"current_streak"`
-	IsNew          bool      `json:"is_new"` // Whether this was a new log or duplicate
-}
-
-// StreakCalculationResult represents the result from calculate_streak() SQL function
-type StreakCalculationResult struct {
-	CurrentStreak  int        `db:"current_streak"`
-	LongestStreak  int        `db:"longest_streak"`
-	LastActiveDate *time.Time `db:"last_active_date"`
+	Success       bool                `json:"success"`
+	ActivityID    string              `json:"activity_id"`
+	ActivityDate  time.Time           `json:"activity_date"`
+	CurrentStreak int                 `json:"current_streak"`
+	IsNew         bool                `json:"is_new"`                 // Whether this was the first activity of the day
+	Duplicate     bool                `json:"duplicate,omitempty"`    // Focus session was already logged
+	Achievements  []StreakAchievement `json:"achievements,omitempty"` // Reached by this activity
+}
+
+// StreakInputs is the raw data the streak engine needs for one user
+type StreakInputs struct {
+	UserID        string
+	DisplayName   string
+	Timezone      string
+	StreakMode    StreakMode
+	JoinedAt      time.Time
+	CurrentStreak int // Stored value from the last calculation
+	LongestStreak int // Stored longest for the current mode
+	FreezeTokens  int
+	ActiveDates   []time.Time       // Distinct activity days (user timezone)
+	FrozenDates   []time.Time       // Days covered by a freeze token
+	FocusMinutes  map[time.Time]int // Focus minutes per active day
+}
+
+// StreakUpdate is a computed streak to persist on the profile
+type StreakUpdate struct {
+	UserID           string
+	StreakMode       StreakMode // Mode the streak was computed in
+	CurrentStreak    int
+	LongestStreak    int
+	ConsistencyScore int
+}
+
+// AtRiskUser represents a user whose streak is at risk
+type AtRiskUser struct {
+	UserID           string
+	DisplayName      string
+	CurrentStreak    int
+	LastActivityDate time.Time
+	Timezone         string
+	StreakMode       StreakMode
+	WeekActiveDays   int // Covered days so far in the local ISO week
+}
+
+// RiskThreshold is a graded warning fired a fixed time before the user's
+// local midnight (the streak deadline)
+type RiskThreshold struct {
+	Name   string        // e.g. "6h", "2h"
+	Before time.Duration // Time left until local midnight
 }
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SquadStreamEvent is one Server-Sent Event of a squad's real-time stream.
+// ID is the event's position in the stream, echoed back by clients in
+// Last-Event-ID to resume after a reconnect.
+type SquadStreamEvent struct {
+	ID    string          `json:"id"`
+	Type  string          `json:"type"` // focus.started, member.joined, notification, streak.updated, ...
+	Data  json.RawMessage `json:"data"`
+	Final bool            `json:"-"` // The subscriber left the squad; close the stream after this
+}
+
+// StreamTicket opens event streams from clients that can't send an
+// Authorization header (a browser EventSource), as ?ticket=
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudyRoomStatus is where a study room stands, derived from its schedule
+type StudyRoomStatus string
+
+const (
+	StudyRoomScheduled StudyRoomStatus = "scheduled"
+	StudyRoomLive      StudyRoomStatus = "live"
+	StudyRoomEnded     StudyRoomStatus = "ended"
+	StudyRoomCancelled StudyRoomStatus = "cancelled"
+)
+
+// RSVP answers to a study room
+const (
+	RSVPGoing    = "going"
+	RSVPMaybe    = "maybe"
+	RSVPDeclined = "declined"
+)
+
+// StudyRoom is a group focus block scheduled within a squad
+type StudyRoom struct {
+	ID              uuid.UUID       `json:"id"`
+	SquadID         uuid.UUID       `json:"squad_id"`
+	HostID          uuid.UUID       `json:"host_id"`
+	HostName        string          `json:"host_name"`
+	Topic           string          `json:"topic"`
+	StartsAt        time.Time       `json:"starts_at"`
+	EndsAt          time.Time       `json:"ends_at"`
+	DurationMinutes int             `json:"duration_minutes"`
+	Status          StudyRoomStatus `json:"status"`
+	CancelledAt     *time.Time      `json:"cancelled_at,omitempty"`
+	Going           int             `json:"going"`
+	Maybe           int             `json:"maybe"`
+	Attended        int             `json:"attended"`
+	MyRSVP          *string         `json:"my_rsvp"` // nil = no answer yet
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// StudyRoomDetail is a study room with everyone who answered or joined
+type StudyRoomDetail struct {
+	StudyRoom
+	Members []StudyRoomMember `json:"members"`
+}
+
+// StudyRoomMember is a member's RSVP and attendance for a study room
+type StudyRoomMember struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	DisplayName    string     `json:"display_name"`
+	AvatarURL      *string    `json:"avatar_url"`
+	RSVP           *string    `json:"rsvp"`
+	JoinedAt       *time.Time `json:"joined_at,omitempty"` // nil = did not attend
+	FocusedMinutes int        `json:"focused_minutes"`     // Net time in sessions linked to the room
+}
+
+// CreateStudyRoomRequest is the request body for scheduling a study room
+type CreateStudyRoomRequest struct {
+	Topic           string    `json:"topic"`
+	StartsAt        time.Time `json:"starts_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+}
+
+// RSVPRequest is the request body for answering a study room
+type RSVPRequest struct {
+	Status string `json:"status"` // going, maybe, declined
+}
+
+// JoinStudyRoomResponse is the response after joining a study room
+type JoinStudyRoomResponse struct {
+	RoomID  uuid.UUID           `json:"room_id"`
+	Session *StartFocusResponse `json:"session"`
+}
+
+// StudyRoomReminder is a study room about to start, with the members who
+// said they would come
+type StudyRoomReminder struct {
+	RoomID          uuid.UUID
+	SquadID         uuid.UUID
+	SquadName       string
+	Topic           string
+	StartsAt        time.Time
+	DurationMinutes int
+	Recipients      []uuid.UUID
+}
@@ -8,11 +8,59 @@ import (
 
 // Event subjects
 const (
-	SubjectActivityLogged = "events.activity.logged"
-	SubjectStreakRisk     = "events.streak.risk"
-	SubjectStreakBroken   = "events.streak.broken"
+	SubjectActivityLogged  = "events.activity.logged"
+	SubjectStreakRisk      = "events.streak.risk"
+	SubjectStreakBroken    = "events.streak.broken"
+	SubjectSquadStreakRisk = "events.squad.streak_risk"
+	SubjectStreakMilestone = "events.streak.milestone"
+
+	SubjectStudyRoomReminder = "events.study_room.reminder"
+	SubjectSquadOwnerChanged = "events.squad_owner.changed"
+)
+
+// Squad stream event kinds, published on events.squad.<squad_id>.<kind>
+const (
+	SquadEventFocusStarted    = "focus.started"
+	SquadEventFocusStopped    = "focus.stopped"
+	SquadEventFocusPaused     = "focus.paused"
+	SquadEventFocusResumed    = "focus.resumed"
+	SquadEventFocusBackfilled = "focus.backfilled"
+	SquadEventMemberJoined    = "member.joined"
+	SquadEventMemberLeft      = "member.left"
+	SquadEventMemberRole      = "member.role_changed"
+	SquadEventOwnerChanged    = "squad.owner_changed"
+	SquadEventArchived        = "squad.archived"
+	SquadEventNotification    = "notification"
+
+	SquadEventStudyRoomScheduled = "study_room.scheduled"
+	SquadEventStudyRoomCancelled = "study_room.cancelled"
 )
 
+// Personal stream event kinds, published on events.user.<user_id>.<kind>
+const (
+	UserEventStreakUpdated = "streak.updated"
+)
+
+// UserSubject is the subject of a personal stream event
+func UserSubject(userID uuid.UUID, kind string) string {
+	return "events.user." + userID.String() + "." + kind
+}
+
+// UserSubjects matches every personal stream event of a user
+func UserSubjects(userID uuid.UUID) string {
+	return "events.user." + userID.String() + ".>"
+}
+
+// SquadSubject is the subject of a squad stream event
+func SquadSubject(squadID uuid.UUID, kind string) string {
+	return "events.squad." + squadID.String() + "." + kind
+}
+
+// SquadSubjects matches every stream event of a squad
+func SquadSubjects(squadID uuid.UUID) string {
+	return "events.squad." + squadID.String() + ".>"
+}
+
 // BaseEvent is the common structure for all events
 type BaseEvent struct {
 	Type      string    `json:"type"`
@@ -25,7 +73,9 @@ type ActivityLoggedEvent struct {
 	BaseEvent
 	ActivityType string `json:"activity_type"` // focus_session, manual_checkin, squad_join
 	SquadID      string `json:"squad_id,omitempty"`
+	SessionID    string `json:"session_id,omitempty"` // Focus session, the idempotency key
 	Duration     int    `json:"duration_minutes,omitempty"`
+	ActivityDate string `json:"activity_date,omitempty"` // Local day to credit (YYYY-MM-DD) for backfills; empty = today
 }
 
 // StreakRiskEvent is published when a user's streak is at risk
@@ -34,7 +84,13 @@ type StreakRiskEvent struct {
 	UserName     string    `json:"user_name"`
 	StreakDays   int       `json:"streak_days"`
 	LastActivity time.Time `json:"last_activity"`
-	RiskFactor   string    `json:"risk_factor"` // inactive_24h, approaching_deadline
+	RiskFactor   string    `json:"risk_factor"`            // approaching_deadline, weekly_target
+	StreakMode   string    `json:"streak_mode,omitempty"`  // daily, weekly:N (StreakDays is in weeks)
+	Threshold    string    `json:"threshold,omitempty"`    // Grade, e.g. "6h", "2h"
+	Timezone     string    `json:"timezone,omitempty"`     // User's IANA timezone
+	LocalDate    string    `json:"local_date,omitempty"`   // Day at risk (YYYY-MM-DD, user timezone)
+	Deadline     time.Time `json:"deadline,omitempty"`     // Local midnight, as an instant
+	MinutesLeft  int       `json:"minutes_left,omitempty"` // Time left until the deadline
 }
 
 // StreakBrokenEvent is published when a user's streak breaks
@@ -43,6 +99,88 @@ type StreakBrokenEvent struct {
 	UserName      string `json:"user_name"`
 	BrokenStreak  int    `json:"broken_streak"`
 	LongestStreak int    `json:"longest_streak"`
+	StreakMode    string `json:"streak_mode,omitempty"` // daily, weekly:N (streaks are in weeks)
+}
+
+// StreakUpdatedEvent is pushed to the user's personal stream after an
+// activity changes their streak, e.g. when a focus session is credited in
+// the background
+type StreakUpdatedEvent struct {
+	BaseEvent
+	ActivityType  string `json:"activity_type"`
+	ActivityDate  string `json:"activity_date"` // YYYY-MM-DD, user timezone
+	FirstToday    bool   `json:"first_today"`   // First activity of the day
+	CurrentStreak int    `json:"current_streak"`
+	StreakMode    string `json:"streak_mode,omitempty"`
+}
+
+// StreakMilestoneEvent is published when a streak reaches a milestone or
+// passes the user's longest streak. Teammates are the user's squadmates.
+type StreakMilestoneEvent struct {
+	BaseEvent
+	UserName       string      `json:"user_name"`
+	Kind           string      `json:"kind"`  // milestone, personal_record
+	Value          int         `json:"value"` // Milestone, or the new record
+	PreviousRecord int         `json:"previous_record,omitempty"`
+	StreakMode     string      `json:"streak_mode,omitempty"` // daily, weekly:N (Value is in weeks)
+	Teammates      []uuid.UUID `json:"teammates,omitempty"`
+}
+
+// SquadStreakRiskEvent is published when a lagging member puts the squad
+// streak at risk. UserID is the lagging member; Teammates can nudge them.
+type SquadStreakRiskEvent struct {
+	BaseEvent
+	SquadID       uuid.UUID   `json:"squad_id"`
+	SquadName     string      `json:"squad_name"`
+	SquadStreak   int         `json:"squad_streak"`
+	UserName      string      `json:"user_name"`
+	Teammates     []uuid.UUID `json:"teammates"`
+	MembersNeeded int         `json:"members_needed"` // More active members needed today
+	Threshold     string      `json:"threshold,omitempty"`
+	Deadline      time.Time   `json:"deadline,omitempty"` // Lagging member's local midnight
+	MinutesLeft   int         `json:"minutes_left,omitempty"`
+}
+
+// SquadEvent is pushed to squad members in real time. Type is one of the
+// SquadEvent* kinds; the other fields are set as relevant to it.
+type SquadEvent struct {
+	BaseEvent
+	SquadID          uuid.UUID `json:"squad_id"`
+	SessionID        string    `json:"session_id,omitempty"`
+	DurationMinutes  int       `json:"duration_minutes,omitempty"`
+	Reason           string    `json:"reason,omitempty"` // focus.stopped: stopped, auto_stopped, hidden; member.left: left, removed; squad.owner_changed: transferred, owner_left
+	Solo             bool      `json:"solo,omitempty"`   // Focus events of a broadcast solo session
+	Role             string    `json:"role,omitempty"`   // member.role_changed: the new role
+	StudyRoomID      string    `json:"study_room_id,omitempty"`
+	NotificationType string    `json:"notification_type,omitempty"`
+	Title            string    `json:"title,omitempty"` // Study room events: the topic
+	Message          string    `json:"message,omitempty"`
+}
+
+// StudyRoomReminderEvent is published shortly before a study room starts.
+// Recipients are the members going or maybe coming.
+type StudyRoomReminderEvent struct {
+	BaseEvent
+	RoomID          uuid.UUID   `json:"room_id"`
+	SquadID         uuid.UUID   `json:"squad_id"`
+	SquadName       string      `json:"squad_name"`
+	Topic           string      `json:"topic"`
+	StartsAt        time.Time   `json:"starts_at"`
+	DurationMinutes int         `json:"duration_minutes"`
+	Recipients      []uuid.UUID `json:"recipients"`
+}
+
+// SquadOwnerChangedEvent is published when a squad gets a new owner, by
+// transfer or because the owner left. UserID is the new owner; Recipients
+// are the members to notify.
+type SquadOwnerChangedEvent struct {
+	BaseEvent
+	SquadID         uuid.UUID   `json:"squad_id"`
+	SquadName       string      `json:"squad_name"`
+	OwnerName       string      `json:"owner_name"`
+	PreviousOwnerID uuid.UUID   `json:"previous_owner_id"`
+	Reason          string      `json:"reason"` // transferred, owner_left
+	Recipients      []uuid.UUID `json:"recipients"`
 }
 
 // NewActivityLoggedEvent creates a new activity event
@@ -85,3 +223,93 @@ func NewStreakBrokenEvent(userID uuid.UUID, userName string, brokenStreak, longe
 		LongestStreak: longestStreak,
 	}
 }
+
+// NewSquadStreakRiskEvent creates a new squad streak risk event
+func NewSquadStreakRiskEvent(squadID uuid.UUID, squadName string, squadStreak int, userID uuid.UUID, userName string, teammates []uuid.UUID, membersNeeded int) SquadStreakRiskEvent {
+	return SquadStreakRiskEvent{
+		BaseEvent: BaseEvent{
+			Type:      SubjectSquadStreakRisk,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		},
+		SquadID:       squadID,
+		SquadName:     squadName,
+		SquadStreak:   squadStreak,
+		UserName:      userName,
+		Teammates:     teammates,
+		MembersNeeded: membersNeeded,
+	}
+}
+
+// NewStreakUpdatedEvent creates a new streak updated event
+func NewStreakUpdatedEvent(userID uuid.UUID, activityType string, currentStreak int) StreakUpdatedEvent {
+	return StreakUpdatedEvent{
+		BaseEvent: BaseEvent{
+			Type:      UserEventStreakUpdated,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		},
+		ActivityType:  activityType,
+		CurrentStreak: currentStreak,
+	}
+}
+
+// NewStreakMilestoneEvent creates a new streak milestone event
+func NewStreakMilestoneEvent(userID uuid.UUID, userName, kind string, value int) StreakMilestoneEvent {
+	return StreakMilestoneEvent{
+		BaseEvent: BaseEvent{
+			Type:      SubjectStreakMilestone,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		},
+		UserName: userName,
+		Kind:     kind,
+		Value:    value,
+	}
+}
+
+// NewSquadEvent creates a new squad stream event
+func NewSquadEvent(kind string, squadID, userID uuid.UUID) SquadEvent {
+	return SquadEvent{
+		BaseEvent: BaseEvent{
+			Type:      kind,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		},
+		SquadID: squadID,
+	}
+}
+
+// NewStudyRoomReminderEvent creates a new study room reminder event
+func NewStudyRoomReminderEvent(roomID, squadID uuid.UUID, squadName, topic string, startsAt time.Time, durationMinutes int, recipients []uuid.UUID) StudyRoomReminderEvent {
+	return StudyRoomReminderEvent{
+		BaseEvent: BaseEvent{
+			Type:      SubjectStudyRoomReminder,
+			Timestamp: time.Now(),
+		},
+		RoomID:          roomID,
+		SquadID:         squadID,
+		SquadName:       squadName,
+		Topic:           topic,
+		StartsAt:        startsAt,
+		DurationMinutes: durationMinutes,
+		Recipients:      recipients,
+	}
+}
+
+// NewSquadOwnerChangedEvent creates a new squad owner changed event
+func NewSquadOwnerChangedEvent(squadID uuid.UUID, squadName string, ownerID uuid.UUID, ownerName string, previousOwnerID uuid.UUID, reason string, recipients []uuid.UUID) SquadOwnerChangedEvent {
+	return SquadOwnerChangedEvent{
+		BaseEvent: BaseEvent{
+			Type:      SubjectSquadOwnerChanged,
+			UserID:    ownerID,
+			Timestamp: time.Now(),
+		},
+		SquadID:         squadID,
+		SquadName:       squadName,
+		OwnerName:       ownerName,
+		PreviousOwnerID: previousOwnerID,
+		Reason:          reason,
+		Recipients:      recipients,
+	}
+}
@@ -87,3 +87,60 @@ func (eb *EventBus) Subscribe(ctx context.Context, streamName string, subject st
 	_ = consumeCtx
 	return nil
 }
+
+// Follow delivers messages on subject to handler with their stream
+// sequence, starting after afterSeq (0 = new messages only). It uses an
+// ephemeral ordered consumer, so nothing is acked; call stop to end it.
+func (eb *EventBus) Follow(ctx context.Context, streamName string, subject string, afterSeq uint64, handler func(seq uint64, data []byte)) (stop func(), err error) {
+	config := jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{subject},
+		DeliverPolicy:  jetstream.DeliverNewPolicy,
+	}
+	if afterSeq > 0 {
+		config.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		config.OptStartSeq = afterSeq + 1
+	}
+
+	cons, err := eb.js.OrderedConsumer(ctx, streamName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	consumeCtx, err := cons.Consume(func(msg jetstream.Msg) {
+		meta, err := msg.Metadata()
+		if err != nil {
+			log.Printf("Error reading metadata on %s: %v", subject, err)
+			return
+		}
+		handler(meta.Sequence.Stream, msg.Data())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumeCtx.Stop, nil
+}
+
+// PublishEphemeral publishes on core NATS, outside JetStream: nothing is
+// stored and only live subscribers receive it
+func (eb *EventBus) PublishEphemeral(subject string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return eb.nc.Publish(subject, payload)
+}
+
+// SubscribeEphemeral receives core NATS messages on subject (wildcards
+// allowed) until unsubscribe is called
+func (eb *EventBus) SubscribeEphemeral(subject string, handler func(data []byte)) (unsubscribe func(), err error) {
+	sub, err := eb.nc.Subscribe(subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { _ = sub.Unsubscribe() }, nil
+}
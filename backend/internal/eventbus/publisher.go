@@ -43,6 +43,60 @@ func (p *Publisher) PublishStreakBroken(ctx context.Context, event StreakBrokenE
 	return p.publish(ctx, SubjectStreakBroken, event)
 }
 
+// PublishStreakUpdated publishes when an activity updates a user's streak
+func (p *Publisher) PublishStreakUpdated(ctx context.Context, event StreakUpdatedEvent) error {
+	if p.bus == nil {
+		log.Println("Warning: EventBus is nil, skipping publish")
+		return nil
+	}
+	return p.publish(ctx, UserSubject(event.UserID, UserEventStreakUpdated), event)
+}
+
+// PublishStreakMilestone publishes when a streak reaches a milestone or record
+func (p *Publisher) PublishStreakMilestone(ctx context.Context, event StreakMilestoneEvent) error {
+	if p.bus == nil {
+		log.Println("Warning: EventBus is nil, skipping publish")
+		return nil
+	}
+	return p.publish(ctx, SubjectStreakMilestone, event)
+}
+
+// PublishSquadStreakRisk publishes when a member's lapse puts a squad streak at risk
+func (p *Publisher) PublishSquadStreakRisk(ctx context.Context, event SquadStreakRiskEvent) error {
+	if p.bus == nil {
+		log.Println("Warning: EventBus is nil, skipping publish")
+		return nil
+	}
+	return p.publish(ctx, SubjectSquadStreakRisk, event)
+}
+
+// PublishStudyRoomReminder publishes when a study room is about to start
+func (p *Publisher) PublishStudyRoomReminder(ctx context.Context, event StudyRoomReminderEvent) error {
+	if p.bus == nil {
+		log.Println("Warning: EventBus is nil, skipping publish")
+		return nil
+	}
+	return p.publish(ctx, SubjectStudyRoomReminder, event)
+}
+
+// PublishSquadOwnerChanged publishes a squad ownership change
+func (p *Publisher) PublishSquadOwnerChanged(ctx context.Context, event SquadOwnerChangedEvent) error {
+	if p.bus == nil {
+		log.Println("Warning: EventBus is nil, skipping publish")
+		return nil
+	}
+	return p.publish(ctx, SubjectSquadOwnerChanged, event)
+}
+
+// PublishSquadEvent publishes a real-time event to a squad's stream
+func (p *Publisher) PublishSquadEvent(ctx context.Context, event SquadEvent) error {
+	if p.bus == nil {
+		log.Println("Warning: EventBus is nil, skipping publish")
+		return nil
+	}
+	return p.publish(ctx, SquadSubject(event.SquadID, event.Type), event)
+}
+
 func (p *Publisher) publish(ctx context.Context, subject string, event interface{}) error {
 	payload, err := json.Marshal(event)
 	if err != nil {
@@ -0,0 +1,90 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+)
+
+// ActivitySubscriber credits completed focus sessions to the user's streak
+type ActivitySubscriber struct {
+	bus           *eventbus.EventBus
+	streakService domain.StreakService
+	minMinutes    int // Shorter sessions don't count toward the streak
+}
+
+// NewActivitySubscriber creates a new subscriber
+func NewActivitySubscriber(bus *eventbus.EventBus, streakService domain.StreakService, minMinutes int) *ActivitySubscriber {
+	return &ActivitySubscriber{
+		bus:           bus,
+		streakService: streakService,
+		minMinutes:    minMinutes,
+	}
+}
+
+// Start begins listening on activity.logged events
+func (s *ActivitySubscriber) Start(ctx context.Context) error {
+	log.Printf("📡 Starting ActivitySubscriber on %s...", eventbus.SubjectActivityLogged)
+
+	// Ensure stream exists
+	if err := s.bus.InitStream(ctx, "ANTIGRAVITY", []string{"events.>"}); err != nil {
+		log.Printf("Warning: Stream init failed (may exist): %v", err)
+	}
+
+	return s.bus.Subscribe(ctx, "ANTIGRAVITY", eventbus.SubjectActivityLogged, "streak_activity_processor", s.handleMessage)
+}
+
+// handleMessage logs the session once per session ID; redeliveries are
+// reported as duplicates by the repository and acked
+func (s *ActivitySubscriber) handleMessage(msg []byte) error {
+	var event eventbus.ActivityLoggedEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Printf("Failed to parse event: %v", err)
+		return err
+	}
+
+	if event.ActivityType != string(domain.ActivityTypeFocusSession) {
+		return nil
+	}
+	if event.SessionID == "" {
+		log.Printf("Skipping focus session without session ID for %s", event.UserID)
+		return nil
+	}
+	if event.Duration < s.minMinutes {
+		log.Printf("Skipping focus session %s: %d min is under the %d min minimum", event.SessionID, event.Duration, s.minMinutes)
+		return nil
+	}
+
+	var activityDate *time.Time
+	if event.ActivityDate != "" {
+		date, err := time.Parse("2006-01-02", event.ActivityDate)
+		if err != nil {
+			log.Printf("Skipping focus session %s: invalid activity date %q", event.SessionID, event.ActivityDate)
+			return nil
+		}
+		activityDate = &date
+	}
+
+	response, err := s.streakService.LogFocusSession(context.Background(), event.UserID.String(), event.SessionID, event.Duration, activityDate)
+	if errors.Is(err, domain.ErrProfileNotFound) {
+		// Account deleted since the session ended; retrying won't help
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to credit focus session %s: %v", event.SessionID, err)
+		return err
+	}
+
+	if response.Duplicate {
+		log.Printf("Focus session %s already credited", event.SessionID)
+		return nil
+	}
+
+	log.Printf("✅ Focus session %s credited to %s (streak %d)", event.SessionID, event.UserID, response.CurrentStreak)
+	return nil
+}
@@ -0,0 +1,141 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/google/uuid"
+)
+
+// stubStreakService records LogFocusSession calls
+type stubStreakService struct {
+	domain.StreakService
+	calls    []string
+	dates    []*time.Time
+	response *domain.LogActivityResponse
+	err      error
+}
+
+func (s *stubStreakService) LogFocusSession(ctx context.Context, userID, sessionID string, duration int, activityDate *time.Time) (*domain.LogActivityResponse, error) {
+	s.calls = append(s.calls, sessionID)
+	s.dates = append(s.dates, activityDate)
+	return s.response, s.err
+}
+
+func activityMessage(t *testing.T, activityType, sessionID string, duration int) []byte {
+	t.Helper()
+	event := eventbus.NewActivityLoggedEvent(uuid.New(), activityType)
+	event.SessionID = sessionID
+	event.Duration = duration
+	msg, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestActivitySubscriber_HandleMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       func(t *testing.T) []byte
+		response  *domain.LogActivityResponse
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "credits focus session",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "focus_session", "s1", 25) },
+			response:  &domain.LogActivityResponse{Success: true, IsNew: true},
+			wantCalls: 1,
+		},
+		{
+			name:      "duplicate session is acked",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "focus_session", "s1", 25) },
+			response:  &domain.LogActivityResponse{Success: true, Duplicate: true},
+			wantCalls: 1,
+		},
+		{
+			name:      "under minimum duration is skipped",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "focus_session", "s1", 4) },
+			wantCalls: 0,
+		},
+		{
+			name:      "missing session id is skipped",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "focus_session", "", 25) },
+			wantCalls: 0,
+		},
+		{
+			name:      "other activity types are ignored",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "manual_checkin", "s1", 25) },
+			wantCalls: 0,
+		},
+		{
+			name:      "deleted profile is not retried",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "focus_session", "s1", 25) },
+			err:       domain.ErrProfileNotFound,
+			wantCalls: 1,
+		},
+		{
+			name:      "storage error is retried",
+			msg:       func(t *testing.T) []byte { return activityMessage(t, "focus_session", "s1", 25) },
+			err:       errors.New("connection reset"),
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:    "malformed message",
+			msg:     func(t *testing.T) []byte { return []byte("{") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &stubStreakService{response: tt.response, err: tt.err}
+			sub := NewActivitySubscriber(nil, svc, 5)
+
+			err := sub.handleMessage(tt.msg(t))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(svc.calls) != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, len(svc.calls))
+			}
+		})
+	}
+}
+
+func TestActivitySubscriber_BackfillDate(t *testing.T) {
+	svc := &stubStreakService{response: &domain.LogActivityResponse{Success: true, IsNew: true}}
+	sub := NewActivitySubscriber(nil, svc, 5)
+
+	event := eventbus.NewActivityLoggedEvent(uuid.New(), "focus_session")
+	event.SessionID = "s1"
+	event.Duration = 45
+	event.ActivityDate = "2026-03-10"
+	msg, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sub.handleMessage(msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(svc.dates) != 1 || svc.dates[0] == nil || svc.dates[0].Format("2006-01-02") != "2026-03-10" {
+		t.Errorf("expected the session credited on 2026-03-10, got %v", svc.dates)
+	}
+
+	// Live sessions credit today
+	if err := sub.handleMessage(activityMessage(t, "focus_session", "s2", 25)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.dates[1] != nil {
+		t.Errorf("expected no date for a live session, got %v", svc.dates[1])
+	}
+}
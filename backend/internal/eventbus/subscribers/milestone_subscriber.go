@@ -0,0 +1,107 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/antigravity/backend/internal/ai"
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/repository"
+)
+
+// MilestoneSubscriber listens to streak milestone events, congratulates
+// the user and lets their squadmates celebrate with them
+type MilestoneSubscriber struct {
+	bus       *eventbus.EventBus
+	groq      *ai.GroqClient
+	notifRepo *repository.NotificationRepository
+}
+
+// NewMilestoneSubscriber creates a new subscriber
+func NewMilestoneSubscriber(bus *eventbus.EventBus, groq *ai.GroqClient, notifRepo *repository.NotificationRepository) *MilestoneSubscriber {
+	return &MilestoneSubscriber{
+		bus:       bus,
+		groq:      groq,
+		notifRepo: notifRepo,
+	}
+}
+
+// Start begins listening on streak.milestone events
+func (s *MilestoneSubscriber) Start(ctx context.Context) error {
+	log.Printf("📡 Starting MilestoneSubscriber on %s...", eventbus.SubjectStreakMilestone)
+
+	// Ensure stream exists
+	if err := s.bus.InitStream(ctx, "ANTIGRAVITY", []string{"events.>"}); err != nil {
+		log.Printf("Warning: Stream init failed (may exist): %v", err)
+	}
+
+	return s.bus.Subscribe(ctx, "ANTIGRAVITY", eventbus.SubjectStreakMilestone, "streak_milestone_processor", s.handleMessage)
+}
+
+func (s *MilestoneSubscriber) handleMessage(msg []byte) error {
+	var event eventbus.StreakMilestoneEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Printf("Failed to parse event: %v", err)
+		return err
+	}
+
+	unit := domain.StreakUnitDays
+	if mode, err := domain.ParseStreakMode(event.StreakMode); err == nil {
+		unit = mode.Unit()
+	}
+	personalRecord := event.Kind == string(domain.AchievementPersonalRecord)
+
+	log.Printf("🏆 %s reached %d %s (%s)", event.UserName, event.Value, unit, event.Kind)
+
+	// AI-written congratulation
+	ctx := context.Background()
+	congrats, err := s.groq.GenerateCongratulation(ctx, event.UserName, event.Value, unit, personalRecord)
+	if err != nil {
+		log.Printf("Groq error (using fallback): %v", err)
+		congrats = fmt.Sprintf("%d %s in a row. that's real consistency, keep it going!", event.Value, unit)
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"kind":            event.Kind,
+		"value":           event.Value,
+		"previous_record": event.PreviousRecord,
+		"streak_mode":     event.StreakMode,
+		"user_id":         event.UserID,
+	})
+
+	title := fmt.Sprintf("%d %s streak! 🎉", event.Value, unit)
+	teammateMessage := fmt.Sprintf("%s just hit a %d-%s streak! Send them some love.", event.UserName, event.Value, strings.TrimSuffix(unit, "s"))
+	if personalRecord {
+		title = "New personal best! 🏆"
+		teammateMessage = fmt.Sprintf("%s just beat their best streak with %d %s in a row! Send them some love.", event.UserName, event.Value, unit)
+	}
+
+	notifications := []*domain.Notification{{
+		UserID:   event.UserID,
+		Type:     "streak_milestone",
+		Title:    title,
+		Message:  congrats,
+		Metadata: metadata,
+	}}
+	for _, teammate := range event.Teammates {
+		notifications = append(notifications, &domain.Notification{
+			UserID:   teammate,
+			Type:     "streak_milestone",
+			Title:    "Squad celebration! 🎉",
+			Message:  teammateMessage,
+			Metadata: metadata,
+		})
+	}
+
+	if err := s.notifRepo.CreateMany(ctx, notifications); err != nil {
+		log.Printf("Failed to save notifications: %v", err)
+		return err
+	}
+
+	log.Printf("✅ Milestone celebrated with %d members", len(notifications))
+	return nil
+}
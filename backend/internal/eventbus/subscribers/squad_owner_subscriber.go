@@ -0,0 +1,92 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/repository"
+)
+
+// SquadOwnerSubscriber listens to squad ownership changes and tells the
+// members who leads the squad now
+type SquadOwnerSubscriber struct {
+	bus       *eventbus.EventBus
+	notifRepo *repository.NotificationRepository
+}
+
+// NewSquadOwnerSubscriber creates a new subscriber
+func NewSquadOwnerSubscriber(bus *eventbus.EventBus, notifRepo *repository.NotificationRepository) *SquadOwnerSubscriber {
+	return &SquadOwnerSubscriber{
+		bus:       bus,
+		notifRepo: notifRepo,
+	}
+}
+
+// Start begins listening on squad_owner.changed events
+func (s *SquadOwnerSubscriber) Start(ctx context.Context) error {
+	log.Printf("📡 Starting SquadOwnerSubscriber on %s...", eventbus.SubjectSquadOwnerChanged)
+
+	// Ensure stream exists
+	if err := s.bus.InitStream(ctx, "ANTIGRAVITY", []string{"events.>"}); err != nil {
+		log.Printf("Warning: Stream init failed (may exist): %v", err)
+	}
+
+	return s.bus.Subscribe(ctx, "ANTIGRAVITY", eventbus.SubjectSquadOwnerChanged, "squad_owner_processor", s.handleMessage)
+}
+
+func (s *SquadOwnerSubscriber) handleMessage(msg []byte) error {
+	var event eventbus.SquadOwnerChangedEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Printf("Failed to parse event: %v", err)
+		return err
+	}
+
+	ctx := context.Background()
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"squad_id":          event.SquadID,
+		"owner_id":          event.UserID,
+		"previous_owner_id": event.PreviousOwnerID,
+		"reason":            event.Reason,
+	})
+
+	notifications := make([]*domain.Notification, 0, len(event.Recipients))
+	for _, userID := range event.Recipients {
+		title, message := ownerChangeText(event, userID == event.UserID)
+		notifications = append(notifications, &domain.Notification{
+			UserID:   userID,
+			Type:     "squad_owner_changed",
+			Title:    title,
+			Message:  message,
+			Metadata: metadata,
+		})
+	}
+	if err := s.notifRepo.CreateMany(ctx, notifications); err != nil {
+		log.Printf("Failed to save notifications: %v", err)
+		return err
+	}
+
+	log.Printf("✅ Owner change of %s sent to %d members", event.SquadName, len(event.Recipients))
+	return nil
+}
+
+// ownerChangeText phrases the notification for the new owner or the rest
+// of the squad
+func ownerChangeText(event eventbus.SquadOwnerChangedEvent, isNewOwner bool) (string, string) {
+	if isNewOwner {
+		title := fmt.Sprintf("You now lead %s 👑", event.SquadName)
+		if event.Reason == "owner_left" {
+			return title, "The previous owner left and you've been in the squad the longest, so it's yours now. You can edit it, manage roles or hand it over."
+		}
+		return title, "The squad was handed over to you. You can edit it, manage roles or hand it over."
+	}
+
+	title := fmt.Sprintf("%s has a new owner", event.SquadName)
+	if event.Reason == "owner_left" {
+		return title, fmt.Sprintf("The previous owner left, so %s leads the squad now.", event.OwnerName)
+	}
+	return title, fmt.Sprintf("%s leads the squad now.", event.OwnerName)
+}
@@ -0,0 +1,100 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/repository"
+)
+
+// SquadStreakSubscriber listens to squad streak risk events and asks
+// teammates to nudge the lagging member
+type SquadStreakSubscriber struct {
+	bus       *eventbus.EventBus
+	publisher *eventbus.Publisher
+	notifRepo *repository.NotificationRepository
+}
+
+// NewSquadStreakSubscriber creates a new subscriber
+func NewSquadStreakSubscriber(bus *eventbus.EventBus, notifRepo *repository.NotificationRepository) *SquadStreakSubscriber {
+	return &SquadStreakSubscriber{
+		bus:       bus,
+		publisher: eventbus.NewPublisher(bus),
+		notifRepo: notifRepo,
+	}
+}
+
+// Start begins listening on squad.streak_risk events
+func (s *SquadStreakSubscriber) Start(ctx context.Context) error {
+	log.Printf("📡 Starting SquadStreakSubscriber on %s...", eventbus.SubjectSquadStreakRisk)
+
+	// Ensure stream exists
+	if err := s.bus.InitStream(ctx, "ANTIGRAVITY", []string{"events.>"}); err != nil {
+		log.Printf("Warning: Stream init failed (may exist): %v", err)
+	}
+
+	return s.bus.Subscribe(ctx, "ANTIGRAVITY", eventbus.SubjectSquadStreakRisk, "squad_streak_processor", s.handleMessage)
+}
+
+func (s *SquadStreakSubscriber) handleMessage(msg []byte) error {
+	var event eventbus.SquadStreakRiskEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Printf("Failed to parse event: %v", err)
+		return err
+	}
+
+	log.Printf("⚠️ Squad %s (%d days) at risk: %s hasn't logged today", event.SquadName, event.SquadStreak, event.UserName)
+
+	ctx := context.Background()
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"squad_id":       event.SquadID,
+		"lagging_user":   event.UserID,
+		"squad_streak":   event.SquadStreak,
+		"members_needed": event.MembersNeeded,
+		"threshold":      event.Threshold,
+		"deadline":       event.Deadline,
+	})
+
+	// The lagging member
+	notifications := []*domain.Notification{{
+		UserID:   event.UserID,
+		Type:     "squad_streak_alert",
+		Title:    fmt.Sprintf("%s is counting on you! 🔥", event.SquadName),
+		Message:  fmt.Sprintf("Your squad's %d-day streak needs you today. One quick session keeps it alive.", event.SquadStreak),
+		Metadata: metadata,
+	}}
+
+	// Teammates, so they can nudge them
+	teammateTitle := "Squad Streak at Risk! 🔥"
+	teammateMessage := fmt.Sprintf("%s hasn't logged today and %s's %d-day streak is on the line. Give them a nudge!", event.UserName, event.SquadName, event.SquadStreak)
+	for _, teammate := range event.Teammates {
+		notifications = append(notifications, &domain.Notification{
+			UserID:   teammate,
+			Type:     "squad_streak_alert",
+			Title:    teammateTitle,
+			Message:  teammateMessage,
+			Metadata: metadata,
+		})
+	}
+
+	if err := s.notifRepo.CreateMany(ctx, notifications); err != nil {
+		log.Printf("Failed to save notifications: %v", err)
+		return err
+	}
+
+	// Live squad views show the alert too
+	squadEvent := eventbus.NewSquadEvent(eventbus.SquadEventNotification, event.SquadID, event.UserID)
+	squadEvent.NotificationType = "squad_streak_alert"
+	squadEvent.Title = teammateTitle
+	squadEvent.Message = teammateMessage
+	if err := s.publisher.PublishSquadEvent(ctx, squadEvent); err != nil {
+		log.Printf("Failed to publish squad event: %v", err)
+	}
+
+	log.Printf("✅ Squad streak alert sent to %d members of %s", len(notifications), event.SquadName)
+	return nil
+}
@@ -0,0 +1,87 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/repository"
+)
+
+// StreakBrokenSubscriber listens to streak broken events and encourages
+// the user to start again
+type StreakBrokenSubscriber struct {
+	bus       *eventbus.EventBus
+	notifRepo *repository.NotificationRepository
+}
+
+// NewStreakBrokenSubscriber creates a new subscriber
+func NewStreakBrokenSubscriber(bus *eventbus.EventBus, notifRepo *repository.NotificationRepository) *StreakBrokenSubscriber {
+	return &StreakBrokenSubscriber{
+		bus:       bus,
+		notifRepo: notifRepo,
+	}
+}
+
+// Start begins listening on streak.broken events
+func (s *StreakBrokenSubscriber) Start(ctx context.Context) error {
+	log.Printf("📡 Starting StreakBrokenSubscriber on %s...", eventbus.SubjectStreakBroken)
+
+	// Ensure stream exists
+	if err := s.bus.InitStream(ctx, "ANTIGRAVITY", []string{"events.>"}); err != nil {
+		log.Printf("Warning: Stream init failed (may exist): %v", err)
+	}
+
+	return s.bus.Subscribe(ctx, "ANTIGRAVITY", eventbus.SubjectStreakBroken, "streak_broken_processor", s.handleMessage)
+}
+
+func (s *StreakBrokenSubscriber) handleMessage(msg []byte) error {
+	var event eventbus.StreakBrokenEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Printf("Failed to parse event: %v", err)
+		return err
+	}
+
+	log.Printf("💔 %s's streak of %d ended", event.UserName, event.BrokenStreak)
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"broken_streak":  event.BrokenStreak,
+		"longest_streak": event.LongestStreak,
+		"streak_mode":    event.StreakMode,
+	})
+
+	notification := &domain.Notification{
+		UserID:   event.UserID,
+		Type:     "streak_broken",
+		Title:    "Every streak starts with day one 🌱",
+		Message:  streakBrokenMessage(event),
+		Metadata: metadata,
+	}
+
+	if err := s.notifRepo.Create(context.Background(), notification); err != nil {
+		log.Printf("Failed to save notification: %v", err)
+		return err
+	}
+
+	log.Printf("✅ Streak broken notification sent to %s", event.UserName)
+	return nil
+}
+
+// streakBrokenMessage acknowledges the effort rather than the miss
+func streakBrokenMessage(event eventbus.StreakBrokenEvent) string {
+	unit := domain.StreakUnitDays
+	if mode, err := domain.ParseStreakMode(event.StreakMode); err == nil {
+		unit = mode.Unit()
+	}
+	length := fmt.Sprintf("%d-%s", event.BrokenStreak, strings.TrimSuffix(unit, "s"))
+
+	if event.LongestStreak > event.BrokenStreak {
+		return fmt.Sprintf("Your %s streak has ended, and that's okay. You've gone %d %s before, so you know you can do it. Start again today.",
+			length, event.LongestStreak, unit)
+	}
+	return fmt.Sprintf("Your %s streak has ended, and that's okay. It's still your best run yet. One session today starts the next one.", length)
+}
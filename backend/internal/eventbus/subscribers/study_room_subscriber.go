@@ -0,0 +1,87 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/repository"
+)
+
+// StudyRoomSubscriber listens to study room reminder events and notifies
+// the members who said they would come
+type StudyRoomSubscriber struct {
+	bus       *eventbus.EventBus
+	notifRepo *repository.NotificationRepository
+}
+
+// NewStudyRoomSubscriber creates a new subscriber
+func NewStudyRoomSubscriber(bus *eventbus.EventBus, notifRepo *repository.NotificationRepository) *StudyRoomSubscriber {
+	return &StudyRoomSubscriber{
+		bus:       bus,
+		notifRepo: notifRepo,
+	}
+}
+
+// Start begins listening on study_room.reminder events
+func (s *StudyRoomSubscriber) Start(ctx context.Context) error {
+	log.Printf("📡 Starting StudyRoomSubscriber on %s...", eventbus.SubjectStudyRoomReminder)
+
+	// Ensure stream exists
+	if err := s.bus.InitStream(ctx, "ANTIGRAVITY", []string{"events.>"}); err != nil {
+		log.Printf("Warning: Stream init failed (may exist): %v", err)
+	}
+
+	return s.bus.Subscribe(ctx, "ANTIGRAVITY", eventbus.SubjectStudyRoomReminder, "study_room_reminder_processor", s.handleMessage)
+}
+
+func (s *StudyRoomSubscriber) handleMessage(msg []byte) error {
+	var event eventbus.StudyRoomReminderEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Printf("Failed to parse event: %v", err)
+		return err
+	}
+
+	ctx := context.Background()
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"study_room_id":    event.RoomID,
+		"squad_id":         event.SquadID,
+		"starts_at":        event.StartsAt,
+		"duration_minutes": event.DurationMinutes,
+	})
+
+	title := fmt.Sprintf("Study room starting soon in %s 📚", event.SquadName)
+	message := fmt.Sprintf("\"%s\" starts %s and runs %d minutes. Join to focus together!",
+		event.Topic, startsIn(event.StartsAt, event.Timestamp), event.DurationMinutes)
+
+	notifications := make([]*domain.Notification, 0, len(event.Recipients))
+	for _, userID := range event.Recipients {
+		notifications = append(notifications, &domain.Notification{
+			UserID:   userID,
+			Type:     "study_room_reminder",
+			Title:    title,
+			Message:  message,
+			Metadata: metadata,
+		})
+	}
+	if err := s.notifRepo.CreateMany(ctx, notifications); err != nil {
+		log.Printf("Failed to save notifications: %v", err)
+		return err
+	}
+
+	log.Printf("✅ Study room reminder sent to %d members of %s", len(event.Recipients), event.SquadName)
+	return nil
+}
+
+// startsIn phrases how soon a room starts, as of when the reminder was sent
+func startsIn(startsAt, sentAt time.Time) string {
+	minutes := int(startsAt.Sub(sentAt).Round(time.Minute) / time.Minute)
+	if minutes <= 1 {
+		return "in a minute"
+	}
+	return fmt.Sprintf("in %d minutes", minutes)
+}
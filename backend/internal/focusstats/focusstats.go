@@ -0,0 +1,263 @@
+// Package focusstats aggregates a user's focus sessions into per-period
+// totals, a weekday x hour-of-day heatmap and a trend against the
+// previous period.
+//
+// Sessions count toward the day, week or month they started in, in the
+// user's timezone. The heatmap spreads each session's net minutes over
+// the local hours it spanned, so a 9:40-10:20 session counts in both 9:00
+// and 10:00.
+//
+// Callers load the sessions of the window (see Window) and pass in the
+// user's current day, so results don't depend on when they are computed.
+package focusstats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Period is the length of one bucket
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week" // ISO week, starting Monday
+	PeriodMonth Period = "month"
+)
+
+// Valid reports whether p is a known period
+func (p Period) Valid() bool {
+	return p == PeriodDay || p == PeriodWeek || p == PeriodMonth
+}
+
+// Session is an ended focus session
+type Session struct {
+	Start   time.Time
+	End     time.Time
+	Minutes int // Net of pauses
+}
+
+// Totals aggregates the sessions of a span of time
+type Totals struct {
+	Sessions       int
+	TotalMinutes   int
+	AverageMinutes float64 // Rounded to one decimal
+	LongestMinutes int
+}
+
+// Bucket is the totals of one period. Start is its first day, as midnight
+// UTC (like streak.DateOf).
+type Bucket struct {
+	Start time.Time
+	Totals
+}
+
+// Heatmap holds focused minutes by weekday (Monday = 0) and local hour
+type Heatmap [7][24]int
+
+// Result is the stats of the last Count periods up to today
+type Result struct {
+	From     time.Time // First day of the window
+	To       time.Time // Day after the window
+	Buckets  []Bucket  // Oldest first, empty periods included
+	Current  Totals    // Whole window
+	Previous Totals    // The same number of periods just before it
+	Heatmap  Heatmap
+}
+
+// Input is what Compute needs
+type Input struct {
+	Sessions []Session
+	Location *time.Location
+	Period   Period
+	Count    int       // Periods in the window, including the current one
+	Today    time.Time // User's current day, as midnight UTC
+}
+
+// PeriodStart returns the first day of the period date falls in
+func PeriodStart(p Period, date time.Time) time.Time {
+	switch p {
+	case PeriodWeek:
+		offset := (int(date.Weekday()) + 6) % 7
+		return date.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return date
+	}
+}
+
+// addPeriods moves a period start n periods forward (or back if negative)
+func addPeriods(p Period, start time.Time, n int) time.Time {
+	switch p {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7*n)
+	case PeriodMonth:
+		return start.AddDate(0, n, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// Window returns the first day of the window and of the previous window,
+// and the day after the window
+func Window(p Period, today time.Time, count int) (previousFrom, from, to time.Time) {
+	current := PeriodStart(p, today)
+	from = addPeriods(p, current, -(count - 1))
+	return addPeriods(p, from, -count), from, addPeriods(p, current, 1)
+}
+
+// Compute aggregates sessions into the window ending with today's period
+func Compute(in Input) Result {
+	loc := in.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	previousFrom, from, to := Window(in.Period, in.Today, in.Count)
+
+	result := Result{From: from, To: to, Buckets: make([]Bucket, in.Count)}
+	for i := range result.Buckets {
+		result.Buckets[i].Start = addPeriods(in.Period, from, i)
+	}
+
+	var heat [7][24]float64
+	var current, previous []int
+	for _, s := range in.Sessions {
+		y, m, d := s.Start.In(loc).Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+		switch {
+		case !day.Before(from) && day.Before(to):
+			current = append(current, s.Minutes)
+			i := bucketIndex(in.Period, from, PeriodStart(in.Period, day))
+			result.Buckets[i].add(s.Minutes)
+			spread(&heat, s, loc)
+		case !day.Before(previousFrom) && day.Before(from):
+			previous = append(previous, s.Minutes)
+		}
+	}
+
+	for i := range result.Buckets {
+		result.Buckets[i].finish()
+	}
+	result.Current = totalsOf(current)
+	result.Previous = totalsOf(previous)
+	for wd := range heat {
+		for h := range heat[wd] {
+			result.Heatmap[wd][h] = int(math.Round(heat[wd][h]))
+		}
+	}
+	return result
+}
+
+// bucketIndex is the position of a period start in a window starting at from
+func bucketIndex(p Period, from, start time.Time) int {
+	switch p {
+	case PeriodWeek:
+		return int(start.Sub(from).Hours()/24) / 7
+	case PeriodMonth:
+		return (start.Year()-from.Year())*12 + int(start.Month()) - int(from.Month())
+	default:
+		return int(start.Sub(from).Hours() / 24)
+	}
+}
+
+// spread adds a session's net minutes to the heatmap, split over the local
+// hours it spanned in proportion to the wall time in each
+func spread(heat *[7][24]float64, s Session, loc *time.Location) {
+	wall := s.End.Sub(s.Start)
+	if wall <= 0 || s.Minutes <= 0 {
+		return
+	}
+
+	for cursor := s.Start; cursor.Before(s.End); {
+		local := cursor.In(loc)
+		next := time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, loc)
+		if next.After(s.End) {
+			next = s.End
+		}
+
+		share := float64(next.Sub(cursor)) / float64(wall)
+		weekday := (int(local.Weekday()) + 6) % 7
+		heat[weekday][local.Hour()] += share * float64(s.Minutes)
+		cursor = next
+	}
+}
+
+func (b *Bucket) add(minutes int) {
+	b.Sessions++
+	b.TotalMinutes += minutes
+	if minutes > b.LongestMinutes {
+		b.LongestMinutes = minutes
+	}
+}
+
+func (b *Bucket) finish() {
+	if b.Sessions > 0 {
+		b.AverageMinutes = math.Round(float64(b.TotalMinutes)/float64(b.Sessions)*10) / 10
+	}
+}
+
+func totalsOf(minutes []int) Totals {
+	var b Bucket
+	for _, m := range minutes {
+		b.add(m)
+	}
+	b.finish()
+	return b.Totals
+}
+
+// BestHours returns up to n local hours with the most focused minutes,
+// most first. Hours with no focus are left out.
+func (h Heatmap) BestHours(n int) []int {
+	var byHour [24]int
+	for wd := range h {
+		for hour, minutes := range h[wd] {
+			byHour[hour] += minutes
+		}
+	}
+	return top(byHour[:], n)
+}
+
+// BestWeekday returns the weekday (Monday = 0) with the most focused
+// minutes, or -1 if there are none
+func (h Heatmap) BestWeekday() int {
+	byDay := make([]int, 7)
+	for wd := range h {
+		for _, minutes := range h[wd] {
+			byDay[wd] += minutes
+		}
+	}
+	best := top(byDay, 1)
+	if len(best) == 0 {
+		return -1
+	}
+	return best[0]
+}
+
+// top returns the indexes of the n largest positive values, largest first
+// (earliest index first on ties)
+func top(values []int, n int) []int {
+	indexes := []int{}
+	for i, v := range values {
+		if v > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.SliceStable(indexes, func(a, b int) bool { return values[indexes[a]] > values[indexes[b]] })
+	if len(indexes) > n {
+		indexes = indexes[:n]
+	}
+	return indexes
+}
+
+// Change is the percentage change from previous to current, or nil when
+// there is nothing to compare with
+func Change(previous, current int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round(float64(current-previous)/float64(previous)*1000) / 10
+	return &change
+}
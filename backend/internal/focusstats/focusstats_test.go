@@ -0,0 +1,110 @@
+package focusstats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func session(start time.Time, wall time.Duration, minutes int) Session {
+	return Session{Start: start, End: start.Add(wall), Minutes: minutes}
+}
+
+func TestComputeBuckets(t *testing.T) {
+	today := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC) // Wednesday
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+
+	sessions := []Session{
+		session(at(11, 9), 50*time.Minute, 50),  // This week
+		session(at(9, 14), 30*time.Minute, 30),  // This week (Monday)
+		session(at(8, 20), 90*time.Minute, 80),  // Last week (Sunday), paused 10 min
+		session(at(2, 10), 25*time.Minute, 25),  // Last week (Monday)
+		session(at(1, 10), 60*time.Minute, 60),  // Previous window
+		session(at(16, 10), 60*time.Minute, 60), // Next week, out of range
+	}
+
+	result := Compute(Input{Sessions: sessions, Location: time.UTC, Period: PeriodWeek, Count: 2, Today: today})
+
+	if want := at(2, 0); !result.From.Equal(want) {
+		t.Errorf("expected window from %s, got %s", want, result.From)
+	}
+	if want := at(16, 0); !result.To.Equal(want) {
+		t.Errorf("expected window to %s, got %s", want, result.To)
+	}
+
+	wantBuckets := []Bucket{
+		{Start: at(2, 0), Totals: Totals{Sessions: 2, TotalMinutes: 105, AverageMinutes: 52.5, LongestMinutes: 80}},
+		{Start: at(9, 0), Totals: Totals{Sessions: 2, TotalMinutes: 80, AverageMinutes: 40, LongestMinutes: 50}},
+	}
+	if !reflect.DeepEqual(result.Buckets, wantBuckets) {
+		t.Errorf("expected buckets %+v, got %+v", wantBuckets, result.Buckets)
+	}
+
+	if want := (Totals{Sessions: 4, TotalMinutes: 185, AverageMinutes: 46.3, LongestMinutes: 80}); result.Current != want {
+		t.Errorf("expected current %+v, got %+v", want, result.Current)
+	}
+	if want := (Totals{Sessions: 1, TotalMinutes: 60, AverageMinutes: 60, LongestMinutes: 60}); result.Previous != want {
+		t.Errorf("expected previous %+v, got %+v", want, result.Previous)
+	}
+}
+
+func TestComputeUsesLocalDays(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	today := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	// 20:00 UTC on Mar 10 is 01:30 on Mar 11 in Kolkata
+	sessions := []Session{session(time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC), time.Hour, 60)}
+	result := Compute(Input{Sessions: sessions, Location: kolkata, Period: PeriodDay, Count: 2, Today: today})
+
+	if result.Buckets[0].Sessions != 0 || result.Buckets[1].Sessions != 1 {
+		t.Errorf("expected the session on Mar 11, got %+v", result.Buckets)
+	}
+	// Wednesday, split over 01:30-02:00 and 02:00-02:30
+	if result.Heatmap[2][1] != 30 || result.Heatmap[2][2] != 30 {
+		t.Errorf("expected 30 minutes at 1:00 and 2:00 on Wednesday, got %v", result.Heatmap[2])
+	}
+}
+
+func TestHeatmapSpreadsNetMinutes(t *testing.T) {
+	today := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	// 9:40-10:40 wall time with 30 minutes paused: 30 net minutes
+	sessions := []Session{session(time.Date(2026, 3, 11, 9, 40, 0, 0, time.UTC), 60*time.Minute, 30)}
+	result := Compute(Input{Sessions: sessions, Location: time.UTC, Period: PeriodMonth, Count: 1, Today: today})
+
+	if result.Heatmap[2][9] != 10 || result.Heatmap[2][10] != 20 {
+		t.Errorf("expected 10 minutes at 9:00 and 20 at 10:00, got %d and %d", result.Heatmap[2][9], result.Heatmap[2][10])
+	}
+	if got := result.Heatmap.BestHours(3); !reflect.DeepEqual(got, []int{10, 9}) {
+		t.Errorf("expected best hours [10 9], got %v", got)
+	}
+	if got := result.Heatmap.BestWeekday(); got != 2 {
+		t.Errorf("expected Wednesday, got %d", got)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	result := Compute(Input{Period: PeriodMonth, Count: 12, Today: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)})
+
+	if len(result.Buckets) != 12 {
+		t.Fatalf("expected 12 buckets, got %d", len(result.Buckets))
+	}
+	if want := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC); !result.From.Equal(want) {
+		t.Errorf("expected window from %s, got %s", want, result.From)
+	}
+	if len(result.Heatmap.BestHours(3)) != 0 || result.Heatmap.BestWeekday() != -1 {
+		t.Error("expected no best hours or weekday without sessions")
+	}
+}
+
+func TestChange(t *testing.T) {
+	if Change(0, 30) != nil {
+		t.Error("expected no change without a previous period")
+	}
+	if got := Change(120, 90); got == nil || *got != -25 {
+		t.Errorf("expected -25%%, got %v", got)
+	}
+	if got := Change(30, 40); got == nil || *got != 33.3 {
+		t.Errorf("expected 33.3%%, got %v", got)
+	}
+}
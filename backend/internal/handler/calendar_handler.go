@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CalendarHandler handles HTTP requests for iCalendar feeds
+type CalendarHandler struct {
+	service domain.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(service domain.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// GetFeed handles GET /api/v1/calendar/feed
+func (h *CalendarHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	feed, err := h.service.GetFeed(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to fetch calendar feed")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, feed)
+}
+
+// CreateFeed handles POST /api/v1/calendar/feed. It (re)generates the
+// secret feed URL; older URLs stop working.
+func (h *CalendarHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	feed, err := h.service.CreateFeed(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create calendar feed")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, feed)
+}
+
+// RevokeFeed handles DELETE /api/v1/calendar/feed
+func (h *CalendarHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	if err := h.service.RevokeFeed(r.Context(), userID); err != nil {
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to revoke calendar feed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeFeed handles GET /api/v1/calendar/feeds/{token}.ics. It is not
+// behind auth: calendar apps fetch it, and the token is the secret.
+func (h *CalendarHandler) ServeFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.RenderFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarFeedNotFound) {
+			respondError(w, http.StatusNotFound, "FEED_NOT_FOUND", "Calendar feed not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to render calendar feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="antigravity.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(feed)
+}
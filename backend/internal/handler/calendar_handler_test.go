@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/handler"
+	"github.com/antigravity/backend/internal/mocks"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestCalendarHandler_ServeFeed(t *testing.T) {
+	mockService := &mocks.MockCalendarService{}
+	h := handler.NewCalendarHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/calendar/feeds/{token}.ics", h.ServeFeed)
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.RenderFeedFunc = func(ctx context.Context, token string) ([]byte, error) {
+			if token != "s3cret-token" {
+				t.Errorf("expected token s3cret-token, got %q", token)
+			}
+			return []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/calendar/feeds/s3cret-token.ics", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+			t.Errorf("expected text/calendar, got %q", ct)
+		}
+	})
+
+	t.Run("RevokedToken", func(t *testing.T) {
+		mockService.RenderFeedFunc = func(ctx context.Context, token string) ([]byte, error) {
+			return nil, domain.ErrCalendarFeedNotFound
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/calendar/feeds/old-token.ics", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+}
@@ -3,7 +3,10 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/antigravity/backend/internal/middleware"
@@ -36,12 +39,55 @@ func (h *FocusHandler) StartFocus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.SquadID == uuid.Nil {
-		respondError(w, http.StatusBadRequest, "SQUAD_ID_REQUIRED", "squad_id is required")
+	result, err := h.service.StartFocus(r.Context(), userID, &req)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, result)
+}
+
+// StopFocus handles POST /api/v1/focus/stop
+func (h *FocusHandler) StopFocus(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
 		return
 	}
 
-	result, err := h.service.StartFocus(r.Context(), userID, req.SquadID)
+	// The body is optional: an empty one just stops the session
+	var req domain.StopFocusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	result, err := h.service.StopFocus(r.Context(), userID, &req)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// LogSession handles POST /api/v1/focus/sessions, backfilling a session
+// done offline
+func (h *FocusHandler) LogSession(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	var req domain.LogSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	result, err := h.service.LogSession(r.Context(), userID, &req)
 	if err != nil {
 		handleFocusError(w, err)
 		return
@@ -50,15 +96,110 @@ func (h *FocusHandler) StartFocus(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, result)
 }
 
-// StopFocus handles POST /api/v1/focus/stop
-func (h *FocusHandler) StopFocus(w http.ResponseWriter, r *http.Request) {
+// SetBroadcast handles PUT /api/v1/focus/broadcast, showing or hiding the
+// active solo session in all of the user's squads
+func (h *FocusHandler) SetBroadcast(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	var req domain.BroadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	if req.Broadcast == nil {
+		respondError(w, http.StatusBadRequest, "BROADCAST_REQUIRED", "broadcast is required")
+		return
+	}
+
+	result, err := h.service.SetBroadcast(r.Context(), userID, *req.Broadcast)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Heartbeat handles POST /api/v1/focus/heartbeat
+func (h *FocusHandler) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == uuid.Nil {
 		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
 		return
 	}
 
-	result, err := h.service.StopFocus(r.Context(), userID)
+	result, err := h.service.Heartbeat(r.Context(), userID)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// PauseFocus handles POST /api/v1/focus/pause
+func (h *FocusHandler) PauseFocus(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	result, err := h.service.PauseFocus(r.Context(), userID)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// ResumeFocus handles POST /api/v1/focus/resume
+func (h *FocusHandler) ResumeFocus(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	result, err := h.service.ResumeFocus(r.Context(), userID)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// SetOutcome handles POST /api/v1/focus/sessions/{sessionID}/outcome
+func (h *FocusHandler) SetOutcome(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SESSION_ID", "Invalid session ID format")
+		return
+	}
+
+	var req domain.FocusOutcomeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	if req.GoalMet == nil {
+		respondError(w, http.StatusBadRequest, "GOAL_MET_REQUIRED", "goal_met is required")
+		return
+	}
+
+	result, err := h.service.SetOutcome(r.Context(), userID, sessionID, *req.GoalMet)
 	if err != nil {
 		handleFocusError(w, err)
 		return
@@ -91,6 +232,7 @@ func (h *FocusHandler) GetActiveInSquad(w http.ResponseWriter, r *http.Request)
 }
 
 // GetFocusHistory handles GET /api/v1/focus/history/{squad_id}
+// Query: member_id, from, to (RFC 3339), min_minutes, limit, cursor
 func (h *FocusHandler) GetFocusHistory(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == uuid.Nil {
@@ -104,7 +246,195 @@ func (h *FocusHandler) GetFocusHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.GetFocusHistory(r.Context(), userID, squadID)
+	filter, limit, ok := parseHistoryQuery(w, r)
+	if !ok {
+		return
+	}
+	if memberID := r.URL.Query().Get("member_id"); memberID != "" {
+		id, err := uuid.Parse(memberID)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid member_id format")
+			return
+		}
+		filter.UserID = &id
+	}
+
+	result, err := h.service.GetFocusHistory(r.Context(), userID, squadID, filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// GetMyHistory handles GET /api/v1/focus/history
+// Query: squad_id, from, to (RFC 3339), min_minutes, limit, cursor
+func (h *FocusHandler) GetMyHistory(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	filter, limit, ok := parseHistoryQuery(w, r)
+	if !ok {
+		return
+	}
+	if squadID := r.URL.Query().Get("squad_id"); squadID != "" {
+		id, err := uuid.Parse(squadID)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+			return
+		}
+		filter.SquadID = &id
+	}
+
+	result, err := h.service.GetMyHistory(r.Context(), userID, filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// parseHistoryQuery reads the filters shared by both history views,
+// responding with 400 if one is malformed
+func parseHistoryQuery(w http.ResponseWriter, r *http.Request) (domain.FocusHistoryFilter, int, bool) {
+	query := r.URL.Query()
+	filter := domain.FocusHistoryFilter{Tags: query["tag"]}
+
+	from, to, ok := parseTimeRange(w, r)
+	if !ok {
+		return filter, 0, false
+	}
+	filter.From, filter.To = from, to
+
+	if value := query.Get("min_minutes"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "min_minutes must be an integer")
+			return filter, 0, false
+		}
+		filter.MinMinutes = minutes
+	}
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "limit must be an integer")
+			return filter, 0, false
+		}
+		limit = parsed
+	}
+
+	return filter, limit, true
+}
+
+// parseTimeRange reads the optional from and to query parameters,
+// responding with 400 if one isn't an RFC 3339 timestamp
+func parseTimeRange(w http.ResponseWriter, r *http.Request) (from, to *time.Time, ok bool) {
+	for _, bound := range []struct {
+		name string
+		dest **time.Time
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_REQUEST", bound.name+" must be an RFC 3339 timestamp")
+			return nil, nil, false
+		}
+		*bound.dest = &t
+	}
+	return from, to, true
+}
+
+// GetSubjectBreakdown handles GET /api/v1/focus/stats/subjects?from=...&to=...
+func (h *FocusHandler) GetSubjectBreakdown(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	from, to, ok := parseTimeRange(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetSubjectBreakdown(r.Context(), userID, from, to)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// GetMyTags handles GET /api/v1/focus/tags
+func (h *FocusHandler) GetMyTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	result, err := h.service.GetMyTags(r.Context(), userID)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// SetFavouriteTags handles PUT /api/v1/focus/tags/favourites
+func (h *FocusHandler) SetFavouriteTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	var req domain.SetFavouriteTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	result, err := h.service.SetFavouriteTags(r.Context(), userID, &req)
+	if err != nil {
+		handleFocusError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// GetMyStats handles GET /api/v1/focus/stats/me?period=week&count=12
+func (h *FocusHandler) GetMyStats(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	count := 0
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		parsed, err := strconv.Atoi(countStr)
+		if err != nil || parsed <= 0 {
+			respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "count must be a positive integer")
+			return
+		}
+		count = parsed
+	}
+
+	result, err := h.service.GetMyStats(r.Context(), userID, r.URL.Query().Get("period"), count)
 	if err != nil {
 		handleFocusError(w, err)
 		return
@@ -122,6 +452,42 @@ func handleFocusError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusNotFound, "NO_ACTIVE_SESSION", "No active focus session to stop")
 	case errors.Is(err, service.ErrAlreadyFocusing):
 		respondError(w, http.StatusConflict, "ALREADY_FOCUSING", "You already have an active focus session")
+	case errors.Is(err, service.ErrInvalidPomodoro):
+		respondError(w, http.StatusBadRequest, "INVALID_POMODORO", "Pomodoro lengths must be 1-180 min work, 1-60 min short break, 1-120 min long break and 1-12 cycles")
+	case errors.Is(err, service.ErrInvalidPlan):
+		respondError(w, http.StatusBadRequest, "INVALID_PLAN", "planned_minutes must be between 1 and 720")
+	case errors.Is(err, service.ErrInvalidGoal):
+		respondError(w, http.StatusBadRequest, "INVALID_GOAL", "goal must be at most 200 characters")
+	case errors.Is(err, service.ErrSessionNotFound):
+		respondError(w, http.StatusNotFound, "SESSION_NOT_FOUND", "Focus session not found")
+	case errors.Is(err, service.ErrSessionActive):
+		respondError(w, http.StatusConflict, "SESSION_ACTIVE", "Stop the session before recording its outcome")
+	case errors.Is(err, service.ErrAlreadyPaused):
+		respondError(w, http.StatusConflict, "ALREADY_PAUSED", "Your focus session is already paused")
+	case errors.Is(err, service.ErrNotPaused):
+		respondError(w, http.StatusConflict, "NOT_PAUSED", "Your focus session is not paused")
+	case errors.Is(err, service.ErrNoGoal):
+		respondError(w, http.StatusBadRequest, "NO_GOAL", "This session has no goal")
+	case errors.Is(err, service.ErrInvalidHistory):
+		respondError(w, http.StatusBadRequest, "INVALID_FILTER", "limit must be 1-100, min_minutes not negative and from before to")
+	case errors.Is(err, service.ErrInvalidCursor):
+		respondError(w, http.StatusBadRequest, "INVALID_CURSOR", "Invalid history cursor")
+	case errors.Is(err, service.ErrInvalidTags):
+		respondError(w, http.StatusBadRequest, "INVALID_TAGS", "Tags must be at most 32 letters, digits, spaces or -_+#./& each, with up to 5 per session and 20 favourites")
+	case errors.Is(err, service.ErrInvalidNote):
+		respondError(w, http.StatusBadRequest, "INVALID_NOTE", "note must be at most 1000 characters")
+	case errors.Is(err, service.ErrInvalidRange):
+		respondError(w, http.StatusBadRequest, "INVALID_RANGE", "from must be before to, at most a year apart")
+	case errors.Is(err, service.ErrNotSolo):
+		respondError(w, http.StatusBadRequest, "NOT_SOLO", "Only solo sessions (without a squad) can be broadcast")
+	case errors.Is(err, service.ErrInvalidBackfill):
+		respondError(w, http.StatusBadRequest, "INVALID_BACKFILL", "Backfilled sessions must last 1-720 minutes and have ended in the last 48 hours")
+	case errors.Is(err, domain.ErrSessionOverlap):
+		respondError(w, http.StatusConflict, "SESSION_OVERLAP", "This session overlaps another of your focus sessions")
+	case errors.Is(err, domain.ErrBackfillCapReached):
+		respondError(w, http.StatusConflict, "BACKFILL_LIMIT", "You've reached the daily limit for backfilled focus time")
+	case errors.Is(err, service.ErrInvalidStats):
+		respondError(w, http.StatusBadRequest, "INVALID_PERIOD", "period must be day, week or month, with count up to 90 days, 52 weeks or 24 months")
 	default:
 		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
 	}
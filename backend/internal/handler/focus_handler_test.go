@@ -28,19 +28,19 @@ func TestFocusHandler_StartFocus(t *testing.T) {
 		startedAt := time.Now()
 
 		reqBody := domain.StartFocusRequest{
-			SquadID: squadID,
+			SquadID: &squadID,
 		}
 		expectedResp := &domain.StartFocusResponse{
 			SessionID: sessionID,
 			StartedAt: startedAt,
 		}
 
-		mockService.StartFocusFunc = func(ctx context.Context, uid, sid uuid.UUID) (*domain.StartFocusResponse, error) {
+		mockService.StartFocusFunc = func(ctx context.Context, uid uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error) {
 			if uid != userID {
 				t.Errorf("expected userID %v, got %v", userID, uid)
 			}
-			if sid != squadID {
-				t.Errorf("expected squadID %v, got %v", squadID, sid)
+			if req.SquadID == nil || *req.SquadID != squadID {
+				t.Errorf("expected squadID %v, got %v", squadID, req.SquadID)
 			}
 			return expectedResp, nil
 		}
@@ -62,10 +62,10 @@ func TestFocusHandler_StartFocus(t *testing.T) {
         userID := uuid.New()
 		squadID := uuid.New()
 		reqBody := domain.StartFocusRequest{
-			SquadID: squadID,
+			SquadID: &squadID,
 		}
 
-        mockService.StartFocusFunc = func(ctx context.Context, uid, sid uuid.UUID) (*domain.StartFocusResponse, error) {
+        mockService.StartFocusFunc = func(ctx context.Context, uid uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error) {
 			return nil, service.ErrAlreadyFocusing
 		}
 
@@ -82,3 +82,322 @@ func TestFocusHandler_StartFocus(t *testing.T) {
 		}
     })
 }
+
+func TestFocusHandler_StopFocus(t *testing.T) {
+	mockService := &mocks.MockFocusService{}
+	h := handler.NewFocusHandler(mockService)
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		userID := uuid.New()
+
+		mockService.StopFocusFunc = func(ctx context.Context, uid uuid.UUID, req *domain.StopFocusRequest) (*domain.StopFocusResponse, error) {
+			if req.GoalMet != nil {
+				t.Errorf("expected no outcome, got %v", *req.GoalMet)
+			}
+			return &domain.StopFocusResponse{SessionID: uuid.New()}, nil
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/focus/stop", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		h.StopFocus(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("WithOutcome", func(t *testing.T) {
+		userID := uuid.New()
+
+		mockService.StopFocusFunc = func(ctx context.Context, uid uuid.UUID, req *domain.StopFocusRequest) (*domain.StopFocusResponse, error) {
+			if req.GoalMet == nil || !*req.GoalMet {
+				t.Errorf("expected goal_met true, got %v", req.GoalMet)
+			}
+			return &domain.StopFocusResponse{SessionID: uuid.New()}, nil
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/focus/stop", bytes.NewBufferString(`{"goal_met": true}`))
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		h.StopFocus(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+}
+
+func TestFocusHandler_GetMyStats(t *testing.T) {
+	mockService := &mocks.MockFocusService{}
+	h := handler.NewFocusHandler(mockService)
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.GetMyStatsFunc = func(ctx context.Context, uid uuid.UUID, period string, count int) (*domain.FocusStatsResponse, error) {
+			if period != "day" || count != 7 {
+				t.Errorf("expected day x 7, got %q x %d", period, count)
+			}
+			return &domain.FocusStatsResponse{Period: period}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/focus/stats/me?period=day&count=7", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.GetMyStats(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("InvalidPeriod", func(t *testing.T) {
+		mockService.GetMyStatsFunc = func(ctx context.Context, uid uuid.UUID, period string, count int) (*domain.FocusStatsResponse, error) {
+			return nil, service.ErrInvalidStats
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/focus/stats/me?period=year", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.GetMyStats(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("InvalidCount", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/focus/stats/me?count=lots", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.GetMyStats(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestFocusHandler_GetMyHistory(t *testing.T) {
+	mockService := &mocks.MockFocusService{}
+	h := handler.NewFocusHandler(mockService)
+	userID := uuid.New()
+
+	t.Run("Filters", func(t *testing.T) {
+		squadID := uuid.New()
+		mockService.GetMyHistoryFunc = func(ctx context.Context, uid uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
+			if filter.SquadID == nil || *filter.SquadID != squadID {
+				t.Errorf("expected squad filter %s, got %v", squadID, filter.SquadID)
+			}
+			if filter.From == nil || !filter.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("expected from 2026-03-01, got %v", filter.From)
+			}
+			if filter.MinMinutes != 25 || cursor != "abc" || limit != 20 {
+				t.Errorf("expected min 25, cursor abc, limit 20, got %d, %q, %d", filter.MinMinutes, cursor, limit)
+			}
+			return &domain.FocusHistoryResponse{History: []domain.FocusHistory{}}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/focus/history?squad_id="+squadID.String()+"&from=2026-03-01T00:00:00Z&min_minutes=25&cursor=abc&limit=20", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.GetMyHistory(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("InvalidFrom", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/focus/history?from=yesterday", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.GetMyHistory(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		mockService.GetMyHistoryFunc = func(ctx context.Context, uid uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
+			return nil, service.ErrInvalidCursor
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/focus/history?cursor=garbage", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.GetMyHistory(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestFocusHandler_SetFavouriteTags(t *testing.T) {
+	mockService := &mocks.MockFocusService{}
+	h := handler.NewFocusHandler(mockService)
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.SetFavouriteTagsFunc = func(ctx context.Context, uid uuid.UUID, req *domain.SetFavouriteTagsRequest) (*domain.FocusTagsResponse, error) {
+			if len(req.Tags) != 2 {
+				t.Errorf("expected 2 tags, got %v", req.Tags)
+			}
+			return &domain.FocusTagsResponse{Favourites: req.Tags, Recent: []domain.FocusTagUsage{}}, nil
+		}
+
+		req := httptest.NewRequest("PUT", "/api/v1/focus/tags/favourites", bytes.NewBufferString(`{"tags": ["calculus", "physics"]}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.SetFavouriteTags(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("InvalidTags", func(t *testing.T) {
+		mockService.SetFavouriteTagsFunc = func(ctx context.Context, uid uuid.UUID, req *domain.SetFavouriteTagsRequest) (*domain.FocusTagsResponse, error) {
+			return nil, service.ErrInvalidTags
+		}
+
+		req := httptest.NewRequest("PUT", "/api/v1/focus/tags/favourites", bytes.NewBufferString(`{"tags": ["a,b"]}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.SetFavouriteTags(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestFocusHandler_LogSession(t *testing.T) {
+	mockService := &mocks.MockFocusService{}
+	h := handler.NewFocusHandler(mockService)
+	userID := uuid.New()
+	squadID := uuid.New()
+
+	body := `{"squad_id": "` + squadID.String() + `", "started_at": "2026-03-10T14:00:00Z", "ended_at": "2026-03-10T15:30:00Z", "tags": ["chemistry"]}`
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.LogSessionFunc = func(ctx context.Context, uid uuid.UUID, req *domain.LogSessionRequest) (*domain.FocusSession, error) {
+			if req.SquadID == nil || *req.SquadID != squadID || req.EndedAt.Sub(req.StartedAt) != 90*time.Minute {
+				t.Errorf("unexpected request %+v", req)
+			}
+			return &domain.FocusSession{ID: uuid.New(), SquadID: &squadID, Manual: true}, nil
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/focus/sessions", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.LogSession(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("expected status 201, got %d", w.Code)
+		}
+	})
+
+	t.Run("Overlap", func(t *testing.T) {
+		mockService.LogSessionFunc = func(ctx context.Context, uid uuid.UUID, req *domain.LogSessionRequest) (*domain.FocusSession, error) {
+			return nil, domain.ErrSessionOverlap
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/focus/sessions", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.LogSession(w, req)
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("Solo", func(t *testing.T) {
+		mockService.LogSessionFunc = func(ctx context.Context, uid uuid.UUID, req *domain.LogSessionRequest) (*domain.FocusSession, error) {
+			if req.SquadID != nil {
+				t.Errorf("expected a solo session, got squad %v", req.SquadID)
+			}
+			return &domain.FocusSession{ID: uuid.New(), Manual: true}, nil
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/focus/sessions", bytes.NewBufferString(`{"started_at": "2026-03-10T14:00:00Z", "ended_at": "2026-03-10T15:30:00Z"}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.LogSession(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("expected status 201, got %d", w.Code)
+		}
+	})
+}
+
+func TestFocusHandler_SetBroadcast(t *testing.T) {
+	mockService := &mocks.MockFocusService{}
+	h := handler.NewFocusHandler(mockService)
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.SetBroadcastFunc = func(ctx context.Context, uid uuid.UUID, broadcast bool) (*domain.FocusSession, error) {
+			if !broadcast {
+				t.Error("expected broadcast to be turned on")
+			}
+			return &domain.FocusSession{ID: uuid.New(), UserID: uid, Broadcast: true}, nil
+		}
+
+		req := httptest.NewRequest("PUT", "/api/v1/focus/broadcast", bytes.NewBufferString(`{"broadcast": true}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.SetBroadcast(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("SquadSession", func(t *testing.T) {
+		mockService.SetBroadcastFunc = func(ctx context.Context, uid uuid.UUID, broadcast bool) (*domain.FocusSession, error) {
+			return nil, service.ErrNotSolo
+		}
+
+		req := httptest.NewRequest("PUT", "/api/v1/focus/broadcast", bytes.NewBufferString(`{"broadcast": true}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.SetBroadcast(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("MissingField", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/api/v1/focus/broadcast", bytes.NewBufferString(`{}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+		w := httptest.NewRecorder()
+		h.SetBroadcast(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
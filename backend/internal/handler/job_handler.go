@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// JobHandler handles admin HTTP requests for background jobs
+type JobHandler struct {
+	service domain.SchedulerService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(service domain.SchedulerService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+// ListJobs handles GET /api/v1/admin/jobs
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.service.ListJobs(r.Context())
+	if err != nil {
+		handleJobError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, domain.JobsResponse{Jobs: jobs})
+}
+
+// GetJobRuns handles GET /api/v1/admin/jobs/{name}/runs
+func (h *JobHandler) GetJobRuns(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	runs, err := h.service.GetJobRuns(r.Context(), chi.URLParam(r, "name"), limit)
+	if err != nil {
+		handleJobError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, domain.JobRunsResponse{Runs: runs})
+}
+
+// PauseJob handles POST /api/v1/admin/jobs/{name}/pause
+func (h *JobHandler) PauseJob(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.PauseJob(r.Context(), chi.URLParam(r, "name")); err != nil {
+		handleJobError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "paused"})
+}
+
+// ResumeJob handles POST /api/v1/admin/jobs/{name}/resume
+func (h *JobHandler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.ResumeJob(r.Context(), chi.URLParam(r, "name")); err != nil {
+		handleJobError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "active"})
+}
+
+// TriggerJob handles POST /api/v1/admin/jobs/{name}/trigger
+func (h *JobHandler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.TriggerJob(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		handleJobError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, run)
+}
+
+// handleJobError maps scheduler errors to HTTP responses
+func handleJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound):
+		respondError(w, http.StatusNotFound, "JOB_NOT_FOUND", "Job not found")
+	case errors.Is(err, domain.ErrJobAlreadyRunning):
+		respondError(w, http.StatusConflict, "JOB_RUNNING", "Job is already running")
+	default:
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
+	}
+}
@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/handler"
+	"github.com/antigravity/backend/internal/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestJobHandler_TriggerJob(t *testing.T) {
+	mockService := &mocks.MockSchedulerService{}
+	h := handler.NewJobHandler(mockService)
+
+	r := chi.NewRouter()
+	r.Post("/api/v1/admin/jobs/{name}/trigger", h.TriggerJob)
+
+	t.Run("Accepted", func(t *testing.T) {
+		mockService.TriggerJobFunc = func(ctx context.Context, name string) (*domain.JobRun, error) {
+			if name != "streak_recalculation" {
+				t.Errorf("expected job streak_recalculation, got %s", name)
+			}
+			return &domain.JobRun{ID: uuid.New(), JobName: name, Status: domain.JobRunStatusRunning}, nil
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/admin/jobs/streak_recalculation/trigger", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusAccepted {
+			t.Errorf("expected status 202, got %d", w.Code)
+		}
+	})
+
+	t.Run("AlreadyRunning", func(t *testing.T) {
+		mockService.TriggerJobFunc = func(ctx context.Context, name string) (*domain.JobRun, error) {
+			return nil, domain.ErrJobAlreadyRunning
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/admin/jobs/streak_recalculation/trigger", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockService.TriggerJobFunc = func(ctx context.Context, name string) (*domain.JobRun, error) {
+			return nil, domain.ErrJobNotFound
+		}
+
+		req := httptest.NewRequest("POST", "/api/v1/admin/jobs/unknown/trigger", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+}
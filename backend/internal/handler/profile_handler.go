@@ -63,6 +63,10 @@ func (h *ProfileHandler) UpdateMyProfile(w http.ResponseWriter, r *http.Request)
 			respondError(w, http.StatusNotFound, "PROFILE_NOT_FOUND", "Profile not found")
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidTimezone) {
+			respondError(w, http.StatusBadRequest, "INVALID_TIMEZONE", "Timezone must be a valid IANA name, e.g. Asia/Kolkata")
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update profile")
 		return
 	}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/antigravity/backend/internal/middleware"
+	"github.com/antigravity/backend/internal/room"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// RoomHandler handles WebSocket connections to live squad rooms
+type RoomHandler struct {
+	hub      *room.Hub
+	upgrader websocket.Upgrader
+}
+
+// NewRoomHandler creates a new room handler. Browser connections are only
+// accepted from allowedOrigins.
+func NewRoomHandler(hub *room.Hub, allowedOrigins []string) *RoomHandler {
+	return &RoomHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Only the protocol is echoed, never the bearer.<jwt> entry
+			Subprotocols: []string{room.Subprotocol},
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || slices.Contains(allowedOrigins, origin)
+			},
+		},
+	}
+}
+
+// SquadRoom handles GET /api/v1/squads/{squadID}/room (WebSocket)
+func (h *RoomHandler) SquadRoom(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	squadID, err := uuid.Parse(chi.URLParam(r, "squadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+		return
+	}
+
+	if !slices.Contains(websocket.Subprotocols(r), room.Subprotocol) {
+		respondError(w, http.StatusBadRequest, "UNSUPPORTED_PROTOCOL", "Request the "+room.Subprotocol+" subprotocol")
+		return
+	}
+
+	isMember, err := h.hub.IsMember(r.Context(), squadID, userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
+		return
+	}
+	if !isMember {
+		respondError(w, http.StatusForbidden, "NOT_MEMBER", "You are not a member of this squad")
+		return
+	}
+
+	// Upgrade writes its own error response
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	h.hub.Serve(conn, userID, squadID)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/antigravity/backend/internal/middleware"
@@ -86,6 +87,25 @@ func (h *SquadHandler) GetSquadDetail(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, detail)
 }
 
+// GetSquadLeaderboard handles GET /api/v1/squads/leaderboard
+// Returns squads ranked by their current squad streak
+func (h *SquadHandler) GetSquadLeaderboard(w http.ResponseWriter, r *http.Request) {
+	limit := 50 // Default
+	if parsedLimit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsedLimit > 0 {
+		limit = parsedLimit
+	}
+
+	leaderboard, err := h.service.GetSquadLeaderboard(r.Context(), limit)
+	if err != nil {
+		handleSquadError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"leaderboard": leaderboard,
+	})
+}
+
 // UpdateSquad handles PATCH /api/v1/squads/{squadID}
 func (h *SquadHandler) UpdateSquad(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
@@ -216,6 +236,75 @@ func (h *SquadHandler) RegenerateCode(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{"invite_code": newCode})
 }
 
+// UpdateMemberRole handles PUT /api/v1/squads/{squadID}/members/{userID}/role
+// Promotes a member to admin or demotes an admin to member
+func (h *SquadHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	callerID := middleware.GetUserID(r.Context())
+	if callerID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	squadID, err := uuid.Parse(chi.URLParam(r, "squadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+		return
+	}
+
+	targetUserID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_USER_ID", "Invalid user ID format")
+		return
+	}
+
+	var req domain.UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateMemberRole(r.Context(), squadID, targetUserID, callerID, req.Role); err != nil {
+		handleSquadError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"user_id": targetUserID.String(), "role": string(req.Role)})
+}
+
+// TransferOwnership handles POST /api/v1/squads/{squadID}/transfer-ownership
+// Hands the squad to another member; the caller stays on as an admin
+func (h *SquadHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	squadID, err := uuid.Parse(chi.URLParam(r, "squadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+		return
+	}
+
+	var req domain.TransferOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	if req.UserID == uuid.Nil {
+		respondError(w, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		return
+	}
+
+	squad, err := h.service.TransferOwnership(r.Context(), squadID, userID, req.UserID)
+	if err != nil {
+		handleSquadError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, squad)
+}
+
 // handleSquadError maps domain errors to HTTP responses
 func handleSquadError(w http.ResponseWriter, err error) {
 	switch {
@@ -223,6 +312,8 @@ func handleSquadError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusNotFound, "SQUAD_NOT_FOUND", "Squad not found")
 	case errors.Is(err, domain.ErrNotSquadOwner):
 		respondError(w, http.StatusForbidden, "NOT_OWNER", "Only squad owner can perform this action")
+	case errors.Is(err, domain.ErrSquadRoleForbidden):
+		respondError(w, http.StatusForbidden, "ROLE_FORBIDDEN", "Your squad role does not allow this action")
 	case errors.Is(err, domain.ErrNotSquadMember):
 		respondError(w, http.StatusForbidden, "NOT_MEMBER", "You are not a member of this squad")
 	case errors.Is(err, domain.ErrSquadFull):
@@ -233,10 +324,18 @@ func handleSquadError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusBadRequest, "INVALID_INVITE_CODE", "Invalid invite code")
 	case errors.Is(err, domain.ErrCannotKickOwner):
 		respondError(w, http.StatusForbidden, "CANNOT_KICK_OWNER", "Cannot kick squad owner")
+	case errors.Is(err, domain.ErrInvalidSquadRole):
+		respondError(w, http.StatusBadRequest, "INVALID_ROLE", "Role must be admin or member")
+	case errors.Is(err, domain.ErrCannotChangeOwnerRole):
+		respondError(w, http.StatusForbidden, "CANNOT_CHANGE_OWNER_ROLE", "The owner's role cannot be changed")
+	case errors.Is(err, domain.ErrAlreadySquadOwner):
+		respondError(w, http.StatusBadRequest, "ALREADY_OWNER", "You already own this squad")
 	case errors.Is(err, domain.ErrSquadNameRequired):
 		respondError(w, http.StatusBadRequest, "NAME_REQUIRED", "Squad name is required")
 	case errors.Is(err, domain.ErrSquadNameTooLong):
 		respondError(w, http.StatusBadRequest, "NAME_TOO_LONG", "Squad name must be 50 characters or less")
+	case errors.Is(err, domain.ErrInvalidStreakQuorum):
+		respondError(w, http.StatusBadRequest, "INVALID_STREAK_QUORUM", err.Error())
 	default:
 		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/antigravity/backend/internal/handler"
 	"github.com/antigravity/backend/internal/middleware"
 	"github.com/antigravity/backend/internal/mocks"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -83,3 +84,125 @@ func TestSquadHandler_CreateSquad(t *testing.T) {
 		}
 	})
 }
+
+func TestSquadHandler_UpdateMemberRole(t *testing.T) {
+	mockService := &mocks.MockSquadService{}
+	h := handler.NewSquadHandler(mockService)
+	callerID := uuid.New()
+	squadID := uuid.New()
+	targetID := uuid.New()
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("PUT", "/api/v1/squads/"+squadID.String()+"/members/"+targetID.String()+"/role", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("squadID", squadID.String())
+		rctx.URLParams.Add("userID", targetID.String())
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, middleware.UserIDKey, callerID)
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Promote", func(t *testing.T) {
+		mockService.UpdateMemberRoleFunc = func(ctx context.Context, sid, target, caller uuid.UUID, role domain.SquadRole) error {
+			if sid != squadID || target != targetID || caller != callerID || role != domain.SquadRoleAdmin {
+				t.Errorf("unexpected call %v %v %v %q", sid, target, caller, role)
+			}
+			return nil
+		}
+
+		w := httptest.NewRecorder()
+		h.UpdateMemberRole(w, newRequest(`{"role": "admin"}`))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		mockService.UpdateMemberRoleFunc = func(ctx context.Context, sid, target, caller uuid.UUID, role domain.SquadRole) error {
+			return domain.ErrNotSquadOwner
+		}
+
+		w := httptest.NewRecorder()
+		h.UpdateMemberRole(w, newRequest(`{"role": "member"}`))
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		mockService.UpdateMemberRoleFunc = func(ctx context.Context, sid, target, caller uuid.UUID, role domain.SquadRole) error {
+			return domain.ErrInvalidSquadRole
+		}
+
+		w := httptest.NewRecorder()
+		h.UpdateMemberRole(w, newRequest(`{"role": "owner"}`))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestSquadHandler_TransferOwnership(t *testing.T) {
+	mockService := &mocks.MockSquadService{}
+	h := handler.NewSquadHandler(mockService)
+	callerID := uuid.New()
+	squadID := uuid.New()
+	newOwnerID := uuid.New()
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/v1/squads/"+squadID.String()+"/transfer-ownership", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("squadID", squadID.String())
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, middleware.UserIDKey, callerID)
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.TransferOwnershipFunc = func(ctx context.Context, sid, caller, target uuid.UUID) (*domain.Squad, error) {
+			if sid != squadID || caller != callerID || target != newOwnerID {
+				t.Errorf("unexpected call %v %v %v", sid, caller, target)
+			}
+			return &domain.Squad{ID: squadID, OwnerID: newOwnerID}, nil
+		}
+
+		w := httptest.NewRecorder()
+		h.TransferOwnership(w, newRequest(`{"user_id": "`+newOwnerID.String()+`"}`))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		var resp domain.Squad
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.OwnerID != newOwnerID {
+			t.Errorf("expected owner %v, got %v", newOwnerID, resp.OwnerID)
+		}
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		mockService.TransferOwnershipFunc = func(ctx context.Context, sid, caller, target uuid.UUID) (*domain.Squad, error) {
+			return nil, domain.ErrNotSquadOwner
+		}
+
+		w := httptest.NewRecorder()
+		h.TransferOwnership(w, newRequest(`{"user_id": "`+newOwnerID.String()+`"}`))
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("MissingUser", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.TransferOwnership(w, newRequest(`{}`))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -76,6 +76,30 @@ func (h *StreakHandler) GetMyStreak(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, streakData)
 }
 
+// SetStreakMode handles PUT /api/v1/streaks/me/mode
+// Switches between "daily" and "weekly:N" streaks and returns the recomputed streak
+func (h *StreakHandler) SetStreakMode(w http.ResponseWriter, r *http.Request) {
+	userIDStr := middleware.GetUserIDString(r.Context())
+	if userIDStr == "" {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	var req domain.SetStreakModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	streakData, err := h.service.SetStreakMode(r.Context(), userIDStr, &req)
+	if err != nil {
+		handleStreakError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, streakData)
+}
+
 // GetUserStreak handles GET /api/v1/streaks/{user_id}
 // Returns another user's public streak data (no history)
 func (h *StreakHandler) GetUserStreak(w http.ResponseWriter, r *http.Request) {
@@ -95,7 +119,7 @@ func (h *StreakHandler) GetUserStreak(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetLeaderboard handles GET /api/v1/streaks/leaderboard
-// Returns top users by current streak
+// Returns top users by current streak (?mode=daily|weekly, default daily)
 func (h *StreakHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	// Parse optional limit query parameter
 	limitStr := r.URL.Query().Get("limit")
@@ -106,7 +130,7 @@ func (h *StreakHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	leaderboard, err := h.service.GetLeaderboard(r.Context(), limit)
+	leaderboard, err := h.service.GetLeaderboard(r.Context(), limit, r.URL.Query().Get("mode"))
 	if err != nil {
 		handleStreakError(w, err)
 		return
@@ -119,23 +143,22 @@ func (h *StreakHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response)
 }
 
-// TriggerRecalculation handles POST /api/v1/streaks/calculate
-// Triggers streak recalculation for all users (admin/cron only)
-func (h *StreakHandler) TriggerRecalculation(w http.ResponseWriter, r *http.Request) {
-	// This endpoint should be protected by service role middleware
-	// Only accessible via API key or cron job
-	err := h.service.TriggerRecalculation(r.Context())
-	if err != nil {
-		handleStreakError(w, err)
+// GrantFreezeTokens handles POST /api/v1/admin/streaks/{userID}/freezes
+// Gives a user streak freeze tokens (admin only)
+func (h *StreakHandler) GrantFreezeTokens(w http.ResponseWriter, r *http.Request) {
+	var req domain.GrantFreezeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
 		return
 	}
 
-	response := map[string]interface{}{
-		"success": true,
-		"message": "Streak recalculation triggered successfully",
+	result, err := h.service.GrantFreezeTokens(r.Context(), chi.URLParam(r, "userID"), &req)
+	if err != nil {
+		handleStreakError(w, err)
+		return
 	}
 
-	respondJSON(w, http.StatusOK, response)
+	respondJSON(w, http.StatusOK, result)
 }
 
 // handleStreakError maps streak-specific errors to HTTP responses
@@ -143,6 +166,12 @@ func handleStreakError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, service.ErrInvalidActivityType):
 		respondError(w, http.StatusBadRequest, "INVALID_ACTIVITY_TYPE", err.Error())
+	case errors.Is(err, domain.ErrInvalidStreakMode):
+		respondError(w, http.StatusBadRequest, "INVALID_STREAK_MODE", err.Error())
+	case errors.Is(err, domain.ErrInvalidFreezeCount):
+		respondError(w, http.StatusBadRequest, "INVALID_FREEZE_COUNT", err.Error())
+	case errors.Is(err, domain.ErrProfileNotFound):
+		respondError(w, http.StatusNotFound, "PROFILE_NOT_FOUND", "Profile not found")
 	default:
 		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
 	}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/middleware"
+	"github.com/antigravity/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// streamKeepAlive is how often an idle stream sends a comment so proxies
+// don't drop the connection
+const streamKeepAlive = 15 * time.Second
+
+// StreamHandler handles real-time Server-Sent Event streams
+type StreamHandler struct {
+	service   domain.SquadStreamService
+	jwtSecret string // Signs stream tickets
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(service domain.SquadStreamService, jwtSecret string) *StreamHandler {
+	return &StreamHandler{service: service, jwtSecret: jwtSecret}
+}
+
+// CreateTicket handles POST /api/v1/stream/tickets
+// Issues a short-lived ticket for opening streams with a native
+// EventSource, which can't send the Authorization header
+func (h *StreamHandler) CreateTicket(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	ticket, expiresAt, err := middleware.IssueStreamTicket(h.jwtSecret, userID, time.Now())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, domain.StreamTicket{Ticket: ticket, ExpiresAt: expiresAt})
+}
+
+// SquadStream handles GET /api/v1/squads/{squadID}/stream
+func (h *StreamHandler) SquadStream(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	squadID, err := uuid.Parse(chi.URLParam(r, "squadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "STREAMING_UNSUPPORTED", "Streaming is not supported")
+		return
+	}
+
+	events, err := h.service.Subscribe(r.Context(), userID, squadID, lastEventID(r))
+	if err != nil {
+		handleStreamError(w, err)
+		return
+	}
+
+	serveStream(w, r, flusher, events)
+}
+
+// MyStream handles GET /api/v1/profile/me/stream
+// Streams the user's personal events, e.g. streak updates after a focus
+// session is credited
+func (h *StreamHandler) MyStream(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "STREAMING_UNSUPPORTED", "Streaming is not supported")
+		return
+	}
+
+	events, err := h.service.SubscribeUser(r.Context(), userID, lastEventID(r))
+	if err != nil {
+		handleStreamError(w, err)
+		return
+	}
+
+	serveStream(w, r, flusher, events)
+}
+
+// serveStream writes events as Server-Sent Events until the client goes
+// away or a final event is sent
+func serveStream(w http.ResponseWriter, r *http.Request, flusher http.Flusher, events <-chan domain.SquadStreamEvent) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event := <-events:
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+			flusher.Flush()
+			if event.Final {
+				return
+			}
+		}
+	}
+}
+
+// lastEventID is where a client resumes its stream. EventSource sends the
+// header on its own reconnects; a client opening a new EventSource (e.g.
+// with a fresh ticket) passes it as ?last_event_id=.
+func lastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("last_event_id")
+}
+
+// handleStreamError maps stream errors to HTTP responses
+func handleStreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotSquadMember):
+		respondError(w, http.StatusForbidden, "NOT_MEMBER", "You are not a member of this squad")
+	case errors.Is(err, service.ErrStreamUnavailable):
+		respondError(w, http.StatusServiceUnavailable, "STREAM_UNAVAILABLE", "Real-time updates are unavailable, poll instead")
+	default:
+		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
+	}
+}
@@ -0,0 +1,234 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/handler"
+	"github.com/antigravity/backend/internal/middleware"
+	"github.com/antigravity/backend/internal/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const testJWTSecret = "test-jwt-secret"
+
+func TestStreamHandler_SquadStream(t *testing.T) {
+	mockService := &mocks.MockSquadStreamService{}
+	h := handler.NewStreamHandler(mockService, testJWTSecret)
+
+	newRequest := func(userID, squadID uuid.UUID, lastEventID string) *http.Request {
+		req := httptest.NewRequest("GET", "/api/v1/squads/"+squadID.String()+"/stream", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("squadID", squadID.String())
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+		return req.WithContext(ctx)
+	}
+
+	t.Run("StreamsUntilFinal", func(t *testing.T) {
+		userID := uuid.New()
+		squadID := uuid.New()
+
+		mockService.SubscribeFunc = func(ctx context.Context, uid, sid uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+			if lastEventID != "41" {
+				t.Errorf("expected Last-Event-ID 41, got %q", lastEventID)
+			}
+			events := make(chan domain.SquadStreamEvent, 2)
+			events <- domain.SquadStreamEvent{ID: "42", Type: "focus.started", Data: json.RawMessage(`{"type":"focus.started"}`)}
+			events <- domain.SquadStreamEvent{ID: "43", Type: "member.left", Data: json.RawMessage(`{"type":"member.left"}`), Final: true}
+			return events, nil
+		}
+
+		w := httptest.NewRecorder()
+		h.SquadStream(w, newRequest(userID, squadID, "41"))
+
+		if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("expected text/event-stream, got %q", got)
+		}
+		body := w.Body.String()
+		for _, want := range []string{
+			"id: 42\nevent: focus.started\ndata: {\"type\":\"focus.started\"}\n\n",
+			"id: 43\nevent: member.left\n",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected body to contain %q, got %q", want, body)
+			}
+		}
+	})
+
+	t.Run("NotMember", func(t *testing.T) {
+		mockService.SubscribeFunc = func(ctx context.Context, uid, sid uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+			return nil, domain.ErrNotSquadMember
+		}
+
+		w := httptest.NewRecorder()
+		h.SquadStream(w, newRequest(uuid.New(), uuid.New(), ""))
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", w.Code)
+		}
+	})
+}
+
+func TestStreamHandler_MyStream(t *testing.T) {
+	mockService := &mocks.MockSquadStreamService{}
+	h := handler.NewStreamHandler(mockService, testJWTSecret)
+	userID := uuid.New()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middleware.UserIDKey, userID))
+	defer cancel()
+
+	mockService.SubscribeUserFunc = func(ctx context.Context, uid uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+		if uid != userID {
+			t.Errorf("expected user %v, got %v", userID, uid)
+		}
+		events := make(chan domain.SquadStreamEvent, 1)
+		events <- domain.SquadStreamEvent{ID: "7", Type: "streak.updated", Data: json.RawMessage(`{"current_streak":4}`)}
+		// The personal stream has no final event; the client disconnects
+		go func() {
+			for len(events) > 0 {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+		return events, nil
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/profile/me/stream", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	h.MyStream(w, req)
+
+	want := "id: 7\nevent: streak.updated\ndata: {\"current_streak\":4}\n\n"
+	if body := w.Body.String(); !strings.Contains(body, want) {
+		t.Errorf("expected body to contain %q, got %q", want, body)
+	}
+}
+
+func TestStreamHandler_ReconnectAcrossOrigins(t *testing.T) {
+	mockService := &mocks.MockSquadStreamService{}
+	h := handler.NewStreamHandler(mockService, testJWTSecret)
+	userID := uuid.New()
+	squadID := uuid.New()
+	origin := "https://app.example.com"
+
+	r := chi.NewRouter()
+	r.Use(middleware.CORSMiddleware([]string{origin}))
+	r.Get("/api/v1/squads/{squadID}/stream", func(w http.ResponseWriter, req *http.Request) {
+		h.SquadStream(w, req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID)))
+	})
+	path := "/api/v1/squads/" + squadID.String() + "/stream"
+
+	// A fetch-based SSE client sends Authorization and Last-Event-ID, so
+	// the browser asks first
+	preflight := httptest.NewRequest("OPTIONS", path, nil)
+	preflight.Header.Set("Origin", origin)
+	preflight.Header.Set("Access-Control-Request-Method", "GET")
+	preflight.Header.Set("Access-Control-Request-Headers", "authorization,last-event-id")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, preflight)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+		t.Fatalf("expected preflight to allow %s, got %q", origin, got)
+	}
+	if got := strings.ToLower(w.Header().Get("Access-Control-Allow-Headers")); !strings.Contains(got, "last-event-id") {
+		t.Fatalf("expected Last-Event-ID to be allowed, got %q", got)
+	}
+
+	mockService.SubscribeFunc = func(ctx context.Context, uid, sid uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+		if lastEventID != "41" {
+			t.Errorf("expected to resume after 41, got %q", lastEventID)
+		}
+		events := make(chan domain.SquadStreamEvent, 1)
+		events <- domain.SquadStreamEvent{ID: "43", Type: "member.left", Data: json.RawMessage(`{"type":"member.left"}`), Final: true}
+		return events, nil
+	}
+
+	reconnect := httptest.NewRequest("GET", path, nil)
+	reconnect.Header.Set("Origin", origin)
+	reconnect.Header.Set("Last-Event-ID", "41")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, reconnect)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+		t.Errorf("expected stream to allow %s, got %q", origin, got)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "id: 43\n") {
+		t.Errorf("expected the resumed event, got %q", body)
+	}
+}
+
+func TestStreamHandler_TicketOpensEventSourceStream(t *testing.T) {
+	mockService := &mocks.MockSquadStreamService{}
+	h := handler.NewStreamHandler(mockService, testJWTSecret)
+	userID := uuid.New()
+	squadID := uuid.New()
+
+	// The client fetches a ticket with its session token...
+	req := httptest.NewRequest("POST", "/api/v1/stream/tickets", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	w := httptest.NewRecorder()
+	h.CreateTicket(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", w.Code)
+	}
+	var ticket domain.StreamTicket
+	if err := json.NewDecoder(w.Body).Decode(&ticket); err != nil || ticket.Ticket == "" {
+		t.Fatalf("expected a ticket, got %q (%v)", w.Body.String(), err)
+	}
+
+	// ...then opens an EventSource, which can't send headers
+	r := chi.NewRouter()
+	r.With(middleware.StreamAuthMiddleware(testJWTSecret)).Get("/api/v1/squads/{squadID}/stream", h.SquadStream)
+	r.With(middleware.AuthMiddleware(testJWTSecret)).Get("/api/v1/squads", func(w http.ResponseWriter, r *http.Request) {})
+
+	mockService.SubscribeFunc = func(ctx context.Context, uid, sid uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+		if uid != userID {
+			t.Errorf("expected user %v, got %v", userID, uid)
+		}
+		if lastEventID != "41" {
+			t.Errorf("expected to resume after 41, got %q", lastEventID)
+		}
+		events := make(chan domain.SquadStreamEvent, 1)
+		events <- domain.SquadStreamEvent{ID: "43", Type: "member.left", Data: json.RawMessage(`{"type":"member.left"}`), Final: true}
+		return events, nil
+	}
+
+	path := "/api/v1/squads/" + squadID.String() + "/stream"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path+"?ticket="+ticket.Ticket+"&last_event_id=41", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, "id: 43\n") {
+		t.Errorf("expected the streamed event, got %q", body)
+	}
+
+	t.Run("RejectsBadTicket", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path+"?ticket="+ticket.Ticket+"x", nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("TicketIsNotASessionToken", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/squads", nil)
+		req.Header.Set("Authorization", "Bearer "+ticket.Ticket)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", w.Code)
+		}
+	})
+}
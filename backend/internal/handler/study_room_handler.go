@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// StudyRoomHandler handles HTTP requests for scheduled study rooms
+type StudyRoomHandler struct {
+	service domain.StudyRoomService
+}
+
+// NewStudyRoomHandler creates a new study room handler
+func NewStudyRoomHandler(service domain.StudyRoomService) *StudyRoomHandler {
+	return &StudyRoomHandler{service: service}
+}
+
+// CreateRoom handles POST /api/v1/squads/{squadID}/study-rooms
+func (h *StudyRoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	squadID, err := uuid.Parse(chi.URLParam(r, "squadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+		return
+	}
+
+	var req domain.CreateStudyRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	room, err := h.service.CreateRoom(r.Context(), userID, squadID, &req)
+	if err != nil {
+		handleStudyRoomError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, room)
+}
+
+// ListRooms handles GET /api/v1/squads/{squadID}/study-rooms
+func (h *StudyRoomHandler) ListRooms(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return
+	}
+
+	squadID, err := uuid.Parse(chi.URLParam(r, "squadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_SQUAD_ID", "Invalid squad ID format")
+		return
+	}
+
+	rooms, err := h.service.ListRooms(r.Context(), userID, squadID)
+	if err != nil {
+		handleStudyRoomError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": rooms,
+	})
+}
+
+// GetRoom handles GET /api/v1/study-rooms/{roomID}
+func (h *StudyRoomHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
+	userID, roomID, ok := parseStudyRoomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	room, err := h.service.GetRoom(r.Context(), userID, roomID)
+	if err != nil {
+		handleStudyRoomError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, room)
+}
+
+// RSVP handles PUT /api/v1/study-rooms/{roomID}/rsvp
+func (h *StudyRoomHandler) RSVP(w http.ResponseWriter, r *http.Request) {
+	userID, roomID, ok := parseStudyRoomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.RSVPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	room, err := h.service.RSVP(r.Context(), userID, roomID, req.Status)
+	if err != nil {
+		handleStudyRoomError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, room)
+}
+
+// JoinRoom handles POST /api/v1/study-rooms/{roomID}/join
+func (h *StudyRoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
+	userID, roomID, ok := parseStudyRoomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.JoinRoom(r.Context(), userID, roomID)
+	if err != nil {
+		handleStudyRoomError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, result)
+}
+
+// CancelRoom handles DELETE /api/v1/study-rooms/{roomID}
+func (h *StudyRoomHandler) CancelRoom(w http.ResponseWriter, r *http.Request) {
+	userID, roomID, ok := parseStudyRoomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelRoom(r.Context(), userID, roomID); err != nil {
+		handleStudyRoomError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseStudyRoomRequest reads the caller and the room ID, responding with
+// an error if either is missing
+func parseStudyRoomRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid token")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	roomID, err := uuid.Parse(chi.URLParam(r, "roomID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ROOM_ID", "Invalid study room ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, roomID, true
+}
+
+func handleStudyRoomError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrStudyRoomNotFound):
+		respondError(w, http.StatusNotFound, "STUDY_ROOM_NOT_FOUND", "Study room not found")
+	case errors.Is(err, domain.ErrNotSquadMember):
+		respondError(w, http.StatusForbidden, "NOT_MEMBER", "You are not a member of this squad")
+	case errors.Is(err, domain.ErrInvalidStudyRoom):
+		respondError(w, http.StatusBadRequest, "INVALID_STUDY_ROOM", err.Error())
+	case errors.Is(err, domain.ErrInvalidRSVP):
+		respondError(w, http.StatusBadRequest, "INVALID_RSVP", err.Error())
+	case errors.Is(err, domain.ErrNotStudyRoomHost):
+		respondError(w, http.StatusForbidden, "NOT_HOST", "Only the host or a squad admin can perform this action")
+	case errors.Is(err, domain.ErrSquadRoleForbidden):
+		respondError(w, http.StatusForbidden, "ROLE_FORBIDDEN", "Your squad role does not allow this action")
+	case errors.Is(err, domain.ErrStudyRoomCancelled):
+		respondError(w, http.StatusConflict, "STUDY_ROOM_CANCELLED", "This study room was cancelled")
+	case errors.Is(err, domain.ErrStudyRoomStarted):
+		respondError(w, http.StatusConflict, "STUDY_ROOM_STARTED", "This study room has already started")
+	case errors.Is(err, domain.ErrStudyRoomEnded):
+		respondError(w, http.StatusConflict, "STUDY_ROOM_ENDED", "This study room has ended")
+	case errors.Is(err, domain.ErrStudyRoomNotOpen):
+		respondError(w, http.StatusConflict, "STUDY_ROOM_NOT_OPEN", "This study room opens 5 minutes before it starts")
+	default:
+		// Joining starts a focus session
+		handleFocusError(w, err)
+	}
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/handler"
+	"github.com/antigravity/backend/internal/middleware"
+	"github.com/antigravity/backend/internal/mocks"
+	"github.com/antigravity/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestStudyRoomHandler_JoinRoom(t *testing.T) {
+	mockService := &mocks.MockStudyRoomService{}
+	h := handler.NewStudyRoomHandler(mockService)
+
+	newRequest := func(userID, roomID uuid.UUID) *http.Request {
+		req := httptest.NewRequest("POST", "/api/v1/study-rooms/"+roomID.String()+"/join", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("roomID", roomID.String())
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+		return req.WithContext(ctx)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"Success", nil, http.StatusCreated},
+		{"NotFound", domain.ErrStudyRoomNotFound, http.StatusNotFound},
+		{"NotOpenYet", domain.ErrStudyRoomNotOpen, http.StatusConflict},
+		{"Ended", domain.ErrStudyRoomEnded, http.StatusConflict},
+		{"FocusError", service.ErrInvalidPlan, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			roomID := uuid.New()
+
+			mockService.JoinRoomFunc = func(ctx context.Context, uid, rid uuid.UUID) (*domain.JoinStudyRoomResponse, error) {
+				if uid != userID || rid != roomID {
+					t.Errorf("expected user %v in room %v, got %v in %v", userID, roomID, uid, rid)
+				}
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &domain.JoinStudyRoomResponse{RoomID: roomID, Session: &domain.StartFocusResponse{SessionID: uuid.New()}}, nil
+			}
+
+			w := httptest.NewRecorder()
+			h.JoinRoom(w, newRequest(userID, roomID))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+
+	t.Run("InvalidRoomID", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/study-rooms/nope/join", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("roomID", "nope")
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, middleware.UserIDKey, uuid.New())
+
+		w := httptest.NewRecorder()
+		h.JoinRoom(w, req.WithContext(ctx))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
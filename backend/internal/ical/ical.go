@@ -0,0 +1,138 @@
+// Package ical writes RFC 5545 iCalendar feeds.
+//
+// It covers what a read-only subscription feed needs: a calendar of
+// events with UTC start and end times. Times are always written in UTC,
+// so no VTIMEZONE components are needed; callers convert local times
+// (such as a user's midnight) to instants first.
+//
+// Encode takes the DTSTAMP as an argument rather than reading the clock,
+// so the same calendar always encodes to the same bytes.
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event statuses
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineOctets is the longest content line before it must be folded
+const maxLineOctets = 75
+
+// Calendar is a feed of events
+type Calendar struct {
+	ProdID          string // e.g. "-//Antigravity//Squads//EN"
+	Name            string // Shown by clients as the calendar name
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is a VEVENT. Free events (Transparent) don't block time.
+type Event struct {
+	UID         string // Stable across fetches, so clients update in place
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Status      string // One of the Status* values, or empty
+	Transparent bool
+}
+
+// Encode renders the calendar as an iCalendar object. stamp is the
+// DTSTAMP of every event, normally the time the feed is generated.
+func (c *Calendar) Encode(stamp time.Time) []byte {
+	var b strings.Builder
+	w := func(name, value string) {
+		writeLine(&b, name+":"+value)
+	}
+
+	w("BEGIN", "VCALENDAR")
+	w("VERSION", "2.0")
+	w("PRODID", c.ProdID)
+	w("CALSCALE", "GREGORIAN")
+	w("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w("X-WR-CALNAME", Escape(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		writeLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:"+duration(c.RefreshInterval))
+		w("X-PUBLISHED-TTL", duration(c.RefreshInterval))
+	}
+
+	for _, e := range c.Events {
+		w("BEGIN", "VEVENT")
+		w("UID", e.UID)
+		w("DTSTAMP", formatTime(stamp))
+		w("DTSTART", formatTime(e.Start))
+		w("DTEND", formatTime(e.End))
+		w("SUMMARY", Escape(e.Summary))
+		if e.Description != "" {
+			w("DESCRIPTION", Escape(e.Description))
+		}
+		if e.Status != "" {
+			w("STATUS", e.Status)
+		}
+		if e.Transparent {
+			w("TRANSP", "TRANSPARENT")
+		} else {
+			w("TRANSP", "OPAQUE")
+		}
+		w("END", "VEVENT")
+	}
+
+	w("END", "VCALENDAR")
+	return []byte(b.String())
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// Escape escapes a TEXT value
+func Escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeLine writes a content line, folded so that no line is longer than
+// 75 octets. Continuation lines start with a space and never split a
+// UTF-8 sequence.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // The leading space counts
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// duration formats a whole number of minutes as an RFC 5545 duration
+func duration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes%60 == 0 {
+		return "PT" + strconv.Itoa(minutes/60) + "H"
+	}
+	return "PT" + strconv.Itoa(minutes) + "M"
+}
@@ -0,0 +1,99 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Calculus", "Calculus"},
+		{"Ch. 3, 4; review", `Ch. 3\, 4\; review`},
+		{`C:\notes`, `C:\\notes`},
+		{"line one\nline two\r\nthree", `line one\nline two\nthree`},
+	}
+
+	for _, tt := range tests {
+		if got := Escape(tt.in); got != tt.want {
+			t.Errorf("Escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLineFolds(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Calculus"},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{"multibyte", "SUMMARY:" + strings.Repeat("📚é", 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeLine(&b, tt.line)
+			out := b.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("expected CRLF line ending, got %q", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, l := range lines {
+				if len(l) > maxLineOctets {
+					t.Errorf("line %d is %d octets", i, len(l))
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a UTF-8 sequence", i)
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d does not start with a space", i)
+				}
+			}
+
+			// Unfolding gives the original line back
+			if got := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); got != tt.line {
+				t.Errorf("unfolded line differs: %q", got)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2026, 3, 10, 18, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	cal := Calendar{
+		ProdID:          "-//Antigravity//Squads//EN",
+		Name:            "Antigravity",
+		RefreshInterval: time.Hour,
+		Events: []Event{{
+			UID:     "room-1@antigravity",
+			Start:   start,
+			End:     start.Add(50 * time.Minute),
+			Summary: "Study room: Calculus",
+			Status:  StatusConfirmed,
+		}},
+	}
+
+	out := string(cal.Encode(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n",
+		"DTSTAMP:20260301T000000Z\r\n",
+		"DTSTART:20260310T123000Z\r\n",
+		"DTEND:20260310T132000Z\r\n",
+		"STATUS:CONFIRMED\r\nTRANSP:OPAQUE\r\nEND:VEVENT\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected feed to contain %q, got:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("expected feed to end the calendar, got:\n%s", out)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AdminKeyMiddleware protects operational endpoints with a static API key
+// sent as "Authorization: Bearer <key>". If no key is configured, every
+// request is rejected.
+func AdminKeyMiddleware(apiKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey == "" {
+				http.Error(w, `{"error":"Admin API is disabled","code":"FORBIDDEN"}`, http.StatusForbidden)
+				return
+			}
+
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) != 1 {
+				http.Error(w, `{"error":"Invalid admin key","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -2,8 +2,11 @@ package middleware
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -30,49 +33,167 @@ func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 				return
 			}
 
-			tokenString := parts[1]
-
-			// Parse and validate JWT
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				// Validate signing method
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
-				}
-				return []byte(jwtSecret), nil
-			})
-
-			if err != nil || !token.Valid {
-				http.Error(w, `{"error":"Invalid token","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
+			userID, errMsg := parseToken(parts[1], jwtSecret)
+			if errMsg != "" {
+				http.Error(w, `{"error":"`+errMsg+`","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
 				return
 			}
 
-			// Extract user ID from claims
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				http.Error(w, `{"error":"Invalid token claims","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
+			// Add user ID to context
+			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// WebSocketAuthMiddleware validates Supabase JWT tokens on WebSocket
+// handshakes. Browsers can't set headers there, so besides the
+// Authorization header the token may come as a "bearer.<jwt>" entry of
+// Sec-WebSocket-Protocol. Upgraders must never echo that entry back.
+func WebSocketAuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString := ""
+			if parts := strings.Split(r.Header.Get("Authorization"), " "); len(parts) == 2 && parts[0] == "Bearer" {
+				tokenString = parts[1]
+			}
+			if tokenString == "" {
+				for _, protocol := range strings.Split(r.Header.Get("Sec-WebSocket-Protocol"), ",") {
+					if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), "bearer."); ok {
+						tokenString = token
+						break
+					}
+				}
+			}
+			if tokenString == "" {
+				http.Error(w, `{"error":"Missing token","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
 				return
 			}
 
-			// Supabase stores user ID in "sub" claim
-			sub, ok := claims["sub"].(string)
-			if !ok {
-				http.Error(w, `{"error":"Missing user ID in token","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
+			userID, errMsg := parseToken(tokenString, jwtSecret)
+			if errMsg != "" {
+				http.Error(w, `{"error":"`+errMsg+`","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
 				return
 			}
 
-			userID, err := uuid.Parse(sub)
-			if err != nil {
-				http.Error(w, `{"error":"Invalid user ID format","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
+			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// StreamTicketTTL is how long a stream ticket can open a stream. Streams
+// outlive their ticket; clients fetch a new one to reconnect.
+const StreamTicketTTL = 5 * time.Minute
+
+// streamTicketAudience marks stream tickets, so they are never mistaken
+// for session tokens
+const streamTicketAudience = "stream"
+
+// IssueStreamTicket creates a short-lived ticket that lets the user open
+// event streams. It is signed with a key derived from the JWT secret, so
+// AuthMiddleware doesn't accept it.
+func IssueStreamTicket(jwtSecret string, userID uuid.UUID, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(StreamTicketTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   userID.String(),
+		Audience:  jwt.ClaimStrings{streamTicketAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+	ticket, err := token.SignedString(streamTicketKey(jwtSecret))
+	return ticket, expiresAt, err
+}
+
+// StreamAuthMiddleware authenticates Server-Sent Event streams. A native
+// EventSource can't set headers, so besides the Authorization header it
+// accepts a stream ticket in the "ticket" query parameter.
+func StreamAuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var userID uuid.UUID
+			var errMsg string
+			if parts := strings.Split(r.Header.Get("Authorization"), " "); len(parts) == 2 && parts[0] == "Bearer" {
+				userID, errMsg = parseToken(parts[1], jwtSecret)
+			} else if ticket := r.URL.Query().Get("ticket"); ticket != "" {
+				userID, errMsg = parseStreamTicket(ticket, jwtSecret)
+			} else {
+				errMsg = "Missing token"
+			}
+			if errMsg != "" {
+				http.Error(w, `{"error":"`+errMsg+`","code":"UNAUTHORIZED"}`, http.StatusUnauthorized)
 				return
 			}
 
-			// Add user ID to context
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// parseStreamTicket validates a stream ticket and returns its user ID, or
+// an error message
+func parseStreamTicket(ticket, jwtSecret string) (uuid.UUID, string) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(ticket, claims, func(token *jwt.Token) (interface{}, error) {
+		return streamTicketKey(jwtSecret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(streamTicketAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid {
+		return uuid.Nil, "Invalid or expired stream ticket"
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, "Invalid user ID format"
+	}
+	return userID, ""
+}
+
+func streamTicketKey(jwtSecret string) []byte {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte("stream-ticket"))
+	return mac.Sum(nil)
+}
+
+// parseToken validates a JWT and returns its user ID, or an error message
+func parseToken(tokenString, jwtSecret string) (uuid.UUID, string) {
+	// Parse and validate JWT
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(jwtSecret), nil
+	})
+
+	if err != nil || !token.Valid {
+		return uuid.Nil, "Invalid token"
+	}
+
+	// Extract user ID from claims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil, "Invalid token claims"
+	}
+
+	// Supabase stores user ID in "sub" claim
+	sub, ok := claims["sub"].(string)
+	if !ok {
+		return uuid.Nil, "Missing user ID in token"
+	}
+
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, "Invalid user ID format"
+	}
+
+	return userID, ""
+}
+
 // GetUserID extracts user ID from context
 func GetUserID(ctx context.Context) uuid.UUID {
 	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
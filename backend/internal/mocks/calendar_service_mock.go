@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type MockCalendarService struct {
+	GetFeedFunc    func(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error)
+	CreateFeedFunc func(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedURL, error)
+	RevokeFeedFunc func(ctx context.Context, userID uuid.UUID) error
+	RenderFeedFunc func(ctx context.Context, token string) ([]byte, error)
+}
+
+func (m *MockCalendarService) GetFeed(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	if m.GetFeedFunc != nil {
+		return m.GetFeedFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCalendarService) CreateFeed(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedURL, error) {
+	if m.CreateFeedFunc != nil {
+		return m.CreateFeedFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCalendarService) RevokeFeed(ctx context.Context, userID uuid.UUID) error {
+	if m.RevokeFeedFunc != nil {
+		return m.RevokeFeedFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *MockCalendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	if m.RenderFeedFunc != nil {
+		return m.RenderFeedFunc(ctx, token)
+	}
+	return nil, nil
+}
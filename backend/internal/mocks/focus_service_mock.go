@@ -2,28 +2,47 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/google/uuid"
 )
 
 type MockFocusService struct {
-	StartFocusFunc       func(ctx context.Context, userID uuid.UUID, squadID uuid.UUID) (*domain.StartFocusResponse, error)
-	StopFocusFunc        func(ctx context.Context, userID uuid.UUID) (*domain.StopFocusResponse, error)
-	GetActiveInSquadFunc func(ctx context.Context, userID, squadID uuid.UUID) (*domain.ActiveSessionsResponse, error)
-	GetFocusHistoryFunc  func(ctx context.Context, userID, squadID uuid.UUID) (*domain.FocusHistoryResponse, error)
+	StartFocusFunc          func(ctx context.Context, userID uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error)
+	StopFocusFunc           func(ctx context.Context, userID uuid.UUID, req *domain.StopFocusRequest) (*domain.StopFocusResponse, error)
+	SetOutcomeFunc          func(ctx context.Context, userID, sessionID uuid.UUID, goalMet bool) (*domain.FocusSession, error)
+	HeartbeatFunc           func(ctx context.Context, userID uuid.UUID) (*domain.HeartbeatResponse, error)
+	PauseFocusFunc          func(ctx context.Context, userID uuid.UUID) (*domain.FocusPauseResponse, error)
+	ResumeFocusFunc         func(ctx context.Context, userID uuid.UUID) (*domain.FocusPauseResponse, error)
+	GetActiveInSquadFunc    func(ctx context.Context, userID, squadID uuid.UUID) (*domain.ActiveSessionsResponse, error)
+	GetFocusHistoryFunc     func(ctx context.Context, userID, squadID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error)
+	GetMyHistoryFunc        func(ctx context.Context, userID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error)
+	GetMyStatsFunc          func(ctx context.Context, userID uuid.UUID, period string, count int) (*domain.FocusStatsResponse, error)
+	LogSessionFunc          func(ctx context.Context, userID uuid.UUID, req *domain.LogSessionRequest) (*domain.FocusSession, error)
+	SetBroadcastFunc        func(ctx context.Context, userID uuid.UUID, broadcast bool) (*domain.FocusSession, error)
+	GetMyTagsFunc           func(ctx context.Context, userID uuid.UUID) (*domain.FocusTagsResponse, error)
+	SetFavouriteTagsFunc    func(ctx context.Context, userID uuid.UUID, req *domain.SetFavouriteTagsRequest) (*domain.FocusTagsResponse, error)
+	GetSubjectBreakdownFunc func(ctx context.Context, userID uuid.UUID, from, to *time.Time) (*domain.SubjectBreakdownResponse, error)
 }
 
-func (m *MockFocusService) StartFocus(ctx context.Context, userID uuid.UUID, squadID uuid.UUID) (*domain.StartFocusResponse, error) {
+func (m *MockFocusService) StartFocus(ctx context.Context, userID uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error) {
 	if m.StartFocusFunc != nil {
-		return m.StartFocusFunc(ctx, userID, squadID)
+		return m.StartFocusFunc(ctx, userID, req)
 	}
 	return nil, nil
 }
 
-func (m *MockFocusService) StopFocus(ctx context.Context, userID uuid.UUID) (*domain.StopFocusResponse, error) {
+func (m *MockFocusService) StopFocus(ctx context.Context, userID uuid.UUID, req *domain.StopFocusRequest) (*domain.StopFocusResponse, error) {
 	if m.StopFocusFunc != nil {
-		return m.StopFocusFunc(ctx, userID)
+		return m.StopFocusFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) SetOutcome(ctx context.Context, userID, sessionID uuid.UUID, goalMet bool) (*domain.FocusSession, error) {
+	if m.SetOutcomeFunc != nil {
+		return m.SetOutcomeFunc(ctx, userID, sessionID, goalMet)
 	}
 	return nil, nil
 }
@@ -35,9 +54,79 @@ func (m *MockFocusService) GetActiveInSquad(ctx context.Context, userID, squadID
 	return nil, nil
 }
 
-func (m *MockFocusService) GetFocusHistory(ctx context.Context, userID, squadID uuid.UUID) (*domain.FocusHistoryResponse, error) {
+func (m *MockFocusService) GetFocusHistory(ctx context.Context, userID, squadID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
 	if m.GetFocusHistoryFunc != nil {
-		return m.GetFocusHistoryFunc(ctx, userID, squadID)
+		return m.GetFocusHistoryFunc(ctx, userID, squadID, filter, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) GetMyHistory(ctx context.Context, userID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
+	if m.GetMyHistoryFunc != nil {
+		return m.GetMyHistoryFunc(ctx, userID, filter, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) Heartbeat(ctx context.Context, userID uuid.UUID) (*domain.HeartbeatResponse, error) {
+	if m.HeartbeatFunc != nil {
+		return m.HeartbeatFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) PauseFocus(ctx context.Context, userID uuid.UUID) (*domain.FocusPauseResponse, error) {
+	if m.PauseFocusFunc != nil {
+		return m.PauseFocusFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) ResumeFocus(ctx context.Context, userID uuid.UUID) (*domain.FocusPauseResponse, error) {
+	if m.ResumeFocusFunc != nil {
+		return m.ResumeFocusFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) GetMyStats(ctx context.Context, userID uuid.UUID, period string, count int) (*domain.FocusStatsResponse, error) {
+	if m.GetMyStatsFunc != nil {
+		return m.GetMyStatsFunc(ctx, userID, period, count)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) GetMyTags(ctx context.Context, userID uuid.UUID) (*domain.FocusTagsResponse, error) {
+	if m.GetMyTagsFunc != nil {
+		return m.GetMyTagsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) SetFavouriteTags(ctx context.Context, userID uuid.UUID, req *domain.SetFavouriteTagsRequest) (*domain.FocusTagsResponse, error) {
+	if m.SetFavouriteTagsFunc != nil {
+		return m.SetFavouriteTagsFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) GetSubjectBreakdown(ctx context.Context, userID uuid.UUID, from, to *time.Time) (*domain.SubjectBreakdownResponse, error) {
+	if m.GetSubjectBreakdownFunc != nil {
+		return m.GetSubjectBreakdownFunc(ctx, userID, from, to)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) LogSession(ctx context.Context, userID uuid.UUID, req *domain.LogSessionRequest) (*domain.FocusSession, error) {
+	if m.LogSessionFunc != nil {
+		return m.LogSessionFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockFocusService) SetBroadcast(ctx context.Context, userID uuid.UUID, broadcast bool) (*domain.FocusSession, error) {
+	if m.SetBroadcastFunc != nil {
+		return m.SetBroadcastFunc(ctx, userID, broadcast)
 	}
 	return nil, nil
 }
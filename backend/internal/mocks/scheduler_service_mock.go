@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/antigravity/backend/internal/domain"
+)
+
+type MockSchedulerService struct {
+	ListJobsFunc   func(ctx context.Context) ([]domain.JobInfo, error)
+	GetJobRunsFunc func(ctx context.Context, name string, limit int) ([]domain.JobRun, error)
+	PauseJobFunc   func(ctx context.Context, name string) error
+	ResumeJobFunc  func(ctx context.Context, name string) error
+	TriggerJobFunc func(ctx context.Context, name string) (*domain.JobRun, error)
+}
+
+func (m *MockSchedulerService) ListJobs(ctx context.Context) ([]domain.JobInfo, error) {
+	if m.ListJobsFunc != nil {
+		return m.ListJobsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockSchedulerService) GetJobRuns(ctx context.Context, name string, limit int) ([]domain.JobRun, error) {
+	if m.GetJobRunsFunc != nil {
+		return m.GetJobRunsFunc(ctx, name, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockSchedulerService) PauseJob(ctx context.Context, name string) error {
+	if m.PauseJobFunc != nil {
+		return m.PauseJobFunc(ctx, name)
+	}
+	return nil
+}
+
+func (m *MockSchedulerService) ResumeJob(ctx context.Context, name string) error {
+	if m.ResumeJobFunc != nil {
+		return m.ResumeJobFunc(ctx, name)
+	}
+	return nil
+}
+
+func (m *MockSchedulerService) TriggerJob(ctx context.Context, name string) (*domain.JobRun, error) {
+	if m.TriggerJobFunc != nil {
+		return m.TriggerJobFunc(ctx, name)
+	}
+	return nil, nil
+}
@@ -16,6 +16,9 @@ type MockSquadService struct {
 	JoinSquadFunc            func(ctx context.Context, userID uuid.UUID, inviteCode string) (*domain.Squad, error)
 	RemoveMemberFunc         func(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID) error
 	RegenerateInviteCodeFunc func(ctx context.Context, squadID, userID uuid.UUID) (string, error)
+	UpdateMemberRoleFunc     func(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID, role domain.SquadRole) error
+	TransferOwnershipFunc    func(ctx context.Context, squadID, callerUserID, newOwnerID uuid.UUID) (*domain.Squad, error)
+	GetSquadLeaderboardFunc  func(ctx context.Context, limit int) ([]domain.SquadLeaderboardEntry, error)
 }
 
 func (m *MockSquadService) CreateSquad(ctx context.Context, userID uuid.UUID, req *domain.CreateSquadRequest) (*domain.Squad, error) {
@@ -73,3 +76,24 @@ func (m *MockSquadService) RegenerateInviteCode(ctx context.Context, squadID, us
 	}
 	return "", nil
 }
+
+func (m *MockSquadService) UpdateMemberRole(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID, role domain.SquadRole) error {
+	if m.UpdateMemberRoleFunc != nil {
+		return m.UpdateMemberRoleFunc(ctx, squadID, targetUserID, callerUserID, role)
+	}
+	return nil
+}
+
+func (m *MockSquadService) TransferOwnership(ctx context.Context, squadID, callerUserID, newOwnerID uuid.UUID) (*domain.Squad, error) {
+	if m.TransferOwnershipFunc != nil {
+		return m.TransferOwnershipFunc(ctx, squadID, callerUserID, newOwnerID)
+	}
+	return nil, nil
+}
+
+func (m *MockSquadService) GetSquadLeaderboard(ctx context.Context, limit int) ([]domain.SquadLeaderboardEntry, error) {
+	if m.GetSquadLeaderboardFunc != nil {
+		return m.GetSquadLeaderboardFunc(ctx, limit)
+	}
+	return nil, nil
+}
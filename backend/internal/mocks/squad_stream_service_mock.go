@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type MockSquadStreamService struct {
+	SubscribeFunc     func(ctx context.Context, userID, squadID uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error)
+	SubscribeUserFunc func(ctx context.Context, userID uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error)
+}
+
+func (m *MockSquadStreamService) Subscribe(ctx context.Context, userID, squadID uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+	if m.SubscribeFunc != nil {
+		return m.SubscribeFunc(ctx, userID, squadID, lastEventID)
+	}
+	return nil, nil
+}
+
+func (m *MockSquadStreamService) SubscribeUser(ctx context.Context, userID uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+	if m.SubscribeUserFunc != nil {
+		return m.SubscribeUserFunc(ctx, userID, lastEventID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type MockStudyRoomService struct {
+	CreateRoomFunc func(ctx context.Context, userID, squadID uuid.UUID, req *domain.CreateStudyRoomRequest) (*domain.StudyRoom, error)
+	ListRoomsFunc  func(ctx context.Context, userID, squadID uuid.UUID) ([]domain.StudyRoom, error)
+	GetRoomFunc    func(ctx context.Context, userID, roomID uuid.UUID) (*domain.StudyRoomDetail, error)
+	RSVPFunc       func(ctx context.Context, userID, roomID uuid.UUID, status string) (*domain.StudyRoom, error)
+	JoinRoomFunc   func(ctx context.Context, userID, roomID uuid.UUID) (*domain.JoinStudyRoomResponse, error)
+	CancelRoomFunc func(ctx context.Context, userID, roomID uuid.UUID) error
+}
+
+func (m *MockStudyRoomService) CreateRoom(ctx context.Context, userID, squadID uuid.UUID, req *domain.CreateStudyRoomRequest) (*domain.StudyRoom, error) {
+	if m.CreateRoomFunc != nil {
+		return m.CreateRoomFunc(ctx, userID, squadID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockStudyRoomService) ListRooms(ctx context.Context, userID, squadID uuid.UUID) ([]domain.StudyRoom, error) {
+	if m.ListRoomsFunc != nil {
+		return m.ListRoomsFunc(ctx, userID, squadID)
+	}
+	return nil, nil
+}
+
+func (m *MockStudyRoomService) GetRoom(ctx context.Context, userID, roomID uuid.UUID) (*domain.StudyRoomDetail, error) {
+	if m.GetRoomFunc != nil {
+		return m.GetRoomFunc(ctx, userID, roomID)
+	}
+	return nil, nil
+}
+
+func (m *MockStudyRoomService) RSVP(ctx context.Context, userID, roomID uuid.UUID, status string) (*domain.StudyRoom, error) {
+	if m.RSVPFunc != nil {
+		return m.RSVPFunc(ctx, userID, roomID, status)
+	}
+	return nil, nil
+}
+
+func (m *MockStudyRoomService) JoinRoom(ctx context.Context, userID, roomID uuid.UUID) (*domain.JoinStudyRoomResponse, error) {
+	if m.JoinRoomFunc != nil {
+		return m.JoinRoomFunc(ctx, userID, roomID)
+	}
+	return nil, nil
+}
+
+func (m *MockStudyRoomService) CancelRoom(ctx context.Context, userID, roomID uuid.UUID) error {
+	if m.CancelRoomFunc != nil {
+		return m.CancelRoomFunc(ctx, userID, roomID)
+	}
+	return nil
+}
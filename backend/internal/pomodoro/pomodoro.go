@@ -0,0 +1,96 @@
+// Package pomodoro derives the phase of a pomodoro focus session.
+//
+// A session repeats sets of Cycles work blocks separated by short breaks,
+// each set followed by a long break. The schedule is fully determined by
+// the config and the focus time elapsed, so the server never stores phase
+// transitions: it recomputes them on read.
+//
+// It is pure (no I/O), like the streak engine.
+package pomodoro
+
+import "time"
+
+// Phase is the part of the cycle a session is in
+type Phase string
+
+const (
+	PhaseWork       Phase = "work"
+	PhaseShortBreak Phase = "short_break"
+	PhaseLongBreak  Phase = "long_break"
+)
+
+// Config is a pomodoro schedule
+type Config struct {
+	Work       time.Duration
+	ShortBreak time.Duration
+	LongBreak  time.Duration
+	Cycles     int // Work blocks per set, before the long break
+}
+
+// DefaultConfig is the classic 25/5/15 schedule with a long break every 4
+var DefaultConfig = Config{
+	Work:       25 * time.Minute,
+	ShortBreak: 5 * time.Minute,
+	LongBreak:  15 * time.Minute,
+	Cycles:     4,
+}
+
+// State is where a session stands after some focus time
+type State struct {
+	Phase     Phase
+	Cycle     int           // 1-based work block within the current set
+	Completed int           // Work blocks finished so far
+	Remaining time.Duration // Time left in the current phase
+}
+
+// setLength is the duration of one full set, long break included
+func (c Config) setLength() time.Duration {
+	return time.Duration(c.Cycles)*c.Work + time.Duration(c.Cycles-1)*c.ShortBreak + c.LongBreak
+}
+
+// At returns the state after elapsed focus time. An invalid config (any
+// non-positive length) is treated as one endless work block.
+func At(c Config, elapsed time.Duration) State {
+	if c.Work <= 0 || c.ShortBreak <= 0 || c.LongBreak <= 0 || c.Cycles <= 0 {
+		return State{Phase: PhaseWork, Cycle: 1}
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	set := c.setLength()
+	sets := int(elapsed / set)
+	pos := elapsed % set
+	state := State{Completed: sets * c.Cycles}
+
+	for cycle := 1; cycle <= c.Cycles; cycle++ {
+		state.Cycle = cycle
+		if pos < c.Work {
+			state.Phase = PhaseWork
+			state.Remaining = c.Work - pos
+			return state
+		}
+		pos -= c.Work
+		state.Completed++
+
+		breakLength := c.ShortBreak
+		state.Phase = PhaseShortBreak
+		if cycle == c.Cycles {
+			breakLength = c.LongBreak
+			state.Phase = PhaseLongBreak
+		}
+		if pos < breakLength {
+			state.Remaining = breakLength - pos
+			return state
+		}
+		pos -= breakLength
+	}
+
+	// Unreachable: pos < set always lands in a phase above
+	return state
+}
+
+// Completed returns the work blocks finished after elapsed focus time
+func Completed(c Config, elapsed time.Duration) int {
+	return At(c, elapsed).Completed
+}
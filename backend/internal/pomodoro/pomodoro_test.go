@@ -0,0 +1,77 @@
+package pomodoro
+
+import (
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestAt(t *testing.T) {
+	m := time.Minute
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		wantPhase     Phase
+		wantCycle     int
+		wantCompleted int
+		wantRemaining time.Duration
+	}{
+		{"start", 0, PhaseWork, 1, 0, 25 * m},
+		{"mid first work block", 10 * m, PhaseWork, 1, 0, 15 * m},
+		{"first short break", 25 * m, PhaseShortBreak, 1, 1, 5 * m},
+		{"second work block", 30 * m, PhaseWork, 2, 1, 25 * m},
+		{"last work block", 90 * m, PhaseWork, 4, 3, 25 * m},
+		{"long break after the set", 115 * m, PhaseLongBreak, 4, 4, 15 * m},
+		{"next set", 130 * m, PhaseWork, 1, 4, 25 * m},
+		{"second set short break", 155 * m, PhaseShortBreak, 1, 5, 5 * m},
+		{"negative elapsed clamps to start", -5 * m, PhaseWork, 1, 0, 25 * m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := At(DefaultConfig, tt.elapsed)
+			if got.Phase != tt.wantPhase || got.Cycle != tt.wantCycle ||
+				got.Completed != tt.wantCompleted || got.Remaining != tt.wantRemaining {
+				t.Errorf("expected %s cycle %d (%d done, %v left), got %+v",
+					tt.wantPhase, tt.wantCycle, tt.wantCompleted, tt.wantRemaining, got)
+			}
+		})
+	}
+}
+
+func TestAt_InvalidConfig(t *testing.T) {
+	got := At(Config{Work: 25 * time.Minute}, 3*time.Hour)
+	if got.Phase != PhaseWork || got.Completed != 0 {
+		t.Errorf("expected one endless work block, got %+v", got)
+	}
+}
+
+func TestProperty_CompletedNeverDecreases(t *testing.T) {
+	f := func(a, b uint16) bool {
+		early, late := time.Duration(a)*time.Minute, time.Duration(b)*time.Minute
+		if early > late {
+			early, late = late, early
+		}
+		return Completed(DefaultConfig, early) <= Completed(DefaultConfig, late)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProperty_RemainingWithinPhase(t *testing.T) {
+	f := func(a uint16) bool {
+		s := At(DefaultConfig, time.Duration(a)*time.Second*7)
+		limit := DefaultConfig.Work
+		switch s.Phase {
+		case PhaseShortBreak:
+			limit = DefaultConfig.ShortBreak
+		case PhaseLongBreak:
+			limit = DefaultConfig.LongBreak
+		}
+		return s.Remaining > 0 && s.Remaining <= limit
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
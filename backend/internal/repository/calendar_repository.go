@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CalendarRepository handles database operations for calendar feeds
+type CalendarRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarRepository creates a new calendar repository
+func NewCalendarRepository(db *sql.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// SetFeedToken stores the hash of a user's new feed token, replacing (and
+// so revoking) any previous one
+func (r *CalendarRepository) SetFeedToken(ctx context.Context, userID uuid.UUID, tokenHash string) (time.Time, error) {
+	var createdAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO calendar_feeds (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id)
+		DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW(), last_fetched_at = NULL
+		RETURNING created_at
+	`, userID, tokenHash).Scan(&createdAt)
+	return createdAt, err
+}
+
+// GetFeed returns the state of a user's feed
+func (r *CalendarRepository) GetFeed(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	feed := &domain.CalendarFeed{}
+	err := r.db.QueryRowContext(ctx,
+		"SELECT created_at, last_fetched_at FROM calendar_feeds WHERE user_id = $1",
+		userID,
+	).Scan(&feed.CreatedAt, &feed.LastFetchedAt)
+	if err == sql.ErrNoRows {
+		return feed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	feed.Enabled = true
+	return feed, nil
+}
+
+// DeleteFeed revokes a user's feed
+func (r *CalendarRepository) DeleteFeed(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM calendar_feeds WHERE user_id = $1", userID)
+	return err
+}
+
+// GetOwnerByToken returns the user a token hash belongs to and records the
+// fetch, or nil if no feed has that token
+func (r *CalendarRepository) GetOwnerByToken(ctx context.Context, tokenHash string) (*domain.CalendarOwner, error) {
+	query := `
+		WITH feed AS (
+			UPDATE calendar_feeds
+			SET last_fetched_at = NOW()
+			WHERE token_hash = $1
+			RETURNING user_id
+		)
+		SELECT p.id, COALESCE(p.timezone, 'UTC'), p.streak_mode
+		FROM feed
+		JOIN profiles p ON p.id = feed.user_id
+	`
+
+	owner := &domain.CalendarOwner{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&owner.UserID, &owner.Timezone, &owner.StreakMode)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// GetStudyRooms returns the study rooms in a user's squads overlapping
+// [from, to), leaving out rooms the user declined
+func (r *CalendarRepository) GetStudyRooms(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.CalendarStudyRoom, error) {
+	query := `
+		SELECT sr.id, s.name, p.display_name, sr.topic, sr.starts_at, sr.duration_minutes,
+		       sr.cancelled_at IS NOT NULL, r.status
+		FROM study_rooms sr
+		JOIN squad_members sm ON sm.squad_id = sr.squad_id AND sm.user_id = $1
+		JOIN squads s ON s.id = sr.squad_id
+		JOIN profiles p ON p.id = sr.host_id
+		LEFT JOIN study_room_rsvps r ON r.room_id = sr.id AND r.user_id = $1
+		WHERE sr.starts_at < $3
+		  AND sr.starts_at + make_interval(mins => sr.duration_minutes) > $2
+		  AND (r.status IS NULL OR r.status <> 'declined')
+		ORDER BY sr.starts_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []domain.CalendarStudyRoom{}
+	for rows.Next() {
+		var room domain.CalendarStudyRoom
+		if err := rows.Scan(
+			&room.ID, &room.SquadName, &room.HostName, &room.Topic, &room.StartsAt,
+			&room.DurationMinutes, &room.Cancelled, &room.RSVP,
+		); err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+
+	return rooms, nil
+}
+
+// GetFocusSessions returns a user's focus sessions that ended since the
+// given time, oldest first
+func (r *CalendarRepository) GetFocusSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]domain.CalendarFocusSession, error) {
+	query := `
+		SELECT fs.id, s.name, fs.started_at, fs.ended_at, COALESCE(fs.duration_minutes, 0), fs.goal
+		FROM focus_sessions fs
+		LEFT JOIN squads s ON s.id = fs.squad_id
+		WHERE fs.user_id = $1
+		  AND fs.ended_at IS NOT NULL
+		  AND fs.ended_at >= $2
+		ORDER BY fs.started_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.CalendarFocusSession{}
+	for rows.Next() {
+		var session domain.CalendarFocusSession
+		if err := rows.Scan(
+			&session.ID, &session.SquadName, &session.StartedAt, &session.EndedAt,
+			&session.DurationMinutes, &session.Goal,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
@@ -3,10 +3,14 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // FocusRepository handles database operations for focus sessions
@@ -19,26 +23,217 @@ func NewFocusRepository(db *sql.DB) *FocusRepository {
 	return &FocusRepository{db: db}
 }
 
-// StartSession creates a new focus session for a user in a squad
-func (r *FocusRepository) StartSession(ctx context.Context, userID, squadID uuid.UUID) (*domain.FocusSession, error) {
-	query := `
-		INSERT INTO focus_sessions (user_id, squad_id)
-		VALUES ($1, $2)
-		RETURNING id, user_id, squad_id, started_at, ended_at, duration_minutes
-	`
+// focusSessionColumns are the columns scanned by scanFocusSession
+const focusSessionColumns = `
+	id, user_id, squad_id, started_at, ended_at, duration_minutes,
+	paused_at, paused_seconds, pomodoro_work_minutes, pomodoro_short_break_minutes,
+	pomodoro_long_break_minutes, pomodoro_cycles, completed_pomodoros,
+	planned_minutes, goal, goal_met, auto_ended, last_heartbeat_at, study_room_id,
+	tags, note, manual, broadcast
+`
 
+func scanFocusSession(row rowScanner) (*domain.FocusSession, error) {
 	session := &domain.FocusSession{}
-	err := r.db.QueryRowContext(ctx, query, userID, squadID).Scan(
+	var pomodoro nullPomodoro
+	if err := row.Scan(
 		&session.ID,
 		&session.UserID,
 		&session.SquadID,
 		&session.StartedAt,
 		&session.EndedAt,
 		&session.DurationMinutes,
-	)
+		&session.PausedAt,
+		&session.PausedSeconds,
+		&pomodoro.work,
+		&pomodoro.shortBreak,
+		&pomodoro.longBreak,
+		&pomodoro.cycles,
+		&session.CompletedPomodoros,
+		&session.PlannedMinutes,
+		&session.Goal,
+		&session.GoalMet,
+		&session.AutoEnded,
+		&session.LastHeartbeatAt,
+		&session.StudyRoomID,
+		pq.Array(&session.Tags),
+		&session.Note,
+		&session.Manual,
+		&session.Broadcast,
+	); err != nil {
+		return nil, err
+	}
+	session.Pomodoro = pomodoro.config()
+	return session, nil
+}
+
+// nullPomodoro scans the nullable pomodoro columns of a session
+type nullPomodoro struct {
+	work, shortBreak, longBreak, cycles sql.NullInt64
+}
+
+func (p nullPomodoro) config() *domain.PomodoroConfig {
+	if !p.work.Valid {
+		return nil
+	}
+	return &domain.PomodoroConfig{
+		WorkMinutes:       int(p.work.Int64),
+		ShortBreakMinutes: int(p.shortBreak.Int64),
+		LongBreakMinutes:  int(p.longBreak.Int64),
+		Cycles:            int(p.cycles.Int64),
+	}
+}
+
+// StartSession creates a new focus session for a user, in a squad or solo,
+// from an already validated request
+func (r *FocusRepository) StartSession(ctx context.Context, userID uuid.UUID, req *domain.StartFocusRequest) (*domain.FocusSession, error) {
+	query := `
+		INSERT INTO focus_sessions (
+			user_id, squad_id,
+			pomodoro_work_minutes, pomodoro_short_break_minutes,
+			pomodoro_long_break_minutes, pomodoro_cycles,
+			planned_minutes, goal, study_room_id, tags, broadcast
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), NULLIF($8, ''), $9, COALESCE($10::TEXT[], '{}'), $11)
+		RETURNING ` + focusSessionColumns
+
+	var work, shortBreak, longBreak, cycles interface{}
+	if p := req.Pomodoro; p != nil {
+		work, shortBreak, longBreak, cycles = p.WorkMinutes, p.ShortBreakMinutes, p.LongBreakMinutes, p.Cycles
+	}
+
+	args := []interface{}{
+		userID, req.SquadID, work, shortBreak, longBreak, cycles, req.PlannedMinutes, req.Goal, req.StudyRoomID, pq.Array(req.Tags), req.Broadcast,
+	}
+	if req.StudyRoomID == nil {
+		return scanFocusSession(r.db.QueryRowContext(ctx, query, args...))
+	}
+
+	// A study room session and the member's attendance are written together
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := scanFocusSession(tx.QueryRowContext(ctx, query, args...))
 	if err != nil {
 		return nil, err
 	}
+	if err := recordAttendance(ctx, tx, *req.StudyRoomID, userID, session.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// recordAttendance marks a member as attending a study room with the focus
+// session they joined with. The first join time is kept, and joining
+// counts as going.
+func recordAttendance(ctx context.Context, tx *sql.Tx, roomID, userID, sessionID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO study_room_attendance (room_id, user_id, session_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (room_id, user_id)
+		DO UPDATE SET session_id = EXCLUDED.session_id
+	`, roomID, userID, sessionID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO study_room_rsvps (room_id, user_id, status)
+		VALUES ($1, $2, 'going')
+		ON CONFLICT (room_id, user_id)
+		DO UPDATE SET status = 'going', updated_at = NOW()
+		WHERE study_room_rsvps.status <> 'going'
+	`, roomID, userID)
+	return err
+}
+
+// CreateManualSession records a backfilled session from an already
+// validated request, with an audit entry. It fails with
+// domain.ErrSessionOverlap if the user has another session in that time,
+// or domain.ErrBackfillCapReached if the user's backfills ending in
+// [capFrom, capTo) would exceed dailyCapMinutes.
+func (r *FocusRepository) CreateManualSession(ctx context.Context, userID uuid.UUID, req *domain.LogSessionRequest, capFrom, capTo time.Time, dailyCapMinutes int) (*domain.FocusSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Serialize the user's backfills so the checks below hold at insert
+	var locked int
+	err = tx.QueryRowContext(ctx, "SELECT 1 FROM profiles WHERE id = $1 FOR UPDATE", userID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var overlaps bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM focus_sessions
+			WHERE user_id = $1
+			  AND started_at < $3
+			  AND COALESCE(ended_at, NOW()) > $2
+		)
+	`, userID, req.StartedAt, req.EndedAt).Scan(&overlaps); err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, domain.ErrSessionOverlap
+	}
+
+	var backfilled int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(duration_minutes), 0)
+		FROM focus_sessions
+		WHERE user_id = $1 AND manual AND ended_at >= $2 AND ended_at < $3
+	`, userID, capFrom, capTo).Scan(&backfilled); err != nil {
+		return nil, err
+	}
+	if backfilled+int(req.EndedAt.Sub(req.StartedAt).Minutes()) > dailyCapMinutes {
+		return nil, domain.ErrBackfillCapReached
+	}
+
+	query := `
+		INSERT INTO focus_sessions (
+			user_id, squad_id, started_at, ended_at, last_heartbeat_at,
+			goal, goal_met, tags, note, manual
+		)
+		VALUES ($1, $2, $3, $4, $4, NULLIF($5, ''), $6, COALESCE($7::TEXT[], '{}'), NULLIF($8, ''), TRUE)
+		RETURNING ` + focusSessionColumns
+
+	session, err := scanFocusSession(tx.QueryRowContext(ctx, query,
+		userID, req.SquadID, req.StartedAt, req.EndedAt, req.Goal, req.GoalMet, pq.Array(req.Tags), req.Note,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"squad_id":   req.SquadID,
+		"started_at": req.StartedAt,
+		"ended_at":   req.EndedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO focus_session_audit (session_id, user_id, action, details)
+		VALUES ($1, $2, 'backfilled', $3)
+	`, session.ID, userID, details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	return session, nil
 }
@@ -49,18 +244,9 @@ func (r *FocusRepository) EndSession(ctx context.Context, userID uuid.UUID) (*do
 		UPDATE focus_sessions
 		SET ended_at = NOW()
 		WHERE user_id = $1 AND ended_at IS NULL
-		RETURNING id, user_id, squad_id, started_at, ended_at, duration_minutes
-	`
+		RETURNING ` + focusSessionColumns
 
-	session := &domain.FocusSession{}
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&session.ID,
-		&session.UserID,
-		&session.SquadID,
-		&session.StartedAt,
-		&session.EndedAt,
-		&session.DurationMinutes,
-	)
+	session, err := scanFocusSession(r.db.QueryRowContext(ctx, query, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil // No active session
 	}
@@ -73,22 +259,13 @@ func (r *FocusRepository) EndSession(ctx context.Context, userID uuid.UUID) (*do
 
 // GetActiveSession returns the user's current active session, if any
 func (r *FocusRepository) GetActiveSession(ctx context.Context, userID uuid.UUID) (*domain.FocusSession, error) {
-	query := `
-		SELECT id, user_id, squad_id, started_at, ended_at, duration_minutes
+	query := `SELECT ` + focusSessionColumns + `
 		FROM focus_sessions
 		WHERE user_id = $1 AND ended_at IS NULL
 		LIMIT 1
 	`
 
-	session := &domain.FocusSession{}
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&session.ID,
-		&session.UserID,
-		&session.SquadID,
-		&session.StartedAt,
-		&session.EndedAt,
-		&session.DurationMinutes,
-	)
+	session, err := scanFocusSession(r.db.QueryRowContext(ctx, query, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -99,17 +276,231 @@ func (r *FocusRepository) GetActiveSession(ctx context.Context, userID uuid.UUID
 	return session, nil
 }
 
-// GetActiveBySquad returns all active focus sessions for a squad
-func (r *FocusRepository) GetActiveBySquad(ctx context.Context, squadID uuid.UUID) ([]domain.ActiveSession, error) {
+// GetSession returns a session by ID, or nil if it doesn't exist
+func (r *FocusRepository) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.FocusSession, error) {
+	query := `SELECT ` + focusSessionColumns + ` FROM focus_sessions WHERE id = $1`
+
+	session, err := scanFocusSession(r.db.QueryRowContext(ctx, query, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Heartbeat marks the user's active session as still present and returns
+// it, or nil if there is no active session
+func (r *FocusRepository) Heartbeat(ctx context.Context, userID uuid.UUID) (*domain.FocusSession, error) {
 	query := `
-		SELECT fs.user_id, p.display_name, p.avatar_url, fs.started_at, fs.duration_minutes
+		UPDATE focus_sessions
+		SET last_heartbeat_at = NOW()
+		WHERE user_id = $1 AND ended_at IS NULL
+		RETURNING ` + focusSessionColumns
+
+	session, err := scanFocusSession(r.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// PauseSession starts a pause of an active, running session. Returns nil
+// if the session is already paused or has ended.
+func (r *FocusRepository) PauseSession(ctx context.Context, sessionID uuid.UUID) (*domain.FocusSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE focus_sessions
+		SET paused_at = NOW(), last_heartbeat_at = NOW()
+		WHERE id = $1 AND ended_at IS NULL AND paused_at IS NULL
+		RETURNING ` + focusSessionColumns
+
+	session, err := scanFocusSession(tx.QueryRowContext(ctx, query, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO focus_session_pauses (session_id, paused_at) VALUES ($1, $2)",
+		sessionID, session.PausedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ResumeSession ends the current pause of an active session and adds it
+// to the paused total. Returns nil if the session isn't paused.
+func (r *FocusRepository) ResumeSession(ctx context.Context, sessionID uuid.UUID) (*domain.FocusSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE focus_sessions
+		SET paused_seconds = paused_seconds + GREATEST(0, EXTRACT(EPOCH FROM (NOW() - paused_at)))::INTEGER,
+		    paused_at = NULL,
+		    last_heartbeat_at = NOW()
+		WHERE id = $1 AND ended_at IS NULL AND paused_at IS NOT NULL
+		RETURNING ` + focusSessionColumns
+
+	session, err := scanFocusSession(tx.QueryRowContext(ctx, query, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE focus_session_pauses SET resumed_at = NOW() WHERE session_id = $1 AND resumed_at IS NULL",
+		sessionID,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// EndAbandoned ends every active session with no heartbeat for timeout,
+// closing it at its last heartbeat so only real presence is credited
+func (r *FocusRepository) EndAbandoned(ctx context.Context, timeout time.Duration) ([]domain.FocusSession, error) {
+	query := `
+		UPDATE focus_sessions
+		SET ended_at = last_heartbeat_at,
+		    auto_ended = TRUE
+		WHERE ended_at IS NULL
+		  AND last_heartbeat_at < NOW() - make_interval(secs => $1)
+		RETURNING ` + focusSessionColumns
+
+	return r.endSessions(ctx, query, timeout.Seconds())
+}
+
+// AutoEndOverdue ends every running session whose net focus time is grace
+// past its plan. The session is closed at the end of its plan (or its last
+// heartbeat, if earlier), not now, so the forgotten time isn't credited.
+// Paused sessions are left alone: their plan isn't used up.
+func (r *FocusRepository) AutoEndOverdue(ctx context.Context, grace time.Duration) ([]domain.FocusSession, error) {
+	query := `
+		UPDATE focus_sessions
+		SET ended_at = LEAST(started_at + make_interval(mins => planned_minutes, secs => paused_seconds), last_heartbeat_at),
+		    auto_ended = TRUE
+		WHERE ended_at IS NULL
+		  AND paused_at IS NULL
+		  AND planned_minutes IS NOT NULL
+		  AND started_at + make_interval(mins => planned_minutes, secs => paused_seconds + $1::FLOAT8) < NOW()
+		RETURNING ` + focusSessionColumns
+
+	return r.endSessions(ctx, query, grace.Seconds())
+}
+
+// endSessions runs a bulk UPDATE ... RETURNING and scans the ended sessions
+func (r *FocusRepository) endSessions(ctx context.Context, query string, args ...interface{}) ([]domain.FocusSession, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.FocusSession{}
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+// SetGoalMet stores whether the goal of a session was met
+func (r *FocusRepository) SetGoalMet(ctx context.Context, sessionID uuid.UUID, goalMet bool) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE focus_sessions SET goal_met = $2 WHERE id = $1",
+		sessionID, goalMet,
+	)
+	return err
+}
+
+// SetTags replaces the tags of a session
+func (r *FocusRepository) SetTags(ctx context.Context, sessionID uuid.UUID, tags []string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE focus_sessions SET tags = $2 WHERE id = $1",
+		sessionID, pq.Array(tags),
+	)
+	return err
+}
+
+// SetNote stores the post-session note of a session
+func (r *FocusRepository) SetNote(ctx context.Context, sessionID uuid.UUID, note string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE focus_sessions SET note = NULLIF($2, '') WHERE id = $1",
+		sessionID, note,
+	)
+	return err
+}
+
+// SetCompletedPomodoros stores the work blocks finished in a session
+func (r *FocusRepository) SetCompletedPomodoros(ctx context.Context, sessionID uuid.UUID, completed int) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE focus_sessions SET completed_pomodoros = $2 WHERE id = $1",
+		sessionID, completed,
+	)
+	return err
+}
+
+// GetActiveBySquad returns the active focus sessions for a squad that sent
+// a heartbeat within timeout, including broadcast solo sessions of its
+// members. Abandoned ones are hidden until the sweeper closes them.
+func (r *FocusRepository) GetActiveBySquad(ctx context.Context, squadID uuid.UUID, timeout time.Duration) ([]domain.ActiveSession, error) {
+	query := `
+		SELECT fs.user_id, p.display_name, p.avatar_url, fs.started_at,
+		       GREATEST(0, EXTRACT(EPOCH FROM (COALESCE(fs.paused_at, NOW()) - fs.started_at))::INTEGER - fs.paused_seconds),
+		       fs.paused_at,
+		       fs.pomodoro_work_minutes, fs.pomodoro_short_break_minutes,
+		       fs.pomodoro_long_break_minutes, fs.pomodoro_cycles,
+		       fs.planned_minutes, fs.goal, fs.squad_id IS NULL, fs.last_heartbeat_at
 		FROM focus_sessions fs
 		JOIN profiles p ON p.id = fs.user_id
-		WHERE fs.squad_id = $1 AND fs.ended_at IS NULL
+		WHERE (
+		        fs.squad_id = $1
+		        OR (fs.broadcast AND EXISTS (
+		            SELECT 1 FROM squad_members sm
+		            WHERE sm.squad_id = $1 AND sm.user_id = fs.user_id
+		        ))
+		      )
+		  AND fs.ended_at IS NULL
+		  AND fs.last_heartbeat_at >= NOW() - make_interval(secs => $2)
 		ORDER BY fs.started_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, squadID)
+	rows, err := r.db.QueryContext(ctx, query, squadID, timeout.Seconds())
 	if err != nil {
 		return nil, err
 	}
@@ -118,38 +509,83 @@ func (r *FocusRepository) GetActiveBySquad(ctx context.Context, squadID uuid.UUI
 	sessions := []domain.ActiveSession{}
 	for rows.Next() {
 		session := domain.ActiveSession{}
+		var pomodoro nullPomodoro
 		if err := rows.Scan(
 			&session.UserID,
 			&session.DisplayName,
 			&session.AvatarURL,
 			&session.StartedAt,
-			&session.DurationMinutes,
+			&session.FocusedSeconds,
+			&session.PausedAt,
+			&pomodoro.work,
+			&pomodoro.shortBreak,
+			&pomodoro.longBreak,
+			&pomodoro.cycles,
+			&session.PlannedMinutes,
+			&session.Goal,
+			&session.Solo,
+			&session.LastHeartbeatAt,
 		); err != nil {
 			return nil, err
 		}
+		session.DurationMinutes = session.FocusedSeconds / 60
+		session.Paused = session.PausedAt != nil
+		// The service derives the current phase from the config
+		if config := pomodoro.config(); config != nil {
+			session.Pomodoro = &domain.PomodoroState{Config: *config}
+		}
 		sessions = append(sessions, session)
 	}
 
 	return sessions, nil
 }
 
-// GetHistoryBySquad returns completed focus sessions for a squad within the last N days
-func (r *FocusRepository) GetHistoryBySquad(ctx context.Context, squadID uuid.UUID, days int) ([]domain.FocusHistory, error) {
-	cutoff := time.Now().AddDate(0, 0, -days)
+// ListHistory returns a page of ended focus sessions matching the filter,
+// newest first, starting after the cursor if one is given
+func (r *FocusRepository) ListHistory(ctx context.Context, filter domain.FocusHistoryFilter, after *domain.FocusHistoryCursor, limit int) ([]domain.FocusHistory, error) {
+	conditions := []string{"fs.ended_at IS NOT NULL"}
+	args := []interface{}{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.SquadID != nil {
+		conditions = append(conditions, "fs.squad_id = "+arg(*filter.SquadID))
+	}
+	if filter.UserID != nil {
+		conditions = append(conditions, "fs.user_id = "+arg(*filter.UserID))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "fs.started_at >= "+arg(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "fs.started_at < "+arg(*filter.To))
+	}
+	if filter.MinMinutes > 0 {
+		conditions = append(conditions, "fs.duration_minutes >= "+arg(filter.MinMinutes))
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, "fs.tags @> "+arg(pq.Array(filter.Tags))+"::TEXT[]")
+	}
+	if after != nil {
+		conditions = append(conditions, "(fs.started_at, fs.id) < ("+arg(after.StartedAt)+", "+arg(after.ID)+")")
+	}
 
 	query := `
-		SELECT fs.id, fs.user_id, p.display_name, p.avatar_url, 
-		       fs.started_at, fs.ended_at, fs.duration_minutes
+		SELECT fs.id, fs.squad_id, s.name, fs.user_id, p.display_name, p.avatar_url,
+		       fs.started_at, fs.ended_at, fs.duration_minutes, fs.paused_seconds / 60,
+		       fs.pomodoro_work_minutes, fs.pomodoro_short_break_minutes,
+		       fs.pomodoro_long_break_minutes, fs.pomodoro_cycles, fs.completed_pomodoros,
+		       fs.planned_minutes, fs.goal, fs.goal_met, fs.auto_ended, fs.tags, fs.note, fs.manual
 		FROM focus_sessions fs
+		LEFT JOIN squads s ON s.id = fs.squad_id
 		JOIN profiles p ON p.id = fs.user_id
-		WHERE fs.squad_id = $1 
-		  AND fs.ended_at IS NOT NULL
-		  AND fs.started_at >= $2
-		ORDER BY fs.started_at DESC
-		LIMIT 100
-	`
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY fs.started_at DESC, fs.id DESC
+		LIMIT ` + arg(limit)
 
-	rows, err := r.db.QueryContext(ctx, query, squadID, cutoff)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -158,23 +594,248 @@ func (r *FocusRepository) GetHistoryBySquad(ctx context.Context, squadID uuid.UU
 	history := []domain.FocusHistory{}
 	for rows.Next() {
 		h := domain.FocusHistory{}
+		var pomodoro nullPomodoro
 		if err := rows.Scan(
 			&h.ID,
+			&h.SquadID,
+			&h.SquadName,
 			&h.UserID,
 			&h.DisplayName,
 			&h.AvatarURL,
 			&h.StartedAt,
 			&h.EndedAt,
 			&h.DurationMinutes,
+			&h.PausedMinutes,
+			&pomodoro.work,
+			&pomodoro.shortBreak,
+			&pomodoro.longBreak,
+			&pomodoro.cycles,
+			&h.CompletedPomodoros,
+			&h.PlannedMinutes,
+			&h.Goal,
+			&h.GoalMet,
+			&h.AutoEnded,
+			pq.Array(&h.Tags),
+			&h.Note,
+			&h.Manual,
 		); err != nil {
 			return nil, err
 		}
+		h.Pomodoro = pomodoro.config()
 		history = append(history, h)
 	}
 
 	return history, nil
 }
 
+// GetEndedByUser returns a user's ended sessions in every squad that
+// started since the given time, oldest first
+func (r *FocusRepository) GetEndedByUser(ctx context.Context, userID uuid.UUID, since time.Time) ([]domain.FocusSession, error) {
+	query := `SELECT ` + focusSessionColumns + `
+		FROM focus_sessions
+		WHERE user_id = $1 AND ended_at IS NOT NULL AND started_at >= $2
+		ORDER BY started_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.FocusSession{}
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+// GetUserTimezone returns a user's IANA timezone, UTC if unset
+func (r *FocusRepository) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	var timezone string
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COALESCE(timezone, 'UTC') FROM profiles WHERE id = $1",
+		userID,
+	).Scan(&timezone)
+	if err == sql.ErrNoRows {
+		return "UTC", nil
+	}
+	return timezone, err
+}
+
+// GetFavouriteTags returns a user's favourite tags in display order
+func (r *FocusRepository) GetFavouriteTags(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT tag FROM favourite_tags WHERE user_id = $1 ORDER BY position ASC",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// SetFavouriteTags replaces a user's favourite tags
+func (r *FocusRepository) SetFavouriteTags(ctx context.Context, userID uuid.UUID, tags []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM favourite_tags WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO favourite_tags (user_id, tag, position)
+			SELECT $1, t.tag, t.position - 1
+			FROM unnest($2::TEXT[]) WITH ORDINALITY AS t(tag, position)
+		`, userID, pq.Array(tags)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetRecentTags returns the tags a user used most recently
+func (r *FocusRepository) GetRecentTags(ctx context.Context, userID uuid.UUID, limit int) ([]domain.FocusTagUsage, error) {
+	query := `
+		SELECT t.tag, COUNT(*), MAX(fs.started_at)
+		FROM focus_sessions fs
+		CROSS JOIN LATERAL unnest(fs.tags) AS t(tag)
+		WHERE fs.user_id = $1
+		GROUP BY t.tag
+		ORDER BY MAX(fs.started_at) DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []domain.FocusTagUsage{}
+	for rows.Next() {
+		var u domain.FocusTagUsage
+		if err := rows.Scan(&u.Tag, &u.Sessions, &u.LastUsedAt); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, nil
+}
+
+// GetSubjectMinutes totals a user's ended sessions that started in
+// [from, to) per tag, most minutes first, along with the minutes of
+// untagged sessions and of all sessions
+func (r *FocusRepository) GetSubjectMinutes(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.SubjectMinutes, int, int, error) {
+	query := `
+		SELECT t.tag, COUNT(*), COALESCE(SUM(fs.duration_minutes), 0)
+		FROM focus_sessions fs
+		LEFT JOIN LATERAL unnest(fs.tags) AS t(tag) ON TRUE
+		WHERE fs.user_id = $1
+		  AND fs.ended_at IS NOT NULL
+		  AND fs.started_at >= $2
+		  AND fs.started_at < $3
+		GROUP BY t.tag
+		ORDER BY 3 DESC, 1 ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	subjects := []domain.SubjectMinutes{}
+	untagged := 0
+	for rows.Next() {
+		var tag sql.NullString
+		var s domain.SubjectMinutes
+		if err := rows.Scan(&tag, &s.Sessions, &s.Minutes); err != nil {
+			return nil, 0, 0, err
+		}
+		if !tag.Valid {
+			untagged = s.Minutes
+			continue
+		}
+		s.Tag = tag.String
+		subjects = append(subjects, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	var total int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(duration_minutes), 0)
+		FROM focus_sessions
+		WHERE user_id = $1 AND ended_at IS NOT NULL AND started_at >= $2 AND started_at < $3
+	`, userID, from, to).Scan(&total)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return subjects, untagged, total, nil
+}
+
+// SetBroadcast shows or hides the user's active solo session in their
+// squads. It returns nil if the user has no active solo session.
+func (r *FocusRepository) SetBroadcast(ctx context.Context, userID uuid.UUID, broadcast bool) (*domain.FocusSession, error) {
+	query := `
+		UPDATE focus_sessions
+		SET broadcast = $2
+		WHERE user_id = $1 AND ended_at IS NULL AND squad_id IS NULL
+		RETURNING ` + focusSessionColumns
+
+	session, err := scanFocusSession(r.db.QueryRowContext(ctx, query, userID, broadcast))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+// GetUserSquadIDs returns the squads a user belongs to
+func (r *FocusRepository) GetUserSquadIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT squad_id FROM squad_members WHERE user_id = $1",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	squadIDs := []uuid.UUID{}
+	for rows.Next() {
+		var squadID uuid.UUID
+		if err := rows.Scan(&squadID); err != nil {
+			return nil, err
+		}
+		squadIDs = append(squadIDs, squadID)
+	}
+	return squadIDs, rows.Err()
+}
+
 // IsMemberOfSquad checks if a user is a member of a squad
 func (r *FocusRepository) IsMemberOfSquad(ctx context.Context, userID, squadID uuid.UUID) (bool, error) {
 	var exists bool
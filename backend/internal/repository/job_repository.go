@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// JobRepository handles persistence and locking for scheduled jobs
+type JobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// EnsureJob registers a job row, keeping its paused flag but refreshing the schedule
+func (r *JobRepository) EnsureJob(ctx context.Context, name, schedule string) error {
+	query := `
+		INSERT INTO scheduled_jobs (name, schedule)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE
+		SET schedule = EXCLUDED.schedule, updated_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, name, schedule)
+	return err
+}
+
+// IsPaused reports whether a job has been paused by an admin
+func (r *JobRepository) IsPaused(ctx context.Context, name string) (bool, error) {
+	var paused bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT paused FROM scheduled_jobs WHERE name = $1",
+		name,
+	).Scan(&paused)
+	if err == sql.ErrNoRows {
+		return false, domain.ErrJobNotFound
+	}
+	return paused, err
+}
+
+// SetPaused pauses or resumes a job across all replicas
+func (r *JobRepository) SetPaused(ctx context.Context, name string, paused bool) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE scheduled_jobs SET paused = $2, updated_at = NOW() WHERE name = $1",
+		name, paused,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrJobNotFound
+	}
+
+	return nil
+}
+
+// TryLock takes a session-level Postgres advisory lock for the job.
+// The lock lives on a dedicated connection, so only one replica can hold it
+// until release is called.
+func (r *JobRepository) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx,
+		"SELECT pg_try_advisory_lock(hashtext($1))",
+		"job:"+name,
+	).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Close()
+		return nil, false, err
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(context.Background(),
+			"SELECT pg_advisory_unlock(hashtext($1))",
+			"job:"+name,
+		); err != nil {
+			log.Printf("Failed to release advisory lock for job %s: %v", name, err)
+		}
+		conn.Close()
+	}
+
+	return release, true, nil
+}
+
+// HasRunSince checks whether any replica already started the job at or after a time
+func (r *JobRepository) HasRunSince(ctx context.Context, name string, since time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM job_runs WHERE job_name = $1 AND started_at >= $2)",
+		name, since,
+	).Scan(&exists)
+	return exists, err
+}
+
+// StartRun records the beginning of a job run
+func (r *JobRepository) StartRun(ctx context.Context, name, trigger, instance string) (*domain.JobRun, error) {
+	query := `
+		INSERT INTO job_runs (job_name, status, trigger, instance)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, job_name, status, trigger, instance, started_at, finished_at, error
+	`
+
+	run := &domain.JobRun{}
+	err := r.db.QueryRowContext(ctx, query, name, domain.JobRunStatusRunning, trigger, instance).Scan(
+		&run.ID,
+		&run.JobName,
+		&run.Status,
+		&run.Trigger,
+		&run.Instance,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// FinishRun marks a run as succeeded or failed
+func (r *JobRepository) FinishRun(ctx context.Context, runID uuid.UUID, runErr error) error {
+	status := domain.JobRunStatusSucceeded
+	var errMsg *string
+	if runErr != nil {
+		status = domain.JobRunStatusFailed
+		msg := runErr.Error()
+		errMsg = &msg
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE job_runs SET status = $2, error = $3, finished_at = NOW() WHERE id = $1",
+		runID, status, errMsg,
+	)
+	return err
+}
+
+// GetLastRun returns the most recent run of a job, if any
+func (r *JobRepository) GetLastRun(ctx context.Context, name string) (*domain.JobRun, error) {
+	runs, err := r.ListRuns(ctx, name, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0], nil
+}
+
+// ListRuns returns the run history of a job (recent first)
+func (r *JobRepository) ListRuns(ctx context.Context, name string, limit int) ([]domain.JobRun, error) {
+	query := `
+		SELECT id, job_name, status, trigger, instance, started_at, finished_at, error
+		FROM job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []domain.JobRun{}
+	for rows.Next() {
+		run := domain.JobRun{}
+		if err := rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Status,
+			&run.Trigger,
+			&run.Instance,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.Error,
+		); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
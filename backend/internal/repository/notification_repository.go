@@ -35,6 +35,35 @@ func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notificat
 	).Scan(&n.ID, &n.CreatedAt, &n.IsRead)
 }
 
+// CreateMany inserts notifications for several users in one transaction,
+// so a failed fan-out leaves none behind to be duplicated on retry
+func (r *NotificationRepository) CreateMany(ctx context.Context, notifications []*domain.Notification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notifications (user_id, type, title, message, metadata)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, is_read
+	`
+
+	for _, n := range notifications {
+		if n.Metadata == nil {
+			n.Metadata = json.RawMessage("{}")
+		}
+		if err := tx.QueryRowContext(ctx, query,
+			n.UserID, n.Type, n.Title, n.Message, n.Metadata,
+		).Scan(&n.ID, &n.CreatedAt, &n.IsRead); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // List fetches notifications for a user (recent first)
 func (r *NotificationRepository) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Notification, int, error) {
 	// 1. Get total count
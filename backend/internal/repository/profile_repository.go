@@ -20,11 +20,16 @@ func NewProfileRepository(db *sql.DB) *ProfileRepository {
 	return &ProfileRepository{db: db}
 }
 
+// longestStreakColumn selects the longest streak for the profile's current
+// mode (days for daily mode, weeks at the current target for weekly mode)
+const longestStreakColumn = `CASE WHEN streak_mode LIKE 'weekly:%' THEN longest_weekly_streaks[split_part(streak_mode, ':', 2)::INTEGER] ELSE longest_streak END`
+
 // GetByID retrieves a profile by user ID
 func (r *ProfileRepository) GetByID(ctx context.Context, userID uuid.UUID) (*domain.Profile, error) {
 	query := `
 		SELECT id, email, display_name, avatar_url, is_edu_verified, 
-		       timezone, consistency_score, current_streak, longest_streak,
+		       timezone, streak_mode, consistency_score, current_streak,
+		       `+longestStreakColumn+`,
 		       created_at, updated_at
 		FROM profiles
 		WHERE id = $1
@@ -38,6 +43,7 @@ func (r *ProfileRepository) GetByID(ctx context.Context, userID uuid.UUID) (*dom
 		&profile.AvatarURL,
 		&profile.IsEduVerified,
 		&profile.Timezone,
+		&profile.StreakMode,
 		&profile.ConsistencyScore,
 		&profile.CurrentStreak,
 		&profile.LongestStreak,
@@ -88,7 +94,8 @@ func (r *ProfileRepository) Update(ctx context.Context, userID uuid.UUID, req *d
 
 	query += `
 		RETURNING id, email, display_name, avatar_url, is_edu_verified,
-		          timezone, consistency_score, current_streak, longest_streak,
+		          timezone, streak_mode, consistency_score, current_streak,
+		          `+longestStreakColumn+`,
 		          created_at, updated_at
 	`
 
@@ -100,6 +107,7 @@ func (r *ProfileRepository) Update(ctx context.Context, userID uuid.UUID, req *d
 		&profile.AvatarURL,
 		&profile.IsEduVerified,
 		&profile.Timezone,
+		&profile.StreakMode,
 		&profile.ConsistencyScore,
 		&profile.CurrentStreak,
 		&profile.LongestStreak,
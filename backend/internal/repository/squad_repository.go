@@ -78,7 +78,8 @@ func (r *SquadRepository) GetByID(ctx context.Context, squadID uuid.UUID) (*doma
 func (r *SquadRepository) GetDetailByID(ctx context.Context, squadID uuid.UUID) (*domain.SquadDetail, error) {
 	// Get squad info
 	squadQuery := `
-		SELECT id, name, description, invite_code, owner_id, max_members, created_at
+		SELECT id, name, description, invite_code, owner_id, max_members, created_at,
+		       current_streak, longest_streak, last_streak_date, streak_quorum
 		FROM squads WHERE id = $1
 	`
 	
@@ -91,6 +92,10 @@ func (r *SquadRepository) GetDetailByID(ctx context.Context, squadID uuid.UUID)
 		&detail.OwnerID,
 		&detail.MaxMembers,
 		&detail.CreatedAt,
+		&detail.Streak.CurrentStreak,
+		&detail.Streak.LongestStreak,
+		&detail.Streak.LastStreakDate,
+		&detail.Streak.Quorum,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -101,11 +106,16 @@ func (r *SquadRepository) GetDetailByID(ctx context.Context, squadID uuid.UUID)
 
 	// Get members
 	membersQuery := `
-		SELECT sm.user_id, p.display_name, p.avatar_url, sm.role, sm.joined_at
+		SELECT sm.user_id, p.display_name, p.avatar_url, sm.role, sm.joined_at,
+		       EXISTS (
+		           SELECT 1 FROM activity_logs al
+		           WHERE al.user_id = sm.user_id
+		             AND al.activity_date = (NOW() AT TIME ZONE COALESCE(p.timezone, 'UTC'))::DATE
+		       ) AS active_today
 		FROM squad_members sm
 		JOIN profiles p ON p.id = sm.user_id
 		WHERE sm.squad_id = $1
-		ORDER BY sm.role DESC, sm.joined_at ASC
+		ORDER BY CASE sm.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, sm.joined_at ASC
 	`
 	
 	rows, err := r.db.QueryContext(ctx, membersQuery, squadID)
@@ -123,6 +133,7 @@ func (r *SquadRepository) GetDetailByID(ctx context.Context, squadID uuid.UUID)
 			&member.AvatarURL,
 			&member.Role,
 			&member.JoinedAt,
+			&member.ActiveToday,
 		); err != nil {
 			return nil, err
 		}
@@ -187,6 +198,12 @@ func (r *SquadRepository) Update(ctx context.Context, squadID uuid.UUID, req *do
 		args = append(args, *req.Description)
 		argNum++
 	}
+	if req.StreakQuorum != nil {
+		// 0 means every member, stored as NULL
+		setParts = append(setParts, "streak_quorum = NULLIF($"+string(rune('0'+argNum))+"::INTEGER, 0)")
+		args = append(args, *req.StreakQuorum)
+		argNum++
+	}
 
 	if len(setParts) == 0 {
 		return r.GetByID(ctx, squadID)
@@ -216,6 +233,47 @@ func (r *SquadRepository) Update(ctx context.Context, squadID uuid.UUID, req *do
 	return squad, nil
 }
 
+// GetStreakLeaderboard returns top N squads by current squad streak
+func (r *SquadRepository) GetStreakLeaderboard(ctx context.Context, limit int) ([]domain.SquadLeaderboardEntry, error) {
+	query := `
+		SELECT
+			s.id,
+			s.name,
+			(SELECT COUNT(*) FROM squad_members sm WHERE sm.squad_id = s.id) AS member_count,
+			s.current_streak,
+			s.longest_streak,
+			ROW_NUMBER() OVER (ORDER BY s.current_streak DESC, s.longest_streak DESC) AS rank
+		FROM squads s
+		WHERE s.current_streak > 0 AND s.archived_at IS NULL
+		ORDER BY s.current_streak DESC, s.longest_streak DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leaderboard := []domain.SquadLeaderboardEntry{}
+	for rows.Next() {
+		entry := domain.SquadLeaderboardEntry{}
+		if err := rows.Scan(
+			&entry.SquadID,
+			&entry.Name,
+			&entry.MemberCount,
+			&entry.CurrentStreak,
+			&entry.LongestStreak,
+			&entry.Rank,
+		); err != nil {
+			return nil, err
+		}
+		leaderboard = append(leaderboard, entry)
+	}
+
+	return leaderboard, nil
+}
+
 // Delete deletes a squad
 func (r *SquadRepository) Delete(ctx context.Context, squadID uuid.UUID) error {
 	_, err := r.db.ExecContext(ctx, "DELETE FROM squads WHERE id = $1", squadID)
@@ -297,3 +355,145 @@ func (r *SquadRepository) IsMember(ctx context.Context, squadID, userID uuid.UUI
 	).Scan(&exists)
 	return exists, err
 }
+
+// GetMemberRole returns a user's role in a squad, or "" if they are not a
+// member
+func (r *SquadRepository) GetMemberRole(ctx context.Context, squadID, userID uuid.UUID) (domain.SquadRole, error) {
+	var role domain.SquadRole
+	err := r.db.QueryRowContext(ctx,
+		"SELECT role FROM squad_members WHERE squad_id = $1 AND user_id = $2",
+		squadID, userID,
+	).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// SetMemberRole changes the role of a squad member other than the owner
+func (r *SquadRepository) SetMemberRole(ctx context.Context, squadID, userID uuid.UUID, role domain.SquadRole) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE squad_members SET role = $3 WHERE squad_id = $1 AND user_id = $2 AND role <> 'owner'",
+		squadID, userID, role,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrNotSquadMember
+	}
+
+	return nil
+}
+
+// TransferOwnership hands a squad from its owner to another member, who
+// becomes owner while the previous owner becomes an admin
+func (r *SquadRepository) TransferOwnership(ctx context.Context, squadID, fromUserID, toUserID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE squads SET owner_id = $3 WHERE id = $1 AND owner_id = $2",
+		squadID, fromUserID, toUserID,
+	)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotSquadOwner
+	}
+
+	result, err = tx.ExecContext(ctx,
+		"UPDATE squad_members SET role = 'owner' WHERE squad_id = $1 AND user_id = $2",
+		squadID, toUserID,
+	)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotSquadMember
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE squad_members SET role = 'admin' WHERE squad_id = $1 AND user_id = $2",
+		squadID, fromUserID,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// LeaveSquad removes a member who leaves on their own. If the owner
+// leaves, the longest-tenured admin takes over, else the longest-tenured
+// member; if nobody is left, the squad is archived.
+func (r *SquadRepository) LeaveSquad(ctx context.Context, squadID, userID uuid.UUID) (*domain.SquadDeparture, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Serialize departures so two leaving owners can't hand over to each other
+	departure := &domain.SquadDeparture{}
+	err = tx.QueryRowContext(ctx,
+		"SELECT owner_id FROM squads WHERE id = $1 AND archived_at IS NULL FOR UPDATE",
+		squadID,
+	).Scan(&departure.PreviousOwnerID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSquadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"DELETE FROM squad_members WHERE squad_id = $1 AND user_id = $2",
+		squadID, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, domain.ErrNotSquadMember
+	}
+
+	var successor uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT user_id FROM squad_members
+		WHERE squad_id = $1
+		ORDER BY (role = 'admin') DESC, joined_at ASC
+		LIMIT 1
+	`, squadID).Scan(&successor)
+	switch {
+	case err == sql.ErrNoRows:
+		if _, err := tx.ExecContext(ctx, "UPDATE squads SET archived_at = NOW() WHERE id = $1", squadID); err != nil {
+			return nil, err
+		}
+		departure.Archived = true
+	case err != nil:
+		return nil, err
+	case departure.PreviousOwnerID == userID:
+		if _, err := tx.ExecContext(ctx, "UPDATE squads SET owner_id = $2 WHERE id = $1", squadID, successor); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE squad_members SET role = 'owner' WHERE squad_id = $1 AND user_id = $2",
+			squadID, successor,
+		); err != nil {
+			return nil, err
+		}
+		departure.NewOwnerID = &successor
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return departure, nil
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // StreakRepository handles database operations for streak management
@@ -19,12 +24,15 @@ func NewStreakRepository(db *sql.DB) *StreakRepository {
 	return &StreakRepository{db: db}
 }
 
-// LogActivity logs a daily activity using the smart SQL function
+// LogActivity records one activity using the smart SQL function.
+// IsNew reports the first activity of the user's local day; the streak
+// itself is computed by the service.
 func (r *StreakRepository) LogActivity(
 	ctx context.Context,
 	userID string,
 	activityType domain.ActivityType,
 	metadata map[string]interface{},
+	date *time.Time, // Local date to credit; nil = today
 ) (*domain.LogActivityResponse, error) {
 	// Convert metadata to JSONB
 	metadataJSON, err := json.Marshal(metadata)
@@ -33,167 +41,282 @@ func (r *StreakRepository) LogActivity(
 	}
 
 	query := `
-		SELECT success, activity_id, activity_date, is_new
-		FROM log_daily_activity($1::UUID, $2::TEXT, $3::JSONB)
+		SELECT success, activity_id, activity_date, is_new, is_duplicate
+		FROM log_daily_activity($1::UUID, $2::TEXT, $3::JSONB, $4::DATE)
 	`
 
 	var response domain.LogActivityResponse
 	var activityDate time.Time
 
-	err = r.db.QueryRowContext(ctx, query, userID, string(activityType), metadataJSON).Scan(
+	var localDate interface{}
+	if date != nil {
+		localDate = date.Format("2006-01-02")
+	}
+
+	err = r.db.QueryRowContext(ctx, query, userID, string(activityType), metadataJSON, localDate).Scan(
 		&response.Success,
 		&response.ActivityID,
 		&activityDate,
 		&response.IsNew,
+		&response.Duplicate,
 	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+		return nil, domain.ErrProfileNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	response.ActivityDate = activityDate
 
-	// Get current streak after logging
-	streakData, err := r.calculateStreak(ctx, userID)
-	if err == nil && streakData != nil {
-		response.CurrentStreak = streakData.CurrentStreak
-	}
-
 	return &response, nil
 }
 
-// calculateStreak calls the SQL function to calculate streak
-func (r *StreakRepository) calculateStreak(ctx context.Context, userID string) (*domain.StreakCalculationResult, error) {
-	query := `
-		SELECT current_streak, longest_streak, last_active_date
-		FROM calculate_streak($1::UUID)
-	`
+// streakInputsQuery selects everything the streak engine needs per user.
+// Dates are aggregated into arrays so a whole page is a single round trip;
+// a day with several activities appears once, with its focus minutes summed.
+const streakInputsQuery = `
+	SELECT
+		p.id,
+		p.display_name,
+		COALESCE(p.timezone, 'UTC'),
+		p.streak_mode,
+		p.created_at,
+		p.current_streak,
+		CASE WHEN p.streak_mode LIKE 'weekly:%' THEN p.longest_weekly_streaks[split_part(p.streak_mode, ':', 2)::INTEGER] ELSE p.longest_streak END,
+		p.freeze_tokens,
+		COALESCE(act.dates, '{}'),
+		COALESCE(act.minutes, '{}'),
+		COALESCE((
+			SELECT ARRAY_AGG(sf.freeze_date ORDER BY sf.freeze_date)
+			FROM streak_freezes sf
+			WHERE sf.user_id = p.id AND sf.event = 'used'
+		), '{}')
+	FROM profiles p
+	LEFT JOIN LATERAL (
+		-- One entry per active day; minutes[i] is the focus time on dates[i]
+		SELECT ARRAY_AGG(d.activity_date ORDER BY d.activity_date) AS dates,
+		       ARRAY_AGG(d.minutes ORDER BY d.activity_date) AS minutes
+		FROM (
+			SELECT al.activity_date, SUM(al.focus_minutes) AS minutes
+			FROM activity_logs al
+			WHERE al.user_id = p.id
+			GROUP BY al.activity_date
+		) d
+	) act ON TRUE
+`
 
-	result := &domain.StreakCalculationResult{}
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&result.CurrentStreak,
-		&result.LongestStreak,
-		&result.LastActiveDate,
-	)
+// GetStreakInputs returns the activity and freeze dates for one user
+func (r *StreakRepository) GetStreakInputs(ctx context.Context, userID string) (*domain.StreakInputs, error) {
+	row := r.db.QueryRowContext(ctx, streakInputsQuery+" WHERE p.id = $1", userID)
+
+	inputs, err := scanStreakInputs(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrProfileNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	return inputs, nil
 }
 
-// GetUserStreak returns full streak data for a user
-func (r *StreakRepository) GetUserStreak(
-	ctx context.Context,
-	userID string,
-	includeHistory bool,
-	historyDays int,
-) (*domain.StreakData, error) {
-	// Get streak calculation from SQL function
-	streakCalc, err := r.calculateStreak(ctx, userID)
+// ListStreakInputs returns a page of users ordered by id, starting after
+// afterID (keyset pagination for batched recalculation)
+func (r *StreakRepository) ListStreakInputs(ctx context.Context, afterID string, limit int) ([]domain.StreakInputs, error) {
+	query := streakInputsQuery + `
+		WHERE p.id > $1::UUID
+		ORDER BY p.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Get consistency score
-	consistencyQuery := `
-		SELECT get_consistency_score($1::UUID, $2::INTEGER)
-	`
-	var consistencyScore int
-	err = r.db.QueryRowContext(ctx, consistencyQuery, userID, 0).Scan(&consistencyScore)
-	if err != nil {
-		return nil, err
+	page := []domain.StreakInputs{}
+	for rows.Next() {
+		inputs, err := scanStreakInputs(rows)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, *inputs)
 	}
 
-	// Get total active days
-	totalActiveDaysQuery := `
-		SELECT COUNT(DISTINCT activity_date)
-		FROM activity_logs
-		WHERE user_id = $1
-	`
-	var totalActiveDays int
-	err = r.db.QueryRowContext(ctx, totalActiveDaysQuery, userID).Scan(&totalActiveDays)
-	if err != nil {
+	return page, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStreakInputs(row rowScanner) (*domain.StreakInputs, error) {
+	var inputs domain.StreakInputs
+	var activeDates, frozenDates pq.StringArray
+	var focusMinutes pq.Int64Array
+
+	if err := row.Scan(
+		&inputs.UserID,
+		&inputs.DisplayName,
+		&inputs.Timezone,
+		&inputs.StreakMode,
+		&inputs.JoinedAt,
+		&inputs.CurrentStreak,
+		&inputs.LongestStreak,
+		&inputs.FreezeTokens,
+		&activeDates,
+		&focusMinutes,
+		&frozenDates,
+	); err != nil {
 		return nil, err
 	}
 
-	streakData := &domain.StreakData{
-		UserID:           userID,
-		CurrentStreak:    streakCalc.CurrentStreak,
-		LongestStreak:    streakCalc.LongestStreak,
-		LastActiveDate:   streakCalc.LastActiveDate,
-		ConsistencyScore: consistencyScore,
-		TotalActiveDays:  totalActiveDays,
+	var err error
+	if inputs.ActiveDates, err = parseDates(activeDates); err != nil {
+		return nil, err
+	}
+	if inputs.FrozenDates, err = parseDates(frozenDates); err != nil {
+		return nil, err
 	}
 
-	// Optionally include activity history
-	if includeHistory && historyDays > 0 {
-		history, err := r.GetActivityHistory(ctx, userID, historyDays)
-		if err == nil {
-			streakData.StreakHistory = history
+	inputs.FocusMinutes = make(map[time.Time]int, len(focusMinutes))
+	for i, minutes := range focusMinutes {
+		if i < len(inputs.ActiveDates) && minutes > 0 {
+			inputs.FocusMinutes[inputs.ActiveDates[i]] = int(minutes)
 		}
 	}
 
-	return streakData, nil
+	return &inputs, nil
 }
 
-// GetActivityHistory returns activity status for last N days
-func (r *StreakRepository) GetActivityHistory(
-	ctx context.Context,
-	userID string,
-	days int,
-) ([]domain.ActivityDay, error) {
+// GetDailyActivity returns per-day activity aggregates for one user from
+// the given local date onwards, most recent first
+func (r *StreakRepository) GetDailyActivity(ctx context.Context, userID string, from time.Time) ([]domain.DailyActivity, error) {
 	query := `
-		WITH date_series AS (
-			SELECT 
-				generate_series(
-					(NOW() - ($2 || ' days')::INTERVAL)::DATE,
-					NOW()::DATE,
-					'1 day'::INTERVAL
-				)::DATE AS date
-		)
-		SELECT 
-			ds.date,
-			CASE WHEN al.activity_date IS NOT NULL THEN true ELSE false END AS active
-		FROM date_series ds
-		LEFT JOIN activity_logs al ON al.activity_date = ds.date AND al.user_id = $1
-		ORDER BY ds.date DESC
+		SELECT activity_date,
+		       COUNT(*),
+		       COALESCE(SUM(focus_minutes), 0),
+		       ARRAY_AGG(DISTINCT activity_type ORDER BY activity_type)
+		FROM activity_logs
+		WHERE user_id = $1 AND activity_date >= $2::DATE
+		GROUP BY activity_date
+		ORDER BY activity_date DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, days)
+	rows, err := r.db.QueryContext(ctx, query, userID, from.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	history := []domain.ActivityDay{}
+	days := []domain.DailyActivity{}
 	for rows.Next() {
-		var day domain.ActivityDay
-		if err := rows.Scan(&day.Date, &day.Active); err != nil {
+		var day domain.DailyActivity
+		var date time.Time
+		var types pq.StringArray
+		if err := rows.Scan(&date, &day.ActivityCount, &day.FocusMinutes, &types); err != nil {
 			return nil, err
 		}
-		history = append(history, day)
+		day.Date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		for _, t := range types {
+			day.ActivityTypes = append(day.ActivityTypes, domain.ActivityType(t))
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// parseDates converts a Postgres DATE[] (scanned as text) to midnight-UTC times
+func parseDates(values []string) ([]time.Time, error) {
+	dates := make([]time.Time, 0, len(values))
+	for _, v := range values {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", v, err)
+		}
+		dates = append(dates, d)
+	}
+	return dates, nil
+}
+
+// SaveStreaks writes computed streaks in a single statement.
+// Longest streaks are kept per mode (days, and weeks per weekly target) and
+// never decrease.
+// Updates computed for a mode the user has since switched away from are skipped.
+func (r *StreakRepository) SaveStreaks(ctx context.Context, updates []domain.StreakUpdate) error {
+	if len(updates) == 0 {
+		return nil
 	}
 
-	return history, nil
+	ids := make([]string, len(updates))
+	modes := make([]string, len(updates))
+	targets := make([]int64, len(updates))
+	current := make([]int64, len(updates))
+	longest := make([]int64, len(updates))
+	consistency := make([]int64, len(updates))
+	for i, u := range updates {
+		ids[i] = u.UserID
+		modes[i] = string(u.StreakMode)
+		targets[i] = int64(u.StreakMode.WeeklyTarget())
+		current[i] = int64(u.CurrentStreak)
+		longest[i] = int64(u.LongestStreak)
+		consistency[i] = int64(u.ConsistencyScore)
+	}
+
+	query := `
+		UPDATE profiles p
+		SET
+			current_streak = u.current_streak,
+			longest_streak = CASE WHEN u.weekly_target > 0
+				THEN p.longest_streak
+				ELSE GREATEST(p.longest_streak, u.longest_streak) END,
+			longest_weekly_streaks = CASE WHEN u.weekly_target > 0
+				THEN p.longest_weekly_streaks[1:u.weekly_target - 1]
+					|| GREATEST(p.longest_weekly_streaks[u.weekly_target], u.longest_streak)
+					|| p.longest_weekly_streaks[u.weekly_target + 1:7]
+				ELSE p.longest_weekly_streaks END,
+			consistency_score = u.consistency_score,
+			updated_at = NOW()
+		FROM unnest($1::UUID[], $2::TEXT[], $3::INTEGER[], $4::INTEGER[], $5::INTEGER[], $6::INTEGER[])
+			AS u(id, streak_mode, current_streak, longest_streak, consistency_score, weekly_target)
+		WHERE p.id = u.id AND p.streak_mode = u.streak_mode
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		pq.Array(ids),
+		pq.Array(modes),
+		pq.Array(current),
+		pq.Array(longest),
+		pq.Array(consistency),
+		pq.Array(targets),
+	)
+	return err
 }
 
-// GetLeaderboard returns top N users by current streak
-func (r *StreakRepository) GetLeaderboard(ctx context.Context, limit int) ([]domain.LeaderboardEntry, error) {
+// GetLeaderboard returns top N users by current streak. Daily and weekly
+// streaks are measured in different units, so each mode is ranked separately.
+func (r *StreakRepository) GetLeaderboard(ctx context.Context, limit int, weekly bool) ([]domain.LeaderboardEntry, error) {
 	query := `
 		SELECT 
 			p.id,
 			p.display_name,
 			p.avatar_url,
+			p.streak_mode,
 			p.current_streak,
 			p.consistency_score,
 			ROW_NUMBER() OVER (ORDER BY p.current_streak DESC, p.consistency_score DESC) AS rank
 		FROM profiles p
 		WHERE p.current_streak > 0
+		  AND (p.streak_mode LIKE 'weekly:%') = $2
 		ORDER BY p.current_streak DESC, p.consistency_score DESC
 		LIMIT $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, limit, weekly)
 	if err != nil {
 		return nil, err
 	}
@@ -206,38 +329,566 @@ func (r *StreakRepository) GetLeaderboard(ctx context.Context, limit int) ([]dom
 			&entry.UserID,
 			&entry.DisplayName,
 			&entry.AvatarURL,
+			&entry.StreakMode,
 			&entry.CurrentStreak,
 			&entry.ConsistencyScore,
 			&entry.Rank,
 		); err != nil {
 			return nil, err
 		}
+		entry.StreakUnit = entry.StreakMode.Unit()
 		leaderboard = append(leaderboard, entry)
 	}
 
 	return leaderboard, nil
 }
 
-// RecalculateAllStreaks triggers recalculation for all users
-// This should be run by a daily cron job
-func (r *StreakRepository) RecalculateAllStreaks(ctx context.Context) error {
-	query := `
+// SetStreakMode stores the user's streak mode. Activity history and both
+// longest streaks are kept, so switching back and forth loses nothing.
+func (r *StreakRepository) SetStreakMode(ctx context.Context, userID string, mode domain.StreakMode) error {
+	result, err := r.db.ExecContext(ctx, `
 		UPDATE profiles
-		SET 
-			current_streak = streak_calc.current_streak,
-			longest_streak = GREATEST(longest_streak, streak_calc.longest_streak),
-			consistency_score = get_consistency_score(id, 0),
-			updated_at = NOW()
-		FROM (
-			SELECT 
+		SET streak_mode = $2, updated_at = NOW()
+		WHERE id = $1
+	`, userID, string(mode))
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrProfileNotFound
+	}
+	return nil
+}
+
+// GetAtRiskUsers returns users with an active streak who have not been
+// active on their current local day and whose local midnight is within the
+// given window
+func (r *StreakRepository) GetAtRiskUsers(ctx context.Context, within time.Duration) ([]domain.AtRiskUser, error) {
+	query := `
+		WITH candidates AS (
+			SELECT
 				p.id,
-				COALESCE((SELECT current_streak FROM calculate_streak(p.id)), 0) AS current_streak,
-				COALESCE((SELECT longest_streak FROM calculate_streak(p.id)), 0) AS longest_streak
+				p.display_name,
+				p.current_streak,
+				p.streak_mode,
+				COALESCE(p.timezone, 'UTC') AS timezone,
+				NOW() AT TIME ZONE COALESCE(p.timezone, 'UTC') AS local_now
 			FROM profiles p
-		) AS streak_calc
-		WHERE profiles.id = streak_calc.id
+			WHERE p.current_streak > 0
+		)
+		SELECT c.id, c.display_name, c.current_streak, last.logged_at, c.timezone,
+		       c.streak_mode, week.days
+		FROM candidates c
+		JOIN LATERAL (
+			SELECT MAX(al.logged_at) AS logged_at, MAX(al.activity_date) AS activity_date
+			FROM activity_logs al
+			WHERE al.user_id = c.id
+		) last ON last.logged_at IS NOT NULL
+		CROSS JOIN LATERAL (
+			-- Covered days so far in the local ISO week (weekly mode progress)
+			SELECT COUNT(*) AS days
+			FROM (
+				SELECT al.activity_date AS d FROM activity_logs al WHERE al.user_id = c.id
+				UNION
+				SELECT sf.freeze_date FROM streak_freezes sf WHERE sf.user_id = c.id AND sf.event = 'used'
+			) covered
+			WHERE covered.d >= date_trunc('week', c.local_now)::DATE
+		) week
+		WHERE last.activity_date < c.local_now::DATE
+		  AND (c.local_now::DATE + 1) - c.local_now <= $1::INTERVAL
+		ORDER BY c.current_streak DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, fmt.Sprintf("%d seconds", int(within.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.AtRiskUser{}
+	for rows.Next() {
+		var user domain.AtRiskUser
+		if err := rows.Scan(
+			&user.UserID,
+			&user.DisplayName,
+			&user.CurrentStreak,
+			&user.LastActivityDate,
+			&user.Timezone,
+			&user.StreakMode,
+			&user.WeekActiveDays,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// RecordRiskAlert claims a (user, local day, threshold) alert slot.
+// Returns false if the alert was already sent, so no user is nudged twice
+// for the same deadline.
+func (r *StreakRepository) RecordRiskAlert(ctx context.Context, userID string, localDate time.Time, threshold string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO streak_risk_alerts (user_id, local_date, threshold)
+		VALUES ($1::UUID, $2::DATE, $3)
+		ON CONFLICT DO NOTHING
+	`, userID, localDate.Format("2006-01-02"), threshold)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// ReleaseRiskAlert frees a claimed alert slot whose alert could not be
+// sent, so the next run claims it again
+func (r *StreakRepository) ReleaseRiskAlert(ctx context.Context, userID string, localDate time.Time, threshold string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM streak_risk_alerts
+		WHERE user_id = $1::UUID AND local_date = $2::DATE AND threshold = $3
+	`, userID, localDate.Format("2006-01-02"), threshold)
+	return err
+}
+
+// GetFreezeLedger returns the user's freeze token ledger (recent first)
+func (r *StreakRepository) GetFreezeLedger(ctx context.Context, userID string, limit int) ([]domain.StreakFreeze, error) {
+	query := `
+		SELECT id, event, freeze_date, count, note, created_at
+		FROM streak_freezes
+		WHERE user_id = $1
+		ORDER BY freeze_date DESC, created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ledger := []domain.StreakFreeze{}
+	for rows.Next() {
+		var entry domain.StreakFreeze
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Event,
+			&entry.FreezeDate,
+			&entry.Count,
+			&entry.Note,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		ledger = append(ledger, entry)
+	}
+
+	return ledger, nil
+}
+
+// GrantFreezeTokens adds freeze tokens to a user (capped) and returns the
+// new balance. Only tokens that fit under the cap are recorded in the ledger.
+func (r *StreakRepository) GrantFreezeTokens(ctx context.Context, userID string, count int, note string) (int, error) {
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+
+	var balance int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT grant_streak_freezes($1::UUID, $2::INTEGER, $3::TEXT)",
+		userID, count, notePtr,
+	).Scan(&balance)
+	if err != nil {
+		if strings.Contains(err.Error(), "Profile not found") {
+			return 0, domain.ErrProfileNotFound
+		}
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// UseFreezeTokens records frozen days and deducts one token per newly
+// covered day. Returns the number of tokens spent.
+func (r *StreakRepository) UseFreezeTokens(ctx context.Context, userID string, dates []time.Time) (int, error) {
+	if len(dates) == 0 {
+		return 0, nil
+	}
+
+	days := make([]string, len(dates))
+	for i, d := range dates {
+		days[i] = d.Format("2006-01-02")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Lock the balance so concurrent runs can't overspend it
+	var balance int
+	err = tx.QueryRowContext(ctx,
+		"SELECT freeze_tokens FROM profiles WHERE id = $1 FOR UPDATE",
+		userID,
+	).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrProfileNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if balance < len(days) {
+		return 0, nil
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO streak_freezes (user_id, event, freeze_date, note)
+		SELECT $1::UUID, 'used', d, 'Auto-applied to missed day'
+		FROM unnest($2::DATE[]) AS d
+		ON CONFLICT DO NOTHING
+	`, userID, pq.Array(days))
+	if err != nil {
+		return 0, err
+	}
+
+	used, _ := result.RowsAffected()
+	if used > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE profiles
+			SET freeze_tokens = freeze_tokens - $2, updated_at = NOW()
+			WHERE id = $1
+		`, userID, used); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(used), nil
+}
+
+// EarnFreezeToken awards one token for the given activity day, unless the
+// user already holds the maximum or already earned a token that day
+func (r *StreakRepository) EarnFreezeToken(ctx context.Context, userID string, date time.Time, note string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO streak_freezes (user_id, event, freeze_date, note)
+		SELECT id, 'earned', $2::DATE, $3
+		FROM profiles
+		WHERE id = $1 AND freeze_tokens < $4
+		ON CONFLICT DO NOTHING
+	`, userID, date.Format("2006-01-02"), note, domain.MaxFreezeTokens)
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE profiles
+		SET freeze_tokens = freeze_tokens + 1, updated_at = NOW()
+		WHERE id = $1
+	`, userID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// RecordAchievement stores a milestone or personal record. Returns false
+// if this streak already reached it (nothing is stored).
+func (r *StreakRepository) RecordAchievement(ctx context.Context, userID string, achievement *domain.StreakAchievement) (bool, error) {
+	query := `
+		INSERT INTO streak_achievements (user_id, kind, streak_mode, streak_unit, value, previous_record, streak_started_on)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7)
+		ON CONFLICT DO NOTHING
+		RETURNING achieved_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		userID,
+		string(achievement.Kind),
+		string(achievement.StreakMode),
+		achievement.StreakUnit,
+		achievement.Value,
+		achievement.PreviousRecord,
+		achievement.StreakStartedOn.Format("2006-01-02"),
+	).Scan(&achievement.AchievedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetAchievements returns the user's most recent streak achievements
+func (r *StreakRepository) GetAchievements(ctx context.Context, userID string, limit int) ([]domain.StreakAchievement, error) {
+	query := `
+		SELECT kind, streak_mode, streak_unit, value, COALESCE(previous_record, 0), streak_started_on, achieved_at
+		FROM streak_achievements
+		WHERE user_id = $1
+		ORDER BY achieved_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	achievements := []domain.StreakAchievement{}
+	for rows.Next() {
+		var a domain.StreakAchievement
+		if err := rows.Scan(
+			&a.Kind,
+			&a.StreakMode,
+			&a.StreakUnit,
+			&a.Value,
+			&a.PreviousRecord,
+			&a.StreakStartedOn,
+			&a.AchievedAt,
+		); err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, a)
+	}
+
+	return achievements, nil
+}
+
+// GetSquadmateIDs returns everyone who shares a squad with the user
+func (r *StreakRepository) GetSquadmateIDs(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT other.user_id
+		FROM squad_members mine
+		JOIN squad_members other ON other.squad_id = mine.squad_id
+		WHERE mine.user_id = $1 AND other.user_id <> $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// squadStreakInputsQuery selects squads with one row per member, including
+// the member's activity days since joining and whether they are active today
+const squadStreakInputsQuery = `
+	SELECT
+		s.id,
+		s.name,
+		s.streak_quorum,
+		s.current_streak,
+		s.longest_streak,
+		sm.user_id,
+		p.display_name,
+		COALESCE(p.timezone, 'UTC'),
+		sm.joined_at,
+		COALESCE((
+			SELECT ARRAY_AGG(DISTINCT al.activity_date ORDER BY al.activity_date)
+			FROM activity_logs al
+			WHERE al.user_id = sm.user_id
+			  AND al.activity_date >= (sm.joined_at AT TIME ZONE COALESCE(p.timezone, 'UTC'))::DATE
+		), '{}'),
+		EXISTS (
+			SELECT 1 FROM activity_logs al
+			WHERE al.user_id = sm.user_id
+			  AND al.activity_date = (NOW() AT TIME ZONE COALESCE(p.timezone, 'UTC'))::DATE
+		)
+	FROM squads s
+	JOIN squad_members sm ON sm.squad_id = s.id
+	JOIN profiles p ON p.id = sm.user_id
+`
+
+// GetUserSquadIDs returns the squads a user belongs to
+func (r *StreakRepository) GetUserSquadIDs(ctx context.Context, userID string) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT squad_id FROM squad_members WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	squadIDs := []uuid.UUID{}
+	for rows.Next() {
+		var squadID uuid.UUID
+		if err := rows.Scan(&squadID); err != nil {
+			return nil, err
+		}
+		squadIDs = append(squadIDs, squadID)
+	}
+
+	return squadIDs, nil
+}
+
+// GetSquadStreakInputs returns the members' activity for one squad
+func (r *StreakRepository) GetSquadStreakInputs(ctx context.Context, squadID uuid.UUID) (*domain.SquadStreakInputs, error) {
+	squads, err := r.querySquadStreakInputs(ctx, squadStreakInputsQuery+`
+		WHERE s.id = $1
+		ORDER BY s.id, sm.joined_at
+	`, squadID)
+	if err != nil {
+		return nil, err
+	}
+	if len(squads) == 0 {
+		return nil, domain.ErrSquadNotFound
+	}
+
+	return &squads[0], nil
+}
+
+// ListSquadStreakInputs returns a page of squads ordered by id, starting
+// after afterID (keyset pagination for batched recalculation)
+func (r *StreakRepository) ListSquadStreakInputs(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.SquadStreakInputs, error) {
+	return r.querySquadStreakInputs(ctx, squadStreakInputsQuery+`
+		WHERE s.id IN (SELECT id FROM squads WHERE id > $1 ORDER BY id LIMIT $2)
+		ORDER BY s.id, sm.joined_at
+	`, afterID, limit)
+}
+
+// ListActiveSquadStreaks returns squads with a running streak, with each
+// member's activity status for today
+func (r *StreakRepository) ListActiveSquadStreaks(ctx context.Context) ([]domain.SquadStreakInputs, error) {
+	return r.querySquadStreakInputs(ctx, squadStreakInputsQuery+`
+		WHERE s.current_streak > 0
+		ORDER BY s.id, sm.joined_at
+	`)
+}
+
+// querySquadStreakInputs groups member rows (ordered by squad) into squads
+func (r *StreakRepository) querySquadStreakInputs(ctx context.Context, query string, args ...interface{}) ([]domain.SquadStreakInputs, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	squads := []domain.SquadStreakInputs{}
+	for rows.Next() {
+		var squad domain.SquadStreakInputs
+		var member domain.SquadMemberActivity
+		var activeDates pq.StringArray
+
+		if err := rows.Scan(
+			&squad.SquadID,
+			&squad.Name,
+			&squad.Quorum,
+			&squad.CurrentStreak,
+			&squad.LongestStreak,
+			&member.UserID,
+			&member.DisplayName,
+			&member.Timezone,
+			&member.JoinedAt,
+			&activeDates,
+			&member.ActiveToday,
+		); err != nil {
+			return nil, err
+		}
+
+		if member.ActiveDates, err = parseDates(activeDates); err != nil {
+			return nil, err
+		}
+
+		if n := len(squads); n == 0 || squads[n-1].SquadID != squad.SquadID {
+			squads = append(squads, squad)
+		}
+		last := &squads[len(squads)-1]
+		last.Members = append(last.Members, member)
+	}
+
+	return squads, nil
+}
+
+// SaveSquadStreaks writes computed squad streaks in a single statement.
+// longest_streak never decreases.
+func (r *StreakRepository) SaveSquadStreaks(ctx context.Context, updates []domain.SquadStreakUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(updates))
+	current := make([]int64, len(updates))
+	longest := make([]int64, len(updates))
+	lastDates := make([]sql.NullString, len(updates))
+	for i, u := range updates {
+		ids[i] = u.SquadID.String()
+		current[i] = int64(u.CurrentStreak)
+		longest[i] = int64(u.LongestStreak)
+		if u.LastStreakDate != nil {
+			lastDates[i] = sql.NullString{String: u.LastStreakDate.Format("2006-01-02"), Valid: true}
+		}
+	}
+
+	query := `
+		UPDATE squads s
+		SET
+			current_streak = u.current_streak,
+			longest_streak = GREATEST(s.longest_streak, u.longest_streak),
+			last_streak_date = u.last_streak_date
+		FROM unnest($1::UUID[], $2::INTEGER[], $3::INTEGER[], $4::DATE[])
+			AS u(id, current_streak, longest_streak, last_streak_date)
+		WHERE s.id = u.id
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		pq.Array(ids),
+		pq.Array(current),
+		pq.Array(longest),
+		pq.Array(lastDates),
+	)
+	return err
+}
+
+// RecordSquadRiskAlert claims a (squad, member, local day, threshold) alert
+// slot. Returns false if that alert was already sent.
+func (r *StreakRepository) RecordSquadRiskAlert(ctx context.Context, squadID, userID uuid.UUID, localDate time.Time, threshold string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO squad_streak_risk_alerts (squad_id, user_id, local_date, threshold)
+		VALUES ($1, $2, $3::DATE, $4)
+		ON CONFLICT DO NOTHING
+	`, squadID, userID, localDate.Format("2006-01-02"), threshold)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// ReleaseSquadRiskAlert frees a claimed squad alert slot whose alert could
+// not be sent, so the next run claims it again
+func (r *StreakRepository) ReleaseSquadRiskAlert(ctx context.Context, squadID, userID uuid.UUID, localDate time.Time, threshold string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM squad_streak_risk_alerts
+		WHERE squad_id = $1 AND user_id = $2 AND local_date = $3::DATE AND threshold = $4
+	`, squadID, userID, localDate.Format("2006-01-02"), threshold)
 	return err
 }
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// StudyRoomRepository handles database operations for study rooms
+type StudyRoomRepository struct {
+	db *sql.DB
+}
+
+// NewStudyRoomRepository creates a new study room repository
+func NewStudyRoomRepository(db *sql.DB) *StudyRoomRepository {
+	return &StudyRoomRepository{db: db}
+}
+
+// studyRoomSelect selects the rows scanned by scanStudyRoom. $1 is the
+// viewing user, whose RSVP is returned.
+const studyRoomSelect = `
+	SELECT sr.id, sr.squad_id, sr.host_id, p.display_name, sr.topic,
+	       sr.starts_at, sr.starts_at + make_interval(mins => sr.duration_minutes),
+	       sr.duration_minutes, sr.cancelled_at, sr.created_at,
+	       (SELECT COUNT(*) FROM study_room_rsvps r WHERE r.room_id = sr.id AND r.status = 'going'),
+	       (SELECT COUNT(*) FROM study_room_rsvps r WHERE r.room_id = sr.id AND r.status = 'maybe'),
+	       (SELECT COUNT(*) FROM study_room_attendance a WHERE a.room_id = sr.id),
+	       (SELECT r.status FROM study_room_rsvps r WHERE r.room_id = sr.id AND r.user_id = $1)
+	FROM study_rooms sr
+	JOIN profiles p ON p.id = sr.host_id
+`
+
+func scanStudyRoom(row rowScanner) (*domain.StudyRoom, error) {
+	room := &domain.StudyRoom{}
+	if err := row.Scan(
+		&room.ID,
+		&room.SquadID,
+		&room.HostID,
+		&room.HostName,
+		&room.Topic,
+		&room.StartsAt,
+		&room.EndsAt,
+		&room.DurationMinutes,
+		&room.CancelledAt,
+		&room.CreatedAt,
+		&room.Going,
+		&room.Maybe,
+		&room.Attended,
+		&room.MyRSVP,
+	); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// Create schedules a study room from an already validated request. The
+// host is RSVP'd as going.
+func (r *StudyRoomRepository) Create(ctx context.Context, squadID, hostID uuid.UUID, req *domain.CreateStudyRoomRequest) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var roomID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO study_rooms (squad_id, host_id, topic, starts_at, duration_minutes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, squadID, hostID, req.Topic, req.StartsAt, req.DurationMinutes).Scan(&roomID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO study_room_rsvps (room_id, user_id, status)
+		VALUES ($1, $2, 'going')
+	`, roomID, hostID); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return roomID, nil
+}
+
+// GetByID returns a study room as seen by userID, or nil if it does not exist
+func (r *StudyRoomRepository) GetByID(ctx context.Context, roomID, userID uuid.UUID) (*domain.StudyRoom, error) {
+	room, err := scanStudyRoom(r.db.QueryRowContext(ctx, studyRoomSelect+` WHERE sr.id = $2`, userID, roomID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return room, err
+}
+
+// ListBySquad returns a squad's study rooms that end after the given time,
+// soonest first, as seen by userID
+func (r *StudyRoomRepository) ListBySquad(ctx context.Context, squadID, userID uuid.UUID, endingAfter time.Time) ([]domain.StudyRoom, error) {
+	query := studyRoomSelect + `
+		WHERE sr.squad_id = $2
+		  AND sr.starts_at + make_interval(mins => sr.duration_minutes) > $3
+		ORDER BY sr.starts_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, squadID, endingAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []domain.StudyRoom{}
+	for rows.Next() {
+		room, err := scanStudyRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, *room)
+	}
+
+	return rooms, nil
+}
+
+// GetMembers returns everyone who answered or joined a study room, those
+// who attended first. Focused minutes count ended linked sessions only.
+func (r *StudyRoomRepository) GetMembers(ctx context.Context, roomID uuid.UUID) ([]domain.StudyRoomMember, error) {
+	query := `
+		SELECT p.id, p.display_name, p.avatar_url, r.status, a.joined_at,
+		       COALESCE((
+		           SELECT SUM(fs.duration_minutes) FROM focus_sessions fs
+		           WHERE fs.study_room_id = $1 AND fs.user_id = p.id
+		       ), 0)
+		FROM (
+		    SELECT user_id FROM study_room_rsvps WHERE room_id = $1
+		    UNION
+		    SELECT user_id FROM study_room_attendance WHERE room_id = $1
+		) m
+		JOIN profiles p ON p.id = m.user_id
+		LEFT JOIN study_room_rsvps r ON r.room_id = $1 AND r.user_id = m.user_id
+		LEFT JOIN study_room_attendance a ON a.room_id = $1 AND a.user_id = m.user_id
+		ORDER BY a.joined_at ASC NULLS LAST, p.display_name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain.StudyRoomMember{}
+	for rows.Next() {
+		var m domain.StudyRoomMember
+		if err := rows.Scan(&m.UserID, &m.DisplayName, &m.AvatarURL, &m.RSVP, &m.JoinedAt, &m.FocusedMinutes); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+// SetRSVP records or changes a member's answer to a study room
+func (r *StudyRoomRepository) SetRSVP(ctx context.Context, roomID, userID uuid.UUID, status string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO study_room_rsvps (room_id, user_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (room_id, user_id)
+		DO UPDATE SET status = EXCLUDED.status, updated_at = NOW()
+	`, roomID, userID, status)
+	return err
+}
+
+// Cancel cancels a study room
+func (r *StudyRoomRepository) Cancel(ctx context.Context, roomID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE study_rooms SET cancelled_at = NOW() WHERE id = $1 AND cancelled_at IS NULL",
+		roomID,
+	)
+	return err
+}
+
+// ClaimDueReminders marks the reminder of every room starting within lead
+// as sent and returns those rooms with the members who are going or maybe
+// coming. A room is only returned again after ReleaseReminder.
+func (r *StudyRoomRepository) ClaimDueReminders(ctx context.Context, lead time.Duration) ([]domain.StudyRoomReminder, error) {
+	query := `
+		WITH due AS (
+			UPDATE study_rooms
+			SET reminder_sent_at = NOW()
+			WHERE cancelled_at IS NULL
+			  AND reminder_sent_at IS NULL
+			  AND starts_at > NOW()
+			  AND starts_at <= NOW() + make_interval(secs => $1::FLOAT8)
+			RETURNING id, squad_id, topic, starts_at, duration_minutes
+		)
+		SELECT d.id, d.squad_id, s.name, d.topic, d.starts_at, d.duration_minutes,
+		       ARRAY(
+		           SELECT r.user_id FROM study_room_rsvps r
+		           JOIN squad_members sm ON sm.squad_id = d.squad_id AND sm.user_id = r.user_id
+		           WHERE r.room_id = d.id AND r.status IN ('going', 'maybe')
+		       )
+		FROM due d
+		JOIN squads s ON s.id = d.squad_id
+		ORDER BY d.starts_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, lead.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []domain.StudyRoomReminder{}
+	for rows.Next() {
+		var rem domain.StudyRoomReminder
+		if err := rows.Scan(
+			&rem.RoomID, &rem.SquadID, &rem.SquadName, &rem.Topic,
+			&rem.StartsAt, &rem.DurationMinutes, pq.Array(&rem.Recipients),
+		); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, rem)
+	}
+
+	return reminders, nil
+}
+
+// ReleaseReminder unmarks a claimed reminder that could not be sent, so the
+// next run claims it again
+func (r *StudyRoomRepository) ReleaseReminder(ctx context.Context, roomID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE study_rooms SET reminder_sent_at = NULL WHERE id = $1",
+		roomID,
+	)
+	return err
+}
@@ -0,0 +1,161 @@
+package room
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096 // Bytes
+	sendBuffer     = 64   // Queued messages before a slow client is dropped
+
+	// Per-connection rate limit on client messages
+	messagesPerSecond = 5
+	messageBurst      = 10
+)
+
+// client is one WebSocket connection in a squad room
+type client struct {
+	hub     *Hub
+	conn    *websocket.Conn
+	userID  uuid.UUID
+	squadID uuid.UUID
+	limiter *rate.Limiter
+
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newClient(hub *Hub, conn *websocket.Conn, userID, squadID uuid.UUID) *client {
+	return &client{
+		hub:     hub,
+		conn:    conn,
+		userID:  userID,
+		squadID: squadID,
+		limiter: rate.NewLimiter(messagesPerSecond, messageBurst),
+		send:    make(chan []byte, sendBuffer),
+		done:    make(chan struct{}),
+	}
+}
+
+// close ends the connection; both pumps stop
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// evict closes the connection with a policy violation close frame, for
+// users who may no longer be in the room
+func (c *client) evict(reason string) {
+	deadline := time.Now().Add(writeWait)
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason), deadline)
+	c.close()
+}
+
+// enqueue queues a payload for writing, dropping clients that can't keep up
+func (c *client) enqueue(payload []byte) {
+	select {
+	case c.send <- payload:
+	case <-c.done:
+	default:
+		log.Printf("Dropping slow room client %s in squad %s", c.userID, c.squadID)
+		c.close()
+	}
+}
+
+func (c *client) sendMessage(msg Outbound) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to encode room message: %v", err)
+		return
+	}
+	c.enqueue(payload)
+}
+
+func (c *client) sendError(code, message string) {
+	c.sendMessage(c.hub.message(TypeError, c.squadID, uuid.Nil, ErrorData{Code: code, Message: message}))
+}
+
+// decode parses message data, answering with an error if it's invalid.
+// Empty data decodes to the zero value.
+func (c *client) decode(data json.RawMessage, v interface{}) bool {
+	if len(data) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		c.sendError(ErrCodeInvalidMessage, "Invalid message data")
+		return false
+	}
+	return true
+}
+
+// readPump reads client messages until the connection fails or closes
+func (c *client) readPump() {
+	defer c.close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("Room connection of %s closed: %v", c.userID, err)
+			}
+			return
+		}
+
+		if !c.limiter.Allow() {
+			c.sendError(ErrCodeRateLimited, "Too many messages, slow down")
+			continue
+		}
+
+		var in Inbound
+		if err := json.Unmarshal(data, &in); err != nil {
+			c.sendError(ErrCodeInvalidMessage, "Messages must be JSON objects")
+			continue
+		}
+		c.hub.handle(c, in)
+	}
+}
+
+// writePump writes queued messages and keepalive pings
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case payload := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
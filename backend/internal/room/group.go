@@ -0,0 +1,156 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/service"
+	"github.com/google/uuid"
+)
+
+const (
+	groupFocusJoinWindow = time.Minute // Late joins accepted after StartsAt
+	maxGroupFocusMinutes = 720         // Same caps as a focus session's plan
+	maxGroupFocusGoal    = 200         // Characters
+)
+
+var (
+	errGroupNotFound = errors.New("group focus not found")
+	errAlreadyJoined = errors.New("already joined this group focus")
+)
+
+// groupFocus is an announced group focus and who has joined it on this
+// replica
+type groupFocus struct {
+	GroupFocus
+	joined map[uuid.UUID]struct{}
+}
+
+// addGroup records an announced group focus. Every replica records the
+// announcements it relays, so members can join from any of them.
+func (h *Hub) addGroup(squadID uuid.UUID, group GroupFocus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pruneGroups(squadID)
+	if h.groups[squadID] == nil {
+		h.groups[squadID] = map[uuid.UUID]*groupFocus{}
+	}
+	if _, ok := h.groups[squadID][group.GroupID]; ok {
+		return
+	}
+	h.groups[squadID][group.GroupID] = &groupFocus{GroupFocus: group, joined: map[uuid.UUID]struct{}{}}
+}
+
+// pruneGroups drops the room's groups that can no longer be joined.
+// h.mu must be held.
+func (h *Hub) pruneGroups(squadID uuid.UUID) {
+	now := h.now()
+	for id, group := range h.groups[squadID] {
+		if now.After(group.JoinBy) {
+			delete(h.groups[squadID], id)
+		}
+	}
+	if len(h.groups[squadID]) == 0 {
+		delete(h.groups, squadID)
+	}
+}
+
+// claimGroupSeat marks the user as joining a group focus of the room and
+// returns it
+func (h *Hub) claimGroupSeat(squadID, groupID, userID uuid.UUID) (GroupFocus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pruneGroups(squadID)
+	group, ok := h.groups[squadID][groupID]
+	if !ok {
+		return GroupFocus{}, errGroupNotFound
+	}
+	if _, ok := group.joined[userID]; ok {
+		return GroupFocus{}, errAlreadyJoined
+	}
+	group.joined[userID] = struct{}{}
+	return group.GroupFocus, nil
+}
+
+// releaseGroupSeat lets the user join the group again after a failed start
+func (h *Hub) releaseGroupSeat(squadID, groupID, userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if group, ok := h.groups[squadID][groupID]; ok {
+		delete(group.joined, userID)
+	}
+}
+
+// joinGroupFocus starts the member's own focus session with the group's
+// plan at the group's start time, through the regular focus flow
+// (validation, events, streaks)
+func (h *Hub) joinGroupFocus(c *client, groupID uuid.UUID) {
+	group, err := h.claimGroupSeat(c.squadID, groupID, c.userID)
+	switch {
+	case errors.Is(err, errGroupNotFound):
+		c.sendError(ErrCodeGroupNotFound, "This group focus has ended or does not exist")
+		return
+	case errors.Is(err, errAlreadyJoined):
+		c.sendError(ErrCodeAlreadyJoined, "You already joined this group focus")
+		return
+	}
+
+	wait := group.StartsAt.Sub(h.now())
+	if wait <= 0 {
+		h.startGroupFocus(c, group)
+		return
+	}
+
+	go func() {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			h.startGroupFocus(c, group)
+		case <-c.done:
+			h.releaseGroupSeat(c.squadID, group.GroupID, c.userID)
+		}
+	}()
+}
+
+func (h *Hub) startGroupFocus(c *client, group GroupFocus) {
+	ctx, cancel := context.WithTimeout(context.Background(), focusStartTimeout)
+	defer cancel()
+
+	session, err := h.focus.StartFocus(ctx, c.userID, &domain.StartFocusRequest{
+		SquadID:        &c.squadID,
+		PlannedMinutes: group.PlannedMinutes,
+		Goal:           group.Goal,
+	})
+	if err != nil {
+		h.releaseGroupSeat(c.squadID, group.GroupID, c.userID)
+		sendFocusError(c, err)
+		return
+	}
+
+	h.broadcast(h.message(TypeGroupFocusJoined, c.squadID, c.userID, GroupFocusJoined{
+		GroupID:   group.GroupID,
+		SessionID: session.SessionID,
+	}))
+}
+
+// sendFocusError tells the client why its session didn't start, without
+// passing on internal errors
+func sendFocusError(c *client, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotSquadMember):
+		c.sendError(ErrCodeNotMember, "You are not a member of this squad")
+	case errors.Is(err, service.ErrInvalidPlan), errors.Is(err, service.ErrInvalidGoal):
+		c.sendError(ErrCodeInvalidMessage, "The group's plan is not a valid focus session")
+	default:
+		log.Printf("Failed to start group focus session for %s in squad %s: %v", c.userID, c.squadID, err)
+		c.sendError(ErrCodeFocusFailed, "Could not start your focus session")
+	}
+}
@@ -0,0 +1,288 @@
+// Package room runs the live squad rooms: WebSocket connections where
+// squadmates cheer each other on, share typing status and start group
+// focus sessions.
+//
+// Each replica tracks its own connections. Messages are fanned out to all
+// replicas over core NATS, so members connected to different replicas
+// share one room. Room traffic is ephemeral and never stored.
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Room subjects on core NATS (outside the persisted events.> stream)
+const subjectAllRooms = "rooms.squad.*"
+
+// Squad stream events of members leaving or being removed, in every squad.
+// JetStream publishes reach core NATS subscribers too.
+const subjectMembersLeft = "events.squad.*." + eventbus.SquadEventMemberLeft
+
+func roomSubject(squadID uuid.UUID) string {
+	return "rooms.squad." + squadID.String()
+}
+
+const (
+	maxEmojiLength      = 8 // Characters
+	groupFocusCountdown = 10 * time.Second
+	focusStartTimeout   = 10 * time.Second
+)
+
+// Hub tracks this replica's room connections and relays messages between
+// rooms across replicas
+type Hub struct {
+	bus    *eventbus.EventBus // nil = single replica, deliver locally
+	squads domain.SquadRepository
+	focus  domain.FocusService
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	rooms  map[uuid.UUID]map[*client]struct{}
+	groups map[uuid.UUID]map[uuid.UUID]*groupFocus // Announced group focuses per room
+}
+
+// NewHub creates a new hub. bus may be nil when NATS is not configured.
+func NewHub(bus *eventbus.EventBus, squads domain.SquadRepository, focus domain.FocusService) *Hub {
+	return &Hub{
+		bus:    bus,
+		squads: squads,
+		focus:  focus,
+		now:    time.Now,
+		rooms:  map[uuid.UUID]map[*client]struct{}{},
+		groups: map[uuid.UUID]map[uuid.UUID]*groupFocus{},
+	}
+}
+
+// Start receives room messages from every replica (this one included), and
+// closes the connections of members who leave or are removed
+func (h *Hub) Start() error {
+	if h.bus == nil {
+		log.Println("Warning: EventBus is nil, squad rooms are local to this replica")
+		return nil
+	}
+
+	if _, err := h.bus.SubscribeEphemeral(subjectAllRooms, h.receive); err != nil {
+		return err
+	}
+	_, err := h.bus.SubscribeEphemeral(subjectMembersLeft, h.memberLeft)
+	return err
+}
+
+// IsMember reports whether the user may enter the squad's room
+func (h *Hub) IsMember(ctx context.Context, squadID, userID uuid.UUID) (bool, error) {
+	return h.squads.IsMember(ctx, squadID, userID)
+}
+
+// Serve runs an upgraded connection in the squad's room until it closes
+func (h *Hub) Serve(conn *websocket.Conn, userID, squadID uuid.UUID) {
+	c := newClient(h, conn, userID, squadID)
+	h.register(c)
+
+	c.sendMessage(h.message(TypeWelcome, squadID, uuid.Nil, WelcomeData{
+		UserID:              userID,
+		MessagesPerSecond:   messagesPerSecond,
+		MessageBurst:        messageBurst,
+		GroupFocusCountdown: int(groupFocusCountdown.Seconds()),
+	}))
+	h.broadcast(h.message(TypePresenceJoined, squadID, userID, nil))
+
+	go c.writePump()
+	c.readPump()
+
+	h.unregister(c)
+	h.broadcast(h.message(TypePresenceLeft, squadID, userID, nil))
+}
+
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.rooms[c.squadID] == nil {
+		h.rooms[c.squadID] = map[*client]struct{}{}
+	}
+	h.rooms[c.squadID][c] = struct{}{}
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.rooms[c.squadID], c)
+	if len(h.rooms[c.squadID]) == 0 {
+		delete(h.rooms, c.squadID)
+	}
+}
+
+// message builds an outbound message; from is uuid.Nil for server messages
+func (h *Hub) message(msgType string, squadID, from uuid.UUID, data interface{}) Outbound {
+	msg := Outbound{
+		V:       ProtocolVersion,
+		Type:    msgType,
+		SquadID: squadID,
+		SentAt:  h.now().UTC(),
+		Data:    data,
+	}
+	if from != uuid.Nil {
+		msg.From = &from
+	}
+	return msg
+}
+
+// broadcast sends a message to everyone in its room, on every replica
+func (h *Hub) broadcast(msg Outbound) {
+	if h.bus != nil {
+		err := h.bus.PublishEphemeral(roomSubject(msg.SquadID), msg)
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to relay room message, delivering locally: %v", err)
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to encode room message: %v", err)
+		return
+	}
+	h.deliver(msg.SquadID, payload)
+}
+
+// receive delivers a relayed message to this replica's connections,
+// recording group focus announcements on the way
+func (h *Hub) receive(data []byte) {
+	var head struct {
+		Type    string          `json:"type"`
+		SquadID uuid.UUID       `json:"squad_id"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		log.Printf("Failed to parse room message: %v", err)
+		return
+	}
+	if head.Type == TypeGroupFocusStarted {
+		var group GroupFocus
+		if err := json.Unmarshal(head.Data, &group); err != nil {
+			log.Printf("Failed to parse group focus: %v", err)
+			return
+		}
+		h.addGroup(head.SquadID, group)
+	}
+	h.deliver(head.SquadID, data)
+}
+
+func (h *Hub) deliver(squadID uuid.UUID, payload []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.rooms[squadID] {
+		c.enqueue(payload)
+	}
+}
+
+// memberLeft closes the room connections of a member who left the squad or
+// was removed from it
+func (h *Hub) memberLeft(data []byte) {
+	var event eventbus.SquadEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.Printf("Failed to parse member.left event: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	var leaving []*client
+	for c := range h.rooms[event.SquadID] {
+		if c.userID == event.UserID {
+			leaving = append(leaving, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range leaving {
+		c.evict("No longer a member of this squad")
+	}
+}
+
+// handle acts on one message from a client
+func (h *Hub) handle(c *client, in Inbound) {
+	if in.V != ProtocolVersion {
+		c.sendError(ErrCodeUnsupportedVersion, "This server speaks protocol version 1")
+		return
+	}
+
+	switch in.Type {
+	case TypeCheer:
+		var data CheerData
+		if !c.decode(in.Data, &data) {
+			return
+		}
+		data.Emoji = strings.TrimSpace(data.Emoji)
+		if data.Emoji == "" {
+			data.Emoji = "🎉"
+		}
+		if utf8.RuneCountInString(data.Emoji) > maxEmojiLength {
+			c.sendError(ErrCodeInvalidMessage, "emoji is too long")
+			return
+		}
+		h.broadcast(h.message(TypeCheer, c.squadID, c.userID, data))
+
+	case TypeTyping:
+		var data TypingData
+		if !c.decode(in.Data, &data) {
+			return
+		}
+		h.broadcast(h.message(TypeTyping, c.squadID, c.userID, data))
+
+	case TypeGroupFocusStart:
+		var data GroupFocusStartData
+		if !c.decode(in.Data, &data) {
+			return
+		}
+		if data.PlannedMinutes <= 0 || data.PlannedMinutes > maxGroupFocusMinutes {
+			c.sendError(ErrCodeInvalidMessage, "planned_minutes must be between 1 and 720")
+			return
+		}
+		data.Goal = strings.TrimSpace(data.Goal)
+		if utf8.RuneCountInString(data.Goal) > maxGroupFocusGoal {
+			c.sendError(ErrCodeInvalidMessage, "goal must be at most 200 characters")
+			return
+		}
+		startsAt := h.now().UTC().Add(groupFocusCountdown)
+		group := GroupFocus{
+			GroupID:        uuid.New(),
+			HostID:         c.userID,
+			PlannedMinutes: data.PlannedMinutes,
+			Goal:           data.Goal,
+			StartsAt:       startsAt,
+			JoinBy:         startsAt.Add(groupFocusJoinWindow),
+		}
+		h.addGroup(c.squadID, group)
+		h.broadcast(h.message(TypeGroupFocusStarted, c.squadID, c.userID, group))
+
+	case TypeGroupFocusJoin:
+		var data GroupFocusJoinData
+		if !c.decode(in.Data, &data) {
+			return
+		}
+		if data.GroupID == uuid.Nil {
+			c.sendError(ErrCodeInvalidMessage, "group_id is required")
+			return
+		}
+		h.joinGroupFocus(c, data.GroupID)
+
+	case TypePing:
+		c.sendMessage(h.message(TypePong, c.squadID, uuid.Nil, nil))
+
+	default:
+		c.sendError(ErrCodeUnknownType, "Unknown message type "+in.Type)
+	}
+}
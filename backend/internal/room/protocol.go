@@ -0,0 +1,120 @@
+package room
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProtocolVersion is the version of the squad room message protocol. Every
+// message carries it in "v"; clients negotiate it with the Subprotocol.
+const ProtocolVersion = 1
+
+// Subprotocol is the WebSocket subprotocol for this protocol version
+const Subprotocol = "squad-room.v1"
+
+// Client -> server message types
+const (
+	TypeCheer           = "cheer"
+	TypeTyping          = "typing"
+	TypeGroupFocusStart = "group_focus.start"
+	TypeGroupFocusJoin  = "group_focus.join"
+	TypePing            = "ping"
+)
+
+// Server -> client message types (cheer and typing are relayed as is)
+const (
+	TypeWelcome           = "welcome"
+	TypePresenceJoined    = "presence.joined"
+	TypePresenceLeft      = "presence.left"
+	TypeGroupFocusStarted = "group_focus.started"
+	TypeGroupFocusJoined  = "group_focus.joined"
+	TypePong              = "pong"
+	TypeError             = "error"
+)
+
+// Error codes sent in error messages
+const (
+	ErrCodeInvalidMessage     = "invalid_message"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeUnknownType        = "unknown_type"
+	ErrCodeRateLimited        = "rate_limited"
+	ErrCodeFocusFailed        = "focus_failed"
+	ErrCodeGroupNotFound      = "group_not_found"
+	ErrCodeAlreadyJoined      = "already_joined"
+	ErrCodeNotMember          = "not_member"
+)
+
+// Inbound is a message from a client
+type Inbound struct {
+	V    int             `json:"v"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Outbound is a message to clients. Relayed messages are also the payload
+// sent between replicas, so they carry their squad.
+type Outbound struct {
+	V       int         `json:"v"`
+	Type    string      `json:"type"`
+	SquadID uuid.UUID   `json:"squad_id"`
+	From    *uuid.UUID  `json:"from,omitempty"` // Sender, nil for server messages
+	SentAt  time.Time   `json:"sent_at"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// CheerData cheers on a squadmate, or the whole squad if To is nil
+type CheerData struct {
+	To    *uuid.UUID `json:"to,omitempty"`
+	Emoji string     `json:"emoji,omitempty"` // Defaults to 🎉
+}
+
+// TypingData is a member's typing status
+type TypingData struct {
+	Typing bool `json:"typing"`
+}
+
+// GroupFocusStartData proposes a group focus session
+type GroupFocusStartData struct {
+	PlannedMinutes int    `json:"planned_minutes"`
+	Goal           string `json:"goal,omitempty"`
+}
+
+// GroupFocus is a proposed group focus. Clients show a countdown to
+// StartsAt; members who send group_focus.join before JoinBy get a session
+// with its plan, started at StartsAt (or at once if they join late).
+type GroupFocus struct {
+	GroupID        uuid.UUID `json:"group_id"`
+	HostID         uuid.UUID `json:"host_id"`
+	PlannedMinutes int       `json:"planned_minutes"`
+	Goal           string    `json:"goal,omitempty"`
+	StartsAt       time.Time `json:"starts_at"`
+	JoinBy         time.Time `json:"join_by"`
+}
+
+// GroupFocusJoinData joins an announced group focus. The plan is always
+// the group's.
+type GroupFocusJoinData struct {
+	GroupID uuid.UUID `json:"group_id"`
+}
+
+// GroupFocusJoined announces a member's session in a group focus
+type GroupFocusJoined struct {
+	GroupID   uuid.UUID `json:"group_id"`
+	SessionID uuid.UUID `json:"session_id"`
+}
+
+// WelcomeData is sent once a connection joins its room
+type WelcomeData struct {
+	UserID              uuid.UUID `json:"user_id"`
+	MessagesPerSecond   float64   `json:"messages_per_second"`
+	MessageBurst        int       `json:"message_burst"`
+	GroupFocusCountdown int       `json:"group_focus_countdown_seconds"`
+}
+
+// ErrorData describes a rejected message
+type ErrorData struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,269 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/mocks"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// memberRepo lets everyone in; only IsMember is used by the hub
+type memberRepo struct {
+	domain.SquadRepository
+}
+
+func (memberRepo) IsMember(ctx context.Context, squadID, userID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+// dialRoom connects a user to a squad room served by hub
+func dialRoom(t *testing.T, hub *Hub, userID, squadID uuid.UUID) *websocket.Conn {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{Subprotocols: []string{Subprotocol}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.Serve(conn, userID, squadID)
+	}))
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{Subprotocols: []string{Subprotocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// next reads messages until one of type msgType arrives
+func next(t *testing.T, conn *websocket.Conn, msgType string) map[string]interface{} {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		if msg["type"] == msgType {
+			return msg
+		}
+	}
+}
+
+func TestHub_RelaysCheersWithinSquad(t *testing.T) {
+	hub := NewHub(nil, memberRepo{}, &mocks.MockFocusService{})
+	squadID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+
+	aliceConn := dialRoom(t, hub, alice, squadID)
+	next(t, aliceConn, TypeWelcome)
+	bobConn := dialRoom(t, hub, bob, squadID)
+	next(t, bobConn, TypeWelcome)
+	next(t, aliceConn, TypePresenceJoined)
+
+	aliceConn.WriteJSON(map[string]interface{}{"v": 1, "type": TypeCheer, "data": map[string]string{"to": bob.String()}})
+
+	msg := next(t, bobConn, TypeCheer)
+	if msg["from"] != alice.String() {
+		t.Errorf("expected cheer from %s, got %v", alice, msg["from"])
+	}
+	if data := msg["data"].(map[string]interface{}); data["emoji"] != "🎉" {
+		t.Errorf("expected default emoji, got %v", data["emoji"])
+	}
+}
+
+func TestHub_RejectsBadMessages(t *testing.T) {
+	hub := NewHub(nil, memberRepo{}, &mocks.MockFocusService{})
+	conn := dialRoom(t, hub, uuid.New(), uuid.New())
+	next(t, conn, TypeWelcome)
+
+	tests := []struct {
+		name     string
+		message  string
+		wantCode string
+	}{
+		{"not json", "hello", ErrCodeInvalidMessage},
+		{"future version", `{"v":2,"type":"cheer"}`, ErrCodeUnsupportedVersion},
+		{"unknown type", `{"v":1,"type":"dance"}`, ErrCodeUnknownType},
+		{"group focus without plan", `{"v":1,"type":"group_focus.start","data":{}}`, ErrCodeInvalidMessage},
+		{"group focus over the cap", `{"v":1,"type":"group_focus.start","data":{"planned_minutes":721}}`, ErrCodeInvalidMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn.WriteMessage(websocket.TextMessage, []byte(tt.message))
+			msg := next(t, conn, TypeError)
+			if code := msg["data"].(map[string]interface{})["code"]; code != tt.wantCode {
+				t.Errorf("expected %s, got %v", tt.wantCode, code)
+			}
+		})
+	}
+}
+
+func TestHub_RateLimitsConnection(t *testing.T) {
+	hub := NewHub(nil, memberRepo{}, &mocks.MockFocusService{})
+	conn := dialRoom(t, hub, uuid.New(), uuid.New())
+	next(t, conn, TypeWelcome)
+
+	for i := 0; i < messageBurst+5; i++ {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"v":1,"type":"ping"}`))
+	}
+
+	msg := next(t, conn, TypeError)
+	if code := msg["data"].(map[string]interface{})["code"]; code != ErrCodeRateLimited {
+		t.Errorf("expected %s, got %v", ErrCodeRateLimited, code)
+	}
+}
+
+// clock is a settable time source for the hub
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func send(t *testing.T, conn *websocket.Conn, msgType string, data interface{}) {
+	t.Helper()
+
+	if err := conn.WriteJSON(map[string]interface{}{"v": ProtocolVersion, "type": msgType, "data": data}); err != nil {
+		t.Fatalf("send %s: %v", msgType, err)
+	}
+}
+
+func TestHub_GroupFocusJoinStartsSession(t *testing.T) {
+	sessionID := uuid.New()
+	focus := &mocks.MockFocusService{
+		StartFocusFunc: func(ctx context.Context, userID uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error) {
+			if req.PlannedMinutes != 50 || req.Goal != "Chapter 4" {
+				t.Errorf("expected the group's plan, got %d minutes and goal %q", req.PlannedMinutes, req.Goal)
+			}
+			return &domain.StartFocusResponse{SessionID: sessionID}, nil
+		},
+	}
+	hub := NewHub(nil, memberRepo{}, focus)
+	now := &clock{now: time.Now()}
+	hub.now = now.Now
+	conn := dialRoom(t, hub, uuid.New(), uuid.New())
+	next(t, conn, TypeWelcome)
+
+	send(t, conn, TypeGroupFocusStart, map[string]interface{}{"planned_minutes": 50, "goal": "Chapter 4"})
+	started := next(t, conn, TypeGroupFocusStarted)
+	groupID := started["data"].(map[string]interface{})["group_id"]
+
+	// The plan sent with the join is ignored
+	now.Advance(groupFocusCountdown)
+	send(t, conn, TypeGroupFocusJoin, map[string]interface{}{"group_id": groupID, "planned_minutes": 5})
+
+	msg := next(t, conn, TypeGroupFocusJoined)
+	if got := msg["data"].(map[string]interface{})["session_id"]; got != sessionID.String() {
+		t.Errorf("expected session %s, got %v", sessionID, got)
+	}
+
+	send(t, conn, TypeGroupFocusJoin, map[string]interface{}{"group_id": groupID})
+	if code := next(t, conn, TypeError)["data"].(map[string]interface{})["code"]; code != ErrCodeAlreadyJoined {
+		t.Errorf("expected %s, got %v", ErrCodeAlreadyJoined, code)
+	}
+}
+
+func TestHub_GroupFocusJoinRejected(t *testing.T) {
+	focus := &mocks.MockFocusService{
+		StartFocusFunc: func(ctx context.Context, userID uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error) {
+			return nil, errors.New("pq: connection refused")
+		},
+	}
+	hub := NewHub(nil, memberRepo{}, focus)
+	now := &clock{now: time.Now()}
+	hub.now = now.Now
+	conn := dialRoom(t, hub, uuid.New(), uuid.New())
+	next(t, conn, TypeWelcome)
+
+	errorData := func() map[string]interface{} {
+		return next(t, conn, TypeError)["data"].(map[string]interface{})
+	}
+
+	t.Run("UnknownGroup", func(t *testing.T) {
+		send(t, conn, TypeGroupFocusJoin, map[string]interface{}{"group_id": uuid.New()})
+		if code := errorData()["code"]; code != ErrCodeGroupNotFound {
+			t.Errorf("expected %s, got %v", ErrCodeGroupNotFound, code)
+		}
+	})
+
+	send(t, conn, TypeGroupFocusStart, map[string]interface{}{"planned_minutes": 25})
+	groupID := next(t, conn, TypeGroupFocusStarted)["data"].(map[string]interface{})["group_id"]
+
+	t.Run("FocusFailed", func(t *testing.T) {
+		now.Advance(groupFocusCountdown)
+		send(t, conn, TypeGroupFocusJoin, map[string]interface{}{"group_id": groupID})
+		data := errorData()
+		if data["code"] != ErrCodeFocusFailed {
+			t.Errorf("expected %s, got %v", ErrCodeFocusFailed, data["code"])
+		}
+		if strings.Contains(data["message"].(string), "pq:") {
+			t.Errorf("expected the internal error to stay on the server, got %q", data["message"])
+		}
+	})
+
+	t.Run("ExpiredGroup", func(t *testing.T) {
+		now.Advance(groupFocusJoinWindow + time.Second)
+		send(t, conn, TypeGroupFocusJoin, map[string]interface{}{"group_id": groupID})
+		if code := errorData()["code"]; code != ErrCodeGroupNotFound {
+			t.Errorf("expected %s, got %v", ErrCodeGroupNotFound, code)
+		}
+	})
+}
+
+func TestHub_ClosesConnectionsOfMembersWhoLeft(t *testing.T) {
+	hub := NewHub(nil, memberRepo{}, &mocks.MockFocusService{})
+	squadID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+
+	aliceConn := dialRoom(t, hub, alice, squadID)
+	next(t, aliceConn, TypeWelcome)
+	bobConn := dialRoom(t, hub, bob, squadID)
+	next(t, bobConn, TypeWelcome)
+	next(t, aliceConn, TypePresenceJoined)
+
+	removed, _ := json.Marshal(eventbus.NewSquadEvent(eventbus.SquadEventMemberLeft, squadID, bob))
+	hub.memberLeft(removed)
+
+	bobConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := bobConn.ReadMessage(); err != nil {
+			if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+				t.Errorf("expected a policy violation close, got %v", err)
+			}
+			break
+		}
+	}
+
+	if msg := next(t, aliceConn, TypePresenceLeft); msg["from"] != bob.String() {
+		t.Errorf("expected %s to leave the room, got %v", bob, msg["from"])
+	}
+}
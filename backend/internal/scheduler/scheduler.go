@@ -0,0 +1,275 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/robfig/cron/v3"
+)
+
+// Run triggers recorded in job_runs
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+type job struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	fn       domain.JobFunc
+	next     time.Time
+}
+
+// Scheduler runs named jobs on cron-style schedules (evaluated in UTC).
+// Every run is guarded by a Postgres advisory lock so that only one replica
+// executes a given job at a time, and is recorded in job_runs.
+type Scheduler struct {
+	repo     domain.JobRepository
+	instance string
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	jobs  map[string]*job
+	order []string
+}
+
+// New creates a new scheduler
+func New(repo domain.JobRepository) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		repo:     repo,
+		instance: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		now:      func() time.Time { return time.Now().UTC() },
+		jobs:     map[string]*job{},
+	}
+}
+
+// Register adds a job. spec is a standard 5-field cron expression
+// (e.g. "0 * * * *") or a descriptor such as "@hourly".
+func (s *Scheduler) Register(name, spec string, fn domain.JobFunc) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", spec, name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s already registered", name)
+	}
+	s.jobs[name] = &job{name: name, spec: spec, schedule: schedule, fn: fn}
+	s.order = append(s.order, name)
+	return nil
+}
+
+// Start registers all jobs in the database and starts one timer loop per job.
+// Loops stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, name := range s.order {
+		j := s.jobs[name]
+		if err := s.repo.EnsureJob(ctx, j.name, j.spec); err != nil {
+			return fmt.Errorf("register job %s: %w", j.name, err)
+		}
+		go s.loop(ctx, j)
+	}
+
+	log.Printf("⏰ Scheduler started with %d jobs (instance %s)", len(s.order), s.instance)
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	for {
+		next := j.schedule.Next(s.now())
+		s.mu.Lock()
+		j.next = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.runScheduled(ctx, j, next)
+		}
+	}
+}
+
+// runScheduled executes a job for its scheduled slot unless it is paused
+// or another replica already ran that slot
+func (s *Scheduler) runScheduled(ctx context.Context, j *job, slot time.Time) {
+	paused, err := s.repo.IsPaused(ctx, j.name)
+	if err != nil {
+		log.Printf("Job %s: failed to read paused state: %v", j.name, err)
+		return
+	}
+	if paused {
+		log.Printf("Job %s is paused, skipping", j.name)
+		return
+	}
+
+	release, acquired, err := s.repo.TryLock(ctx, j.name)
+	if err != nil {
+		log.Printf("Job %s: failed to acquire lock: %v", j.name, err)
+		return
+	}
+	if !acquired {
+		return // Another replica is running it
+	}
+
+	alreadyRan, err := s.repo.HasRunSince(ctx, j.name, slot)
+	if err != nil || alreadyRan {
+		release()
+		return
+	}
+
+	run, err := s.repo.StartRun(ctx, j.name, TriggerSchedule, s.instance)
+	if err != nil {
+		release()
+		log.Printf("Job %s: failed to record run: %v", j.name, err)
+		return
+	}
+
+	s.execute(ctx, j, run, release)
+}
+
+// execute runs the job body, records the outcome and releases the lock
+func (s *Scheduler) execute(ctx context.Context, j *job, run *domain.JobRun, release func()) {
+	defer release()
+
+	log.Printf("▶️ Job %s started (%s)", j.name, run.Trigger)
+	runErr := s.safeRun(ctx, j)
+	if runErr != nil {
+		log.Printf("❌ Job %s failed: %v", j.name, runErr)
+	} else {
+		log.Printf("✅ Job %s finished", j.name)
+	}
+
+	if err := s.repo.FinishRun(context.Background(), run.ID, runErr); err != nil {
+		log.Printf("Job %s: failed to record result: %v", j.name, err)
+	}
+}
+
+func (s *Scheduler) safeRun(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.fn(ctx)
+}
+
+func (s *Scheduler) getJob(name string) (*job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	return j, nil
+}
+
+// ListJobs returns all registered jobs with their state
+func (s *Scheduler) ListJobs(ctx context.Context) ([]domain.JobInfo, error) {
+	s.mu.RLock()
+	names := append([]string(nil), s.order...)
+	s.mu.RUnlock()
+
+	jobs := []domain.JobInfo{}
+	for _, name := range names {
+		j, _ := s.getJob(name)
+
+		paused, err := s.repo.IsPaused(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		lastRun, err := s.repo.GetLastRun(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		info := domain.JobInfo{
+			Name:     j.name,
+			Schedule: j.spec,
+			Paused:   paused,
+			LastRun:  lastRun,
+		}
+		s.mu.RLock()
+		if !j.next.IsZero() {
+			next := j.next
+			info.NextRun = &next
+		}
+		s.mu.RUnlock()
+
+		jobs = append(jobs, info)
+	}
+
+	return jobs, nil
+}
+
+// GetJobRuns returns the run history of a job
+func (s *Scheduler) GetJobRuns(ctx context.Context, name string, limit int) ([]domain.JobRun, error) {
+	if _, err := s.getJob(name); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.ListRuns(ctx, name, limit)
+}
+
+// PauseJob stops scheduled runs of a job on every replica
+func (s *Scheduler) PauseJob(ctx context.Context, name string) error {
+	if _, err := s.getJob(name); err != nil {
+		return err
+	}
+	return s.repo.SetPaused(ctx, name, true)
+}
+
+// ResumeJob re-enables scheduled runs of a job
+func (s *Scheduler) ResumeJob(ctx context.Context, name string) error {
+	if _, err := s.getJob(name); err != nil {
+		return err
+	}
+	return s.repo.SetPaused(ctx, name, false)
+}
+
+// TriggerJob runs a job immediately, even if paused. The lock is taken
+// synchronously so callers learn right away if the job is already running;
+// the job body itself runs in the background.
+func (s *Scheduler) TriggerJob(ctx context.Context, name string) (*domain.JobRun, error) {
+	j, err := s.getJob(name)
+	if err != nil {
+		return nil, err
+	}
+
+	release, acquired, err := s.repo.TryLock(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, domain.ErrJobAlreadyRunning
+	}
+
+	run, err := s.repo.StartRun(ctx, name, TriggerManual, s.instance)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// Detach from the request context so the run outlives the HTTP call
+	go s.execute(context.Background(), j, run, release)
+
+	return run, nil
+}
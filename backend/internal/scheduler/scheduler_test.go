@@ -0,0 +1,200 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fakeJobRepository is an in-memory domain.JobRepository
+type fakeJobRepository struct {
+	mu       sync.Mutex
+	paused   map[string]bool
+	locked   map[string]bool
+	runs     []domain.JobRun
+	finished chan domain.JobRun
+}
+
+func newFakeJobRepository() *fakeJobRepository {
+	return &fakeJobRepository{
+		paused:   map[string]bool{},
+		locked:   map[string]bool{},
+		finished: make(chan domain.JobRun, 10),
+	}
+}
+
+func (f *fakeJobRepository) EnsureJob(ctx context.Context, name, schedule string) error {
+	return nil
+}
+
+func (f *fakeJobRepository) IsPaused(ctx context.Context, name string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.paused[name], nil
+}
+
+func (f *fakeJobRepository) SetPaused(ctx context.Context, name string, paused bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paused[name] = paused
+	return nil
+}
+
+func (f *fakeJobRepository) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locked[name] {
+		return nil, false, nil
+	}
+	f.locked[name] = true
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.locked[name] = false
+	}, true, nil
+}
+
+func (f *fakeJobRepository) HasRunSince(ctx context.Context, name string, since time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, run := range f.runs {
+		if run.JobName == name && !run.StartedAt.Before(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeJobRepository) StartRun(ctx context.Context, name, trigger, instance string) (*domain.JobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	run := domain.JobRun{
+		ID:        uuid.New(),
+		JobName:   name,
+		Status:    domain.JobRunStatusRunning,
+		Trigger:   trigger,
+		Instance:  instance,
+		StartedAt: time.Now().UTC(),
+	}
+	f.runs = append(f.runs, run)
+	return &run, nil
+}
+
+func (f *fakeJobRepository) FinishRun(ctx context.Context, runID uuid.UUID, runErr error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.runs {
+		if f.runs[i].ID == runID {
+			f.runs[i].Status = domain.JobRunStatusSucceeded
+			if runErr != nil {
+				f.runs[i].Status = domain.JobRunStatusFailed
+			}
+			f.finished <- f.runs[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeJobRepository) GetLastRun(ctx context.Context, name string) (*domain.JobRun, error) {
+	return nil, nil
+}
+
+func (f *fakeJobRepository) ListRuns(ctx context.Context, name string, limit int) ([]domain.JobRun, error) {
+	return nil, nil
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := New(newFakeJobRepository())
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := s.Register("hourly", "0 * * * *", noop); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Register("hourly", "0 * * * *", noop); err == nil {
+		t.Error("expected error for duplicate job name")
+	}
+	if err := s.Register("bad", "not a cron", noop); err == nil {
+		t.Error("expected error for invalid schedule")
+	}
+}
+
+func TestScheduler_TriggerJob(t *testing.T) {
+	t.Run("RecordsFailure", func(t *testing.T) {
+		repo := newFakeJobRepository()
+		s := New(repo)
+		_ = s.Register("recalc", "@daily", func(ctx context.Context) error {
+			return errors.New("boom")
+		})
+
+		run, err := s.TriggerJob(context.Background(), "recalc")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if run.Trigger != TriggerManual {
+			t.Errorf("expected trigger %s, got %s", TriggerManual, run.Trigger)
+		}
+
+		finished := <-repo.finished
+		if finished.Status != domain.JobRunStatusFailed {
+			t.Errorf("expected status failed, got %s", finished.Status)
+		}
+	})
+
+	t.Run("AlreadyRunning", func(t *testing.T) {
+		repo := newFakeJobRepository()
+		s := New(repo)
+		_ = s.Register("recalc", "@daily", func(ctx context.Context) error { return nil })
+
+		release, _, _ := repo.TryLock(context.Background(), "recalc")
+		defer release()
+
+		if _, err := s.TriggerJob(context.Background(), "recalc"); !errors.Is(err, domain.ErrJobAlreadyRunning) {
+			t.Errorf("expected ErrJobAlreadyRunning, got %v", err)
+		}
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		s := New(newFakeJobRepository())
+		if _, err := s.TriggerJob(context.Background(), "missing"); !errors.Is(err, domain.ErrJobNotFound) {
+			t.Errorf("expected ErrJobNotFound, got %v", err)
+		}
+	})
+}
+
+func TestScheduler_RunScheduled(t *testing.T) {
+	t.Run("SkipsPausedJob", func(t *testing.T) {
+		repo := newFakeJobRepository()
+		s := New(repo)
+		ran := false
+		_ = s.Register("risk", "@hourly", func(ctx context.Context) error { ran = true; return nil })
+		_ = s.PauseJob(context.Background(), "risk")
+
+		j, _ := s.getJob("risk")
+		s.runScheduled(context.Background(), j, time.Now().UTC())
+
+		if ran {
+			t.Error("expected paused job not to run")
+		}
+	})
+
+	t.Run("SkipsSlotAlreadyRun", func(t *testing.T) {
+		repo := newFakeJobRepository()
+		s := New(repo)
+		count := 0
+		_ = s.Register("risk", "@hourly", func(ctx context.Context) error { count++; return nil })
+
+		j, _ := s.getJob("risk")
+		slot := time.Now().UTC().Add(-time.Second)
+		s.runScheduled(context.Background(), j, slot)
+		s.runScheduled(context.Background(), j, slot) // Second replica firing for the same slot
+
+		if count != 1 {
+			t.Errorf("expected job to run once, ran %d times", count)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/ical"
+	"github.com/antigravity/backend/internal/streak"
+	"github.com/google/uuid"
+)
+
+// Calendar feed windows
+const (
+	calendarPastWindow      = 90 * 24 * time.Hour // Focus sessions and study rooms shown
+	calendarRefreshInterval = time.Hour           // Suggested to calendar clients
+	calendarDailyDeadlines  = 14                  // Upcoming deadlines in daily mode
+	calendarWeeklyDeadlines = 4                   // Upcoming deadlines in weekly mode
+	streakDeadlineLength    = 30 * time.Minute    // Deadline events end at local midnight
+)
+
+// calendarFeedPath is where feeds are served, followed by "<token>.ics"
+const calendarFeedPath = "/api/v1/calendar/feeds/"
+
+// CalendarService handles iCalendar feeds of squad sessions and streak deadlines
+type CalendarService struct {
+	repo      domain.CalendarRepository
+	publicURL string // Base URL feed links are built on
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(repo domain.CalendarRepository, publicURL string) *CalendarService {
+	return &CalendarService{repo: repo, publicURL: strings.TrimSuffix(publicURL, "/")}
+}
+
+// GetFeed returns whether the user has a feed
+func (s *CalendarService) GetFeed(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	return s.repo.GetFeed(ctx, userID)
+}
+
+// CreateFeed generates a new secret feed URL for the user. Any previous
+// URL stops working.
+func (s *CalendarService) CreateFeed(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedURL, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	createdAt, err := s.repo.SetFeedToken(ctx, userID, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	url := s.publicURL + calendarFeedPath + token + ".ics"
+	webcal := url
+	for _, scheme := range []string{"https://", "http://"} {
+		if rest, ok := strings.CutPrefix(url, scheme); ok {
+			webcal = "webcal://" + rest
+			break
+		}
+	}
+
+	return &domain.CalendarFeedURL{URL: url, WebcalURL: webcal, CreatedAt: createdAt}, nil
+}
+
+// RevokeFeed turns the user's feed off
+func (s *CalendarService) RevokeFeed(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.DeleteFeed(ctx, userID)
+}
+
+// RenderFeed renders the feed a token belongs to: the owner's study rooms,
+// their past focus sessions as busy blocks, and their upcoming streak
+// deadlines at local midnight
+func (s *CalendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, domain.ErrCalendarFeedNotFound
+	}
+	owner, err := s.repo.GetOwnerByToken(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, domain.ErrCalendarFeedNotFound
+	}
+
+	now := time.Now()
+	rooms, err := s.repo.GetStudyRooms(ctx, owner.UserID, now.Add(-calendarPastWindow), now.Add(maxStudyRoomLead))
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.repo.GetFocusSessions(ctx, owner.UserID, now.Add(-calendarPastWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.Calendar{
+		ProdID:          "-//Antigravity//Study Squads//EN",
+		Name:            "Antigravity",
+		RefreshInterval: calendarRefreshInterval,
+	}
+	for _, room := range rooms {
+		cal.Events = append(cal.Events, studyRoomEvent(room))
+	}
+	for _, session := range sessions {
+		cal.Events = append(cal.Events, focusSessionEvent(session))
+	}
+	cal.Events = append(cal.Events, streakDeadlineEvents(owner, now)...)
+
+	return cal.Encode(now), nil
+}
+
+// hashFeedToken is how feed tokens are stored and looked up
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// studyRoomEvent shows a study room, blocking time only if the owner is going
+func studyRoomEvent(room domain.CalendarStudyRoom) ical.Event {
+	event := ical.Event{
+		UID:         "study-room-" + room.ID.String() + "@antigravity",
+		Start:       room.StartsAt,
+		End:         room.StartsAt.Add(time.Duration(room.DurationMinutes) * time.Minute),
+		Summary:     "📚 " + room.Topic,
+		Description: fmt.Sprintf("Study room in %s, hosted by %s.", room.SquadName, room.HostName),
+		Status:      ical.StatusTentative,
+		Transparent: true,
+	}
+
+	switch {
+	case room.Cancelled:
+		event.Status = ical.StatusCancelled
+	case room.RSVP != nil && *room.RSVP == domain.RSVPGoing:
+		event.Status = ical.StatusConfirmed
+		event.Transparent = false
+	}
+	return event
+}
+
+// focusSessionEvent shows an ended focus session as a busy block
+func focusSessionEvent(session domain.CalendarFocusSession) ical.Event {
+	description := fmt.Sprintf("Focused %d min solo.", session.DurationMinutes)
+	if session.SquadName != nil {
+		description = fmt.Sprintf("Focused %d min with %s.", session.DurationMinutes, *session.SquadName)
+	}
+	if session.Goal != nil {
+		description += "\nGoal: " + *session.Goal
+	}
+
+	return ical.Event{
+		UID:         "focus-" + session.ID.String() + "@antigravity",
+		Start:       session.StartedAt,
+		End:         session.EndedAt,
+		Summary:     "🎯 Focus session",
+		Description: description,
+		Status:      ical.StatusConfirmed,
+	}
+}
+
+// streakDeadlineEvents marks the owner's upcoming streak deadlines: each
+// local midnight in daily mode, or the end of each ISO week in weekly mode.
+// Deadlines are free time, ending at the deadline.
+func streakDeadlineEvents(owner *domain.CalendarOwner, now time.Time) []ical.Event {
+	loc := loadLocation(owner.Timezone)
+	today := streak.DateOf(now, loc)
+
+	var deadlines []time.Time
+	description := "Log a focus session before midnight to keep your streak alive."
+	if target := owner.StreakMode.WeeklyTarget(); target > 0 {
+		weekEnd := streak.WeekStart(today).AddDate(0, 0, 7)
+		for i := 0; i < calendarWeeklyDeadlines; i++ {
+			deadlines = append(deadlines, weekEnd.AddDate(0, 0, 7*i))
+		}
+		description = fmt.Sprintf("Last chance this week to reach %d active days and keep your weekly streak alive.", target)
+	} else {
+		for i := 1; i <= calendarDailyDeadlines; i++ {
+			deadlines = append(deadlines, today.AddDate(0, 0, i))
+		}
+	}
+
+	events := make([]ical.Event, 0, len(deadlines))
+	for _, date := range deadlines {
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		events = append(events, ical.Event{
+			UID:         fmt.Sprintf("streak-deadline-%s-%s@antigravity", date.Format("20060102"), owner.UserID),
+			Start:       midnight.Add(-streakDeadlineLength),
+			End:         midnight,
+			Summary:     "🔥 Streak deadline",
+			Description: description,
+			Transparent: true,
+		})
+	}
+	return events
+}
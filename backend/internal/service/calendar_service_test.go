@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestStreakDeadlineEvents(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	t.Run("daily deadlines are local midnights across DST", func(t *testing.T) {
+		owner := &domain.CalendarOwner{UserID: uuid.New(), Timezone: "America/New_York", StreakMode: domain.StreakModeDaily}
+		now := time.Date(2026, 3, 6, 20, 0, 0, 0, newYork) // DST starts on Mar 8
+
+		events := streakDeadlineEvents(owner, now)
+		if len(events) != calendarDailyDeadlines {
+			t.Fatalf("expected %d deadlines, got %d", calendarDailyDeadlines, len(events))
+		}
+		for i, e := range events {
+			end := e.End.In(newYork)
+			if end.Hour() != 0 || end.Minute() != 0 {
+				t.Errorf("deadline %d ends at %s, not local midnight", i, end)
+			}
+			if want := time.Date(2026, 3, 7+i, 0, 0, 0, 0, newYork); !end.Equal(want) {
+				t.Errorf("deadline %d ends at %s, want %s", i, end, want)
+			}
+			if !e.Transparent {
+				t.Errorf("deadline %d should not block time", i)
+			}
+		}
+	})
+
+	t.Run("weekly deadlines end ISO weeks", func(t *testing.T) {
+		owner := &domain.CalendarOwner{UserID: uuid.New(), Timezone: "UTC", StreakMode: domain.WeeklyStreakMode(3)}
+		now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC) // Wednesday
+
+		events := streakDeadlineEvents(owner, now)
+		if len(events) != calendarWeeklyDeadlines {
+			t.Fatalf("expected %d deadlines, got %d", calendarWeeklyDeadlines, len(events))
+		}
+		if want := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC); !events[0].End.Equal(want) {
+			t.Errorf("first deadline ends at %s, want %s", events[0].End, want)
+		}
+		if events[1].End.Sub(events[0].End) != 7*24*time.Hour {
+			t.Errorf("expected weekly deadlines, got %s then %s", events[0].End, events[1].End)
+		}
+	})
+}
@@ -2,11 +2,22 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/focusstats"
+	"github.com/antigravity/backend/internal/pomodoro"
+	"github.com/antigravity/backend/internal/streak"
 	"github.com/google/uuid"
 )
 
@@ -14,47 +25,200 @@ var (
 	ErrNotSquadMember  = errors.New("not a member of this squad")
 	ErrNoActiveSession = errors.New("no active focus session")
 	ErrAlreadyFocusing = errors.New("already have an active focus session")
+	ErrInvalidPomodoro = errors.New("invalid pomodoro config")
+	ErrInvalidPlan     = errors.New("invalid planned duration")
+	ErrInvalidGoal     = errors.New("invalid focus goal")
+	ErrSessionNotFound = errors.New("focus session not found")
+	ErrSessionActive   = errors.New("focus session is still active")
+	ErrNoGoal          = errors.New("focus session has no goal")
+	ErrAlreadyPaused   = errors.New("focus session is already paused")
+	ErrNotPaused       = errors.New("focus session is not paused")
+	ErrInvalidStats    = errors.New("invalid stats period")
+	ErrInvalidHistory  = errors.New("invalid history filter")
+	ErrInvalidCursor   = errors.New("invalid history cursor")
+	ErrInvalidTags     = errors.New("invalid tags")
+	ErrInvalidNote     = errors.New("invalid note")
+	ErrInvalidRange    = errors.New("invalid date range")
+	ErrInvalidBackfill = errors.New("invalid backfilled session")
+	ErrNotSolo         = errors.New("only solo sessions can be broadcast")
+)
+
+// Plan and goal bounds
+const (
+	maxPlannedMinutes = 720
+	maxGoalLength     = 200 // Characters
+)
+
+// Backfilled sessions must have ended within this long ago
+const maxBackfillAge = 48 * time.Hour
+
+// Tag and note bounds
+const (
+	maxSessionTags   = 5
+	maxFavouriteTags = 20
+	maxTagLength     = 32   // Characters
+	maxNoteLength    = 1000 // Characters
+	recentTagsShown  = 20
+)
+
+// Pomodoro config bounds, in minutes (cycles for the last)
+const (
+	maxPomodoroWork       = 180
+	maxPomodoroShortBreak = 60
+	maxPomodoroLongBreak  = 120
+	maxPomodoroCycles     = 12
 )
 
 // FocusService handles business logic for focus sessions
 type FocusService struct {
-	repo      domain.FocusRepository
-	publisher *eventbus.Publisher
+	repo             domain.FocusRepository
+	publisher        *eventbus.Publisher
+	autoStopGrace    time.Duration // How long past its plan a session may run
+	heartbeatTimeout time.Duration // Silence after which a session is abandoned
+	backfillDailyCap int           // Backfilled minutes allowed per local day
 }
 
 // NewFocusService creates a new focus service
-func NewFocusService(repo domain.FocusRepository, publisher *eventbus.Publisher) *FocusService {
-	return &FocusService{repo: repo, publisher: publisher}
+func NewFocusService(repo domain.FocusRepository, publisher *eventbus.Publisher, autoStopGrace, heartbeatTimeout time.Duration, backfillDailyCap int) *FocusService {
+	return &FocusService{
+		repo:             repo,
+		publisher:        publisher,
+		autoStopGrace:    autoStopGrace,
+		heartbeatTimeout: heartbeatTimeout,
+		backfillDailyCap: backfillDailyCap,
+	}
 }
 
-// StartFocus starts a new focus session for a user
-func (s *FocusService) StartFocus(ctx context.Context, userID uuid.UUID, squadID uuid.UUID) (*domain.StartFocusResponse, error) {
-	// Validate squad membership
-	isMember, err := s.repo.IsMemberOfSquad(ctx, userID, squadID)
+// StartFocus starts a new focus session for a user, optionally in pomodoro
+// mode. Without a squad it's a solo session, which may be broadcast to all
+// of the user's squads.
+func (s *FocusService) StartFocus(ctx context.Context, userID uuid.UUID, req *domain.StartFocusRequest) (*domain.StartFocusResponse, error) {
+	if req.SquadID != nil && req.Broadcast {
+		return nil, ErrNotSolo
+	}
+
+	normalized := *req
+	if req.Pomodoro != nil {
+		c, err := normalizePomodoro(*req.Pomodoro)
+		if err != nil {
+			return nil, err
+		}
+		normalized.Pomodoro = &c
+	}
+	if req.PlannedMinutes < 0 || req.PlannedMinutes > maxPlannedMinutes {
+		return nil, ErrInvalidPlan
+	}
+	normalized.Goal = strings.TrimSpace(req.Goal)
+	if utf8.RuneCountInString(normalized.Goal) > maxGoalLength {
+		return nil, ErrInvalidGoal
+	}
+	tags, err := normalizeTags(req.Tags, maxSessionTags)
 	if err != nil {
 		return nil, err
 	}
-	if !isMember {
-		return nil, ErrNotSquadMember
+	normalized.Tags = tags
+
+	if err := s.checkSquad(ctx, userID, req.SquadID); err != nil {
+		return nil, err
 	}
 
 	// End any existing active session first
-	_, _ = s.repo.EndSession(ctx, userID)
+	if previous, err := s.repo.EndSession(ctx, userID); err == nil && previous != nil {
+		s.finishSession(ctx, previous)
+	}
 
 	// Start new session
-	session, err := s.repo.StartSession(ctx, userID, squadID)
+	session, err := s.repo.StartSession(ctx, userID, &normalized)
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.StartFocusResponse{
-		SessionID: session.ID,
-		StartedAt: session.StartedAt,
-	}, nil
+	response := &domain.StartFocusResponse{
+		SessionID:      session.ID,
+		SquadID:        session.SquadID,
+		Broadcast:      session.Broadcast,
+		StartedAt:      session.StartedAt,
+		PlannedMinutes: session.PlannedMinutes,
+		Goal:           session.Goal,
+		Tags:           session.Tags,
+		StudyRoomID:    session.StudyRoomID,
+	}
+	if session.Pomodoro != nil {
+		response.Pomodoro = pomodoroState(*session.Pomodoro, 0, session.StartedAt)
+	}
+
+	s.publishSquadEvent(ctx, eventbus.SquadEventFocusStarted, session, "")
+
+	return response, nil
 }
 
-// StopFocus ends the user's current active focus session
-func (s *FocusService) StopFocus(ctx context.Context, userID uuid.UUID) (*domain.StopFocusResponse, error) {
+// normalizePomodoro fills zero fields with the classic defaults and checks bounds
+func normalizePomodoro(c domain.PomodoroConfig) (domain.PomodoroConfig, error) {
+	defaults := pomodoro.DefaultConfig
+	if c.WorkMinutes == 0 {
+		c.WorkMinutes = int(defaults.Work / time.Minute)
+	}
+	if c.ShortBreakMinutes == 0 {
+		c.ShortBreakMinutes = int(defaults.ShortBreak / time.Minute)
+	}
+	if c.LongBreakMinutes == 0 {
+		c.LongBreakMinutes = int(defaults.LongBreak / time.Minute)
+	}
+	if c.Cycles == 0 {
+		c.Cycles = defaults.Cycles
+	}
+
+	if c.WorkMinutes < 1 || c.WorkMinutes > maxPomodoroWork ||
+		c.ShortBreakMinutes < 1 || c.ShortBreakMinutes > maxPomodoroShortBreak ||
+		c.LongBreakMinutes < 1 || c.LongBreakMinutes > maxPomodoroLongBreak ||
+		c.Cycles < 1 || c.Cycles > maxPomodoroCycles {
+		return c, ErrInvalidPomodoro
+	}
+	return c, nil
+}
+
+// pomodoroSchedule converts a stored config to the pomodoro engine's
+func pomodoroSchedule(c domain.PomodoroConfig) pomodoro.Config {
+	return pomodoro.Config{
+		Work:       time.Duration(c.WorkMinutes) * time.Minute,
+		ShortBreak: time.Duration(c.ShortBreakMinutes) * time.Minute,
+		LongBreak:  time.Duration(c.LongBreakMinutes) * time.Minute,
+		Cycles:     c.Cycles,
+	}
+}
+
+// pomodoroState is the phase of a session after focused (net of pauses) time, as of now
+func pomodoroState(c domain.PomodoroConfig, focused time.Duration, now time.Time) *domain.PomodoroState {
+	state := pomodoro.At(pomodoroSchedule(c), focused)
+	return &domain.PomodoroState{
+		Config:             c,
+		Phase:              domain.PomodoroPhase(state.Phase),
+		Cycle:              state.Cycle,
+		CompletedPomodoros: state.Completed,
+		PhaseEndsAt:        now.Add(state.Remaining),
+		SecondsLeft:        int(state.Remaining.Seconds()),
+	}
+}
+
+// StopFocus ends the user's current active focus session. If the session
+// had a goal, req may carry the outcome; otherwise the response asks for it.
+func (s *FocusService) StopFocus(ctx context.Context, userID uuid.UUID, req *domain.StopFocusRequest) (*domain.StopFocusResponse, error) {
+	// Validate the log before ending, so a bad note doesn't lose it
+	var tags []string
+	var note string
+	if req != nil {
+		var err error
+		if req.Tags != nil {
+			if tags, err = normalizeTags(req.Tags, maxSessionTags); err != nil {
+				return nil, err
+			}
+		}
+		note = strings.TrimSpace(req.Note)
+		if utf8.RuneCountInString(note) > maxNoteLength {
+			return nil, ErrInvalidNote
+		}
+	}
+
 	session, err := s.repo.EndSession(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -63,27 +227,385 @@ func (s *FocusService) StopFocus(ctx context.Context, userID uuid.UUID) (*domain
 		return nil, ErrNoActiveSession
 	}
 
+	durationMinutes := s.finishSession(ctx, session)
+
+	if req != nil && req.GoalMet != nil && session.Goal != nil {
+		if err := s.repo.SetGoalMet(ctx, session.ID, *req.GoalMet); err != nil {
+			log.Printf("Failed to store goal outcome for %s: %v", session.ID, err)
+		} else {
+			session.GoalMet = req.GoalMet
+		}
+	}
+	if tags != nil {
+		if err := s.repo.SetTags(ctx, session.ID, tags); err != nil {
+			log.Printf("Failed to store tags for %s: %v", session.ID, err)
+		} else {
+			session.Tags = tags
+		}
+	}
+	if note != "" {
+		if err := s.repo.SetNote(ctx, session.ID, note); err != nil {
+			log.Printf("Failed to store note for %s: %v", session.ID, err)
+		} else {
+			session.Note = &note
+		}
+	}
+
+	return &domain.StopFocusResponse{
+		SessionID:          session.ID,
+		DurationMinutes:    durationMinutes,
+		CompletedPomodoros: session.CompletedPomodoros,
+		Goal:               session.Goal,
+		GoalMet:            session.GoalMet,
+		OutcomePending:     session.Goal != nil && session.GoalMet == nil,
+		Tags:               session.Tags,
+		Note:               session.Note,
+	}, nil
+}
+
+// LogSession backfills a session the user did offline. It must have ended
+// in the last 48 hours, not overlap their other sessions and stay within
+// the daily backfill cap. Its streak credit goes to the local day it ended
+// on, like a live session stopped at that time.
+func (s *FocusService) LogSession(ctx context.Context, userID uuid.UUID, req *domain.LogSessionRequest) (*domain.FocusSession, error) {
+	now := time.Now()
+	minutes := int(req.EndedAt.Sub(req.StartedAt).Minutes())
+	if req.EndedAt.After(now) || now.Sub(req.EndedAt) > maxBackfillAge || minutes < 1 || minutes > maxPlannedMinutes {
+		return nil, ErrInvalidBackfill
+	}
+
+	normalized := *req
+	normalized.Goal = strings.TrimSpace(req.Goal)
+	if utf8.RuneCountInString(normalized.Goal) > maxGoalLength {
+		return nil, ErrInvalidGoal
+	}
+	if normalized.Goal == "" && req.GoalMet != nil {
+		return nil, ErrNoGoal
+	}
+	tags, err := normalizeTags(req.Tags, maxSessionTags)
+	if err != nil {
+		return nil, err
+	}
+	normalized.Tags = tags
+	normalized.Note = strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(normalized.Note) > maxNoteLength {
+		return nil, ErrInvalidNote
+	}
+
+	if err := s.checkSquad(ctx, userID, req.SquadID); err != nil {
+		return nil, err
+	}
+
+	// The cap applies per local day the sessions ended on
+	timezone, err := s.repo.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := loadLocation(timezone)
+	day := streak.DateOf(req.EndedAt, loc)
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	dayEnd := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+
+	session, err := s.repo.CreateManualSession(ctx, userID, &normalized, dayStart, dayEnd, s.backfillDailyCap)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.publisher != nil {
+		event := eventbus.NewActivityLoggedEvent(userID, "focus_session")
+		if session.SquadID != nil {
+			event.SquadID = session.SquadID.String()
+		}
+		event.SessionID = session.ID.String()
+		event.Duration = minutes
+		event.ActivityDate = day.Format("2006-01-02")
+		if err := s.publisher.PublishActivityLogged(ctx, event); err != nil {
+			log.Printf("Failed to publish activity event: %v", err)
+		}
+	}
+	s.publishSquadEvent(ctx, eventbus.SquadEventFocusBackfilled, session, "")
+
+	return session, nil
+}
+
+// normalizeTags lowercases tags, collapses their whitespace and drops
+// empty and repeated ones, keeping the first-seen order
+func normalizeTags(tags []string, max int) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength || strings.IndexFunc(tag, invalidTagRune) >= 0 {
+			return nil, ErrInvalidTags
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > max {
+		return nil, ErrInvalidTags
+	}
+	return normalized, nil
+}
+
+// invalidTagRune allows letters, digits, spaces and a few separators
+func invalidTagRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && !strings.ContainsRune(" -_+#./&", r)
+}
+
+// finishSession runs the bookkeeping for a session that just ended and
+// returns its duration in minutes
+func (s *FocusService) finishSession(ctx context.Context, session *domain.FocusSession) int {
 	durationMinutes := 0
 	if session.DurationMinutes != nil {
 		durationMinutes = *session.DurationMinutes
 	}
 
+	// Store finished work blocks for history and stats
+	if session.Pomodoro != nil && session.EndedAt != nil {
+		focused := session.EndedAt.Sub(session.StartedAt) - time.Duration(session.PausedSeconds)*time.Second
+		session.CompletedPomodoros = pomodoro.Completed(pomodoroSchedule(*session.Pomodoro), focused)
+		if err := s.repo.SetCompletedPomodoros(ctx, session.ID, session.CompletedPomodoros); err != nil {
+			log.Printf("Failed to store completed pomodoros for %s: %v", session.ID, err)
+		}
+	}
+
 	// Publish activity event
 	if s.publisher != nil {
-		event := eventbus.NewActivityLoggedEvent(userID, "focus_session")
-		event.SquadID = session.SquadID.String()
+		event := eventbus.NewActivityLoggedEvent(session.UserID, "focus_session")
+		if session.SquadID != nil {
+			event.SquadID = session.SquadID.String()
+		}
+		event.SessionID = session.ID.String()
 		event.Duration = durationMinutes
 		if err := s.publisher.PublishActivityLogged(ctx, event); err != nil {
 			log.Printf("Failed to publish activity event: %v", err)
 		}
 	}
 
-	return &domain.StopFocusResponse{
+	reason := "stopped"
+	if session.AutoEnded {
+		reason = "auto_stopped"
+	}
+	s.publishSquadEvent(ctx, eventbus.SquadEventFocusStopped, session, reason)
+
+	return durationMinutes
+}
+
+// checkSquad checks that the user may focus in a squad; solo sessions
+// (nil) need no squad
+func (s *FocusService) checkSquad(ctx context.Context, userID uuid.UUID, squadID *uuid.UUID) error {
+	if squadID == nil {
+		return nil
+	}
+	isMember, err := s.repo.IsMemberOfSquad(ctx, userID, *squadID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return ErrNotSquadMember
+	}
+	return nil
+}
+
+// publishSquadEvent tells the session's squad about a focus change. A
+// broadcast solo session is announced in every squad of its owner, and a
+// private one in none.
+func (s *FocusService) publishSquadEvent(ctx context.Context, kind string, session *domain.FocusSession, reason string) {
+	if s.publisher == nil {
+		return
+	}
+
+	var squadIDs []uuid.UUID
+	switch {
+	case session.SquadID != nil:
+		squadIDs = []uuid.UUID{*session.SquadID}
+	case session.Broadcast:
+		ids, err := s.repo.GetUserSquadIDs(ctx, session.UserID)
+		if err != nil {
+			log.Printf("Failed to load squads of %s: %v", session.UserID, err)
+			return
+		}
+		squadIDs = ids
+	}
+
+	for _, squadID := range squadIDs {
+		event := eventbus.NewSquadEvent(kind, squadID, session.UserID)
+		event.SessionID = session.ID.String()
+		event.Reason = reason
+		event.Solo = session.SquadID == nil
+		if session.DurationMinutes != nil {
+			event.DurationMinutes = *session.DurationMinutes
+		}
+		if err := s.publisher.PublishSquadEvent(ctx, event); err != nil {
+			log.Printf("Failed to publish squad event: %v", err)
+		}
+	}
+}
+
+// SetBroadcast shows or hides the user's active solo session in all their
+// squads. Squads see it start or stop, as if it had just begun or ended.
+func (s *FocusService) SetBroadcast(ctx context.Context, userID uuid.UUID, broadcast bool) (*domain.FocusSession, error) {
+	active, err := s.repo.GetActiveSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, ErrNoActiveSession
+	}
+	if active.SquadID != nil {
+		return nil, ErrNotSolo
+	}
+	if active.Broadcast == broadcast {
+		return active, nil
+	}
+
+	session, err := s.repo.SetBroadcast(ctx, userID, broadcast)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrNoActiveSession // Ended meanwhile
+	}
+
+	if broadcast {
+		s.publishSquadEvent(ctx, eventbus.SquadEventFocusStarted, session, "")
+	} else {
+		// Announce the stop through a copy still marked broadcast, so it
+		// reaches the squads that saw the start
+		announced := *session
+		announced.Broadcast = true
+		s.publishSquadEvent(ctx, eventbus.SquadEventFocusStopped, &announced, "hidden")
+	}
+
+	return session, nil
+}
+
+// SetOutcome records whether the goal of one of the user's ended sessions was met
+func (s *FocusService) SetOutcome(ctx context.Context, userID, sessionID uuid.UUID, goalMet bool) (*domain.FocusSession, error) {
+	session, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID {
+		return nil, ErrSessionNotFound
+	}
+	if session.EndedAt == nil {
+		return nil, ErrSessionActive
+	}
+	if session.Goal == nil {
+		return nil, ErrNoGoal
+	}
+
+	if err := s.repo.SetGoalMet(ctx, sessionID, goalMet); err != nil {
+		return nil, err
+	}
+	session.GoalMet = &goalMet
+
+	return session, nil
+}
+
+// Heartbeat keeps the user's active session alive
+func (s *FocusService) Heartbeat(ctx context.Context, userID uuid.UUID) (*domain.HeartbeatResponse, error) {
+	session, err := s.repo.Heartbeat(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrNoActiveSession
+	}
+
+	return &domain.HeartbeatResponse{
 		SessionID:       session.ID,
-		DurationMinutes: durationMinutes,
+		LastHeartbeatAt: session.LastHeartbeatAt,
+		TimeoutSeconds:  int(s.heartbeatTimeout.Seconds()),
 	}, nil
 }
 
+// PauseFocus pauses the user's active session. Paused time doesn't count
+// toward its duration, plan or pomodoro phases.
+func (s *FocusService) PauseFocus(ctx context.Context, userID uuid.UUID) (*domain.FocusPauseResponse, error) {
+	active, err := s.repo.GetActiveSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, ErrNoActiveSession
+	}
+
+	session, err := s.repo.PauseSession(ctx, active.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrAlreadyPaused
+	}
+
+	s.publishSquadEvent(ctx, eventbus.SquadEventFocusPaused, session, "")
+
+	return pauseResponse(session), nil
+}
+
+// ResumeFocus resumes the user's paused session
+func (s *FocusService) ResumeFocus(ctx context.Context, userID uuid.UUID) (*domain.FocusPauseResponse, error) {
+	active, err := s.repo.GetActiveSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, ErrNoActiveSession
+	}
+
+	session, err := s.repo.ResumeSession(ctx, active.ID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrNotPaused
+	}
+
+	s.publishSquadEvent(ctx, eventbus.SquadEventFocusResumed, session, "")
+
+	return pauseResponse(session), nil
+}
+
+func pauseResponse(session *domain.FocusSession) *domain.FocusPauseResponse {
+	return &domain.FocusPauseResponse{
+		SessionID:     session.ID,
+		Paused:        session.PausedAt != nil,
+		PausedAt:      session.PausedAt,
+		PausedMinutes: session.PausedSeconds / 60,
+	}
+}
+
+// AutoStopSessions ends abandoned sessions at their last heartbeat, then
+// sessions that ran past their plan plus the grace period. Their owners
+// are asked about the goal on their next visit through the history's
+// auto_ended flag.
+func (s *FocusService) AutoStopSessions(ctx context.Context) error {
+	abandoned, err := s.repo.EndAbandoned(ctx, s.heartbeatTimeout)
+	if err != nil {
+		return err
+	}
+	overdue, err := s.repo.AutoEndOverdue(ctx, s.autoStopGrace)
+	if err != nil {
+		return err
+	}
+
+	for _, sessions := range [][]domain.FocusSession{abandoned, overdue} {
+		for i := range sessions {
+			s.finishSession(ctx, &sessions[i])
+		}
+	}
+
+	if len(abandoned)+len(overdue) > 0 {
+		log.Printf("⏹️ Auto-stopped %d abandoned and %d overdue focus sessions", len(abandoned), len(overdue))
+	}
+	return nil
+}
+
 // GetActiveInSquad returns all active focus sessions for a squad
 func (s *FocusService) GetActiveInSquad(ctx context.Context, userID, squadID uuid.UUID) (*domain.ActiveSessionsResponse, error) {
 	// Validate squad membership
@@ -95,18 +617,33 @@ func (s *FocusService) GetActiveInSquad(ctx context.Context, userID, squadID uui
 		return nil, ErrNotSquadMember
 	}
 
-	sessions, err := s.repo.GetActiveBySquad(ctx, squadID)
+	sessions, err := s.repo.GetActiveBySquad(ctx, squadID, s.heartbeatTimeout)
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
+	for i := range sessions {
+		if p := sessions[i].Pomodoro; p != nil {
+			focused := time.Duration(sessions[i].FocusedSeconds) * time.Second
+			sessions[i].Pomodoro = pomodoroState(p.Config, focused, now)
+		}
+	}
+
 	return &domain.ActiveSessionsResponse{
 		ActiveSessions: sessions,
 	}, nil
 }
 
-// GetFocusHistory returns completed focus sessions for a squad (last 7 days)
-func (s *FocusService) GetFocusHistory(ctx context.Context, userID, squadID uuid.UUID) (*domain.FocusHistoryResponse, error) {
+// History page sizes
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 100
+)
+
+// GetFocusHistory returns a page of a squad's ended focus sessions. A
+// member filter narrows it to one member.
+func (s *FocusService) GetFocusHistory(ctx context.Context, userID, squadID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
 	// Validate squad membership
 	isMember, err := s.repo.IsMemberOfSquad(ctx, userID, squadID)
 	if err != nil {
@@ -116,12 +653,272 @@ func (s *FocusService) GetFocusHistory(ctx context.Context, userID, squadID uuid
 		return nil, ErrNotSquadMember
 	}
 
-	history, err := s.repo.GetHistoryBySquad(ctx, squadID, 7)
+	filter.SquadID = &squadID
+	return s.listHistory(ctx, userID, filter, cursor, limit)
+}
+
+// GetMyHistory returns a page of the user's own ended focus sessions
+// across all their squads. A squad filter narrows it to one squad.
+func (s *FocusService) GetMyHistory(ctx context.Context, userID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
+	filter.UserID = &userID
+	return s.listHistory(ctx, userID, filter, cursor, limit)
+}
+
+// listHistory validates a history query and fetches one page of it.
+// Notes are private, so only the viewer's own are kept.
+func (s *FocusService) listHistory(ctx context.Context, viewerID uuid.UUID, filter domain.FocusHistoryFilter, cursor string, limit int) (*domain.FocusHistoryResponse, error) {
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit < 0 || limit > maxHistoryLimit || filter.MinMinutes < 0 {
+		return nil, ErrInvalidHistory
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidHistory
+	}
+	tags, err := normalizeTags(filter.Tags, maxSessionTags)
+	if err != nil {
+		return nil, err
+	}
+	filter.Tags = tags
+
+	var after *domain.FocusHistoryCursor
+	if cursor != "" {
+		decoded, err := decodeHistoryCursor(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = decoded
+	}
+
+	// One extra row tells whether there is a next page
+	history, err := s.repo.ListHistory(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		if history[i].UserID != viewerID {
+			history[i].Note = nil
+		}
+	}
+
+	response := &domain.FocusHistoryResponse{History: history}
+	if len(history) > limit {
+		response.History = history[:limit]
+		last := response.History[limit-1]
+		next := encodeHistoryCursor(domain.FocusHistoryCursor{StartedAt: last.StartedAt, ID: last.ID})
+		response.NextCursor = &next
+	}
+	return response, nil
+}
+
+// encodeHistoryCursor makes an opaque cursor out of a session's position.
+// Postgres keeps microseconds, so they round-trip exactly.
+func encodeHistoryCursor(c domain.FocusHistoryCursor) string {
+	raw := strconv.FormatInt(c.StartedAt.UnixMicro(), 10) + "_" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeHistoryCursor(cursor string) (*domain.FocusHistoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	micros, id, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	startedAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.FocusHistoryCursor{StartedAt: time.UnixMicro(startedAt).UTC(), ID: sessionID}, nil
+}
+
+// Periods in a stats window by default, and at most
+var (
+	defaultStatsCount = map[focusstats.Period]int{focusstats.PeriodDay: 30, focusstats.PeriodWeek: 12, focusstats.PeriodMonth: 12}
+	maxStatsCount     = map[focusstats.Period]int{focusstats.PeriodDay: 90, focusstats.PeriodWeek: 52, focusstats.PeriodMonth: 24}
+)
+
+// GetMyStats aggregates the user's ended sessions, in every squad, per day,
+// week or month of their timezone. count = 0 uses the period's default.
+func (s *FocusService) GetMyStats(ctx context.Context, userID uuid.UUID, period string, count int) (*domain.FocusStatsResponse, error) {
+	p := focusstats.Period(period)
+	if period == "" {
+		p = focusstats.PeriodWeek
+	}
+	if !p.Valid() || count < 0 || count > maxStatsCount[p] {
+		return nil, ErrInvalidStats
+	}
+	if count == 0 {
+		count = defaultStatsCount[p]
+	}
+
+	timezone, err := s.repo.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := loadLocation(timezone)
+	today := streak.DateOf(time.Now(), loc)
+
+	previousFrom, _, _ := focusstats.Window(p, today, count)
+	since := time.Date(previousFrom.Year(), previousFrom.Month(), previousFrom.Day(), 0, 0, 0, 0, loc)
+	ended, err := s.repo.GetEndedByUser(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]focusstats.Session, 0, len(ended))
+	for _, session := range ended {
+		minutes := 0
+		if session.DurationMinutes != nil {
+			minutes = *session.DurationMinutes
+		}
+		sessions = append(sessions, focusstats.Session{Start: session.StartedAt, End: *session.EndedAt, Minutes: minutes})
+	}
+
+	result := focusstats.Compute(focusstats.Input{
+		Sessions: sessions,
+		Location: loc,
+		Period:   p,
+		Count:    count,
+		Today:    today,
+	})
+	return focusStatsResponse(p, timezone, result), nil
+}
+
+// focusStatsResponse shapes computed stats for the API
+func focusStatsResponse(p focusstats.Period, timezone string, result focusstats.Result) *domain.FocusStatsResponse {
+	const dateLayout = "2006-01-02"
+
+	response := &domain.FocusStatsResponse{
+		Period:    string(p),
+		Timezone:  timezone,
+		From:      result.From.Format(dateLayout),
+		To:        result.To.AddDate(0, 0, -1).Format(dateLayout),
+		Totals:    focusTotals(result.Current),
+		Previous:  focusTotals(result.Previous),
+		Buckets:   make([]domain.FocusStatsBucket, 0, len(result.Buckets)),
+		Heatmap:   result.Heatmap,
+		BestHours: result.Heatmap.BestHours(3),
+		Trend: domain.FocusTrend{
+			MinutesChangePercent:  focusstats.Change(result.Previous.TotalMinutes, result.Current.TotalMinutes),
+			SessionsChangePercent: focusstats.Change(result.Previous.Sessions, result.Current.Sessions),
+			Direction:             "flat",
+		},
+	}
+	for _, b := range result.Buckets {
+		response.Buckets = append(response.Buckets, domain.FocusStatsBucket{
+			Start:       b.Start.Format(dateLayout),
+			FocusTotals: focusTotals(b.Totals),
+		})
+	}
+
+	switch {
+	case result.Current.TotalMinutes > result.Previous.TotalMinutes:
+		response.Trend.Direction = "up"
+	case result.Current.TotalMinutes < result.Previous.TotalMinutes:
+		response.Trend.Direction = "down"
+	}
+
+	if wd := result.Heatmap.BestWeekday(); wd >= 0 {
+		weekday := time.Weekday((wd + 1) % 7) // Heatmap weeks start on Monday
+		name := strings.ToLower(weekday.String())
+		response.BestWeekday = &name
+		if len(response.BestHours) > 0 {
+			hour := response.BestHours[0]
+			response.Insight = fmt.Sprintf("You focus best on %ss between %02d:00 and %02d:00.", weekday, hour, (hour+1)%24)
+		}
+	}
+
+	return response
+}
+
+func focusTotals(t focusstats.Totals) domain.FocusTotals {
+	return domain.FocusTotals{
+		TotalMinutes:   t.TotalMinutes,
+		Sessions:       t.Sessions,
+		AverageMinutes: t.AverageMinutes,
+		LongestMinutes: t.LongestMinutes,
+	}
+}
+
+// GetMyTags returns the user's favourite and recently used tags
+func (s *FocusService) GetMyTags(ctx context.Context, userID uuid.UUID) (*domain.FocusTagsResponse, error) {
+	favourites, err := s.repo.GetFavouriteTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	recent, err := s.repo.GetRecentTags(ctx, userID, recentTagsShown)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.FocusTagsResponse{
+		Favourites: favourites,
+		Recent:     recent,
+	}, nil
+}
+
+// SetFavouriteTags replaces the user's favourite tags
+func (s *FocusService) SetFavouriteTags(ctx context.Context, userID uuid.UUID, req *domain.SetFavouriteTagsRequest) (*domain.FocusTagsResponse, error) {
+	tags, err := normalizeTags(req.Tags, maxFavouriteTags)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.repo.SetFavouriteTags(ctx, userID, tags); err != nil {
+		return nil, err
+	}
+
+	return s.GetMyTags(ctx, userID)
+}
+
+// Subject breakdown window: the last 30 days by default, a year at most
+const (
+	defaultSubjectWindow = 30 * 24 * time.Hour
+	maxSubjectWindow     = 366 * 24 * time.Hour
+)
+
+// GetSubjectBreakdown totals the user's focus time per tag for sessions
+// that started in [from, to)
+func (s *FocusService) GetSubjectBreakdown(ctx context.Context, userID uuid.UUID, from, to *time.Time) (*domain.SubjectBreakdownResponse, error) {
+	end := time.Now()
+	if to != nil {
+		end = *to
+	}
+	start := end.Add(-defaultSubjectWindow)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) || end.Sub(start) > maxSubjectWindow {
+		return nil, ErrInvalidRange
+	}
+
+	subjects, untagged, total, err := s.repo.GetSubjectMinutes(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	// Multi-tag sessions count once per tag, so shares are of the tag sum
+	tagged := 0
+	for _, subject := range subjects {
+		tagged += subject.Minutes
+	}
+	for i := range subjects {
+		if tagged > 0 {
+			subjects[i].SharePercent = math.Round(float64(subjects[i].Minutes)/float64(tagged)*1000) / 10
+		}
+	}
 
-	return &domain.FocusHistoryResponse{
-		History: history,
+	return &domain.SubjectBreakdownResponse{
+		From:            start,
+		To:              end,
+		TotalMinutes:    total,
+		UntaggedMinutes: untagged,
+		Subjects:        subjects,
 	}, nil
 }
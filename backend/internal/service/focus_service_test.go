@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestNormalizePomodoro(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      domain.PomodoroConfig
+		want    domain.PomodoroConfig
+		wantErr error
+	}{
+		{"empty uses the classic schedule", domain.PomodoroConfig{}, domain.PomodoroConfig{WorkMinutes: 25, ShortBreakMinutes: 5, LongBreakMinutes: 15, Cycles: 4}, nil},
+		{"partial keeps given fields", domain.PomodoroConfig{WorkMinutes: 50, Cycles: 2}, domain.PomodoroConfig{WorkMinutes: 50, ShortBreakMinutes: 5, LongBreakMinutes: 15, Cycles: 2}, nil},
+		{"work too long", domain.PomodoroConfig{WorkMinutes: 181}, domain.PomodoroConfig{}, ErrInvalidPomodoro},
+		{"negative break", domain.PomodoroConfig{ShortBreakMinutes: -1}, domain.PomodoroConfig{}, ErrInvalidPomodoro},
+		{"too many cycles", domain.PomodoroConfig{Cycles: 13}, domain.PomodoroConfig{}, ErrInvalidPomodoro},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizePomodoro(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHistoryCursor(t *testing.T) {
+	want := domain.FocusHistoryCursor{
+		StartedAt: time.Date(2026, 3, 11, 9, 30, 15, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	got, err := decodeHistoryCursor(encodeHistoryCursor(want))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.StartedAt.Equal(want.StartedAt) || got.ID != want.ID {
+		t.Errorf("expected %+v, got %+v", want, *got)
+	}
+
+	for _, cursor := range []string{"not base64!", "bm9wZQ", "MTIzX25vdC1hLXV1aWQ"} {
+		if _, err := decodeHistoryCursor(cursor); err == nil {
+			t.Errorf("expected an error for cursor %q", cursor)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr error
+	}{
+		{"nil", nil, []string{}, nil},
+		{"lowercases and collapses spaces", []string{"  Linear   Algebra ", "C++"}, []string{"linear algebra", "c++"}, nil},
+		{"drops empty and repeated", []string{"Math", "", "math", " MATH "}, []string{"math"}, nil},
+		{"keeps non-latin letters", []string{"Français", "日本語"}, []string{"français", "日本語"}, nil},
+		{"too many", []string{"a", "b", "c", "d", "e", "f"}, nil, ErrInvalidTags},
+		{"too long", []string{"abcdefghijklmnopqrstuvwxyz0123456"}, nil, ErrInvalidTags},
+		{"invalid characters", []string{"math, physics"}, nil, ErrInvalidTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.in, maxSessionTags)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLogSessionWindow(t *testing.T) {
+	s := &FocusService{} // Rejected before any repository call
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+	}{
+		{"ends in the future", now.Add(-time.Hour), now.Add(time.Hour)},
+		{"older than 48 hours", now.Add(-50 * time.Hour), now.Add(-49 * time.Hour)},
+		{"under a minute", now.Add(-time.Hour), now.Add(-time.Hour + 30*time.Second)},
+		{"ends before it starts", now.Add(-time.Hour), now.Add(-2 * time.Hour)},
+		{"longer than 12 hours", now.Add(-13 * time.Hour), now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &domain.LogSessionRequest{StartedAt: tt.start, EndedAt: tt.end}
+			if _, err := s.LogSession(context.Background(), uuid.New(), req); !errors.Is(err, ErrInvalidBackfill) {
+				t.Errorf("expected ErrInvalidBackfill, got %v", err)
+			}
+		})
+	}
+}
+
+// subjectRepo returns fixed per-tag totals
+type subjectRepo struct {
+	domain.FocusRepository
+	subjects        []domain.SubjectMinutes
+	untagged, total int
+}
+
+func (r *subjectRepo) GetSubjectMinutes(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.SubjectMinutes, int, int, error) {
+	return r.subjects, r.untagged, r.total, nil
+}
+
+func TestGetSubjectBreakdown_SharesOfTaggedMinutes(t *testing.T) {
+	// A 60-minute session tagged math and physics, a 30-minute math
+	// session and 10 untagged minutes
+	repo := &subjectRepo{
+		subjects: []domain.SubjectMinutes{
+			{Tag: "math", Sessions: 2, Minutes: 90},
+			{Tag: "physics", Sessions: 1, Minutes: 60},
+		},
+		untagged: 10,
+		total:    100,
+	}
+	s := &FocusService{repo: repo}
+
+	got, err := s.GetSubjectBreakdown(context.Background(), uuid.New(), nil, nil)
+	if err != nil {
+		t.Fatalf("GetSubjectBreakdown: %v", err)
+	}
+
+	want := map[string]float64{"math": 60, "physics": 40}
+	sum := 0.0
+	for _, subject := range got.Subjects {
+		if subject.SharePercent != want[subject.Tag] {
+			t.Errorf("%s share = %v, want %v", subject.Tag, subject.SharePercent, want[subject.Tag])
+		}
+		sum += subject.SharePercent
+	}
+	if sum != 100 {
+		t.Errorf("shares add up to %v, want 100", sum)
+	}
+	if got.TotalMinutes != 100 || got.UntaggedMinutes != 10 {
+		t.Errorf("totals = %d/%d untagged, want 100/10", got.TotalMinutes, got.UntaggedMinutes)
+	}
+}
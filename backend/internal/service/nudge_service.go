@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 
 	"github.com/antigravity/backend/internal/ai"
@@ -51,20 +52,38 @@ func (s *NudgeService) StartConsumer(ctx context.Context) error {
 func (s *NudgeService) HandleRiskEvent(ctx context.Context, event domain.NudgeEvent) error {
 	log.Printf("⚠️ Risk detected for user %s: %s", event.UserName, event.RiskFactor)
 
+	// Give the AI the graded deadline when we have one
+	riskFactor := event.RiskFactor
+	if event.Threshold != "" {
+		riskFactor = fmt.Sprintf("%s (%s left before their local midnight)", event.RiskFactor, event.Threshold)
+	}
+	if target := domain.StreakMode(event.StreakMode).WeeklyTarget(); target > 0 {
+		riskFactor = fmt.Sprintf("%s; their %d-week streak needs activity today to reach %d active days this week",
+			riskFactor, event.StreakDays, target)
+	}
+
 	// 1. Generate AI Nudge
-	nudgeMsg, err := s.ai.GenerateNudge(ctx, event.UserName, event.StreakDays, event.RiskFactor)
+	nudgeMsg, err := s.ai.GenerateNudge(ctx, event.UserName, event.StreakDays, riskFactor)
 	if err != nil {
 		log.Printf("AI Error (falling back to default): %v", err)
 		nudgeMsg = "Keep your streak alive! You got this!"
 	}
 
 	// 2. Create Notification
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"risk_factor":  event.RiskFactor,
+		"threshold":    event.Threshold,
+		"streak_mode":  event.StreakMode,
+		"deadline":     event.Deadline,
+		"minutes_left": event.MinutesLeft,
+		"streak_days":  event.StreakDays,
+	})
 	notification := &domain.Notification{
 		UserID:   event.UserID,
 		Type:     "streak_alert", // or "nudge"
 		Title:    "Streak at Risk! 🔥",
 		Message:  nudgeMsg,
-		Metadata: json.RawMessage(`{"risk_factor": "` + event.RiskFactor + `"}`),
+		Metadata: metadata,
 	}
 
 	// 3. Persist
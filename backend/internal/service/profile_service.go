@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/google/uuid"
@@ -46,6 +47,13 @@ func (s *ProfileService) UpdateMyProfile(ctx context.Context, userID uuid.UUID,
 		return nil, ErrProfileNotFound
 	}
 
+	// Timezone drives streak day boundaries and deadlines, so it must be a valid IANA name
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			return nil, domain.ErrInvalidTimezone
+		}
+	}
+
 	// Perform update
 	return s.repo.Update(ctx, userID, req)
 }
@@ -2,19 +2,22 @@ package service
 
 import (
 	"context"
+	"log"
 
 	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
 	"github.com/google/uuid"
 )
 
 // SquadService handles business logic for squads
 type SquadService struct {
-	repo domain.SquadRepository
+	repo      domain.SquadRepository
+	publisher *eventbus.Publisher
 }
 
 // NewSquadService creates a new squad service
-func NewSquadService(repo domain.SquadRepository) *SquadService {
-	return &SquadService{repo: repo}
+func NewSquadService(repo domain.SquadRepository, publisher *eventbus.Publisher) *SquadService {
+	return &SquadService{repo: repo, publisher: publisher}
 }
 
 // CreateSquad creates a new squad
@@ -49,21 +52,28 @@ func (s *SquadService) GetSquadDetail(ctx context.Context, squadID, userID uuid.
 		return nil, domain.ErrSquadNotFound
 	}
 
+	// Today's progress toward the squad streak
+	detail.Streak.MembersRequired = domain.EffectiveQuorum(detail.Streak.Quorum, len(detail.Members))
+	for _, member := range detail.Members {
+		if member.ActiveToday {
+			detail.Streak.ActiveToday++
+		}
+	}
+
 	return detail, nil
 }
 
-// UpdateSquad updates squad details (owner only)
+// UpdateSquad updates squad details (owner and admins)
 func (s *SquadService) UpdateSquad(ctx context.Context, squadID, userID uuid.UUID, req *domain.UpdateSquadRequest) (*domain.Squad, error) {
-	// Check ownership
-	squad, err := s.repo.GetByID(ctx, squadID)
+	squad, role, err := s.memberRole(ctx, squadID, userID)
 	if err != nil {
 		return nil, err
 	}
-	if squad == nil {
-		return nil, domain.ErrSquadNotFound
+	if !role.CanEditSquad() {
+		return nil, domain.ErrSquadRoleForbidden
 	}
-	if squad.OwnerID != userID {
-		return nil, domain.ErrNotSquadOwner
+	if req.StreakQuorum != nil && (*req.StreakQuorum < 0 || *req.StreakQuorum > squad.MaxMembers) {
+		return nil, domain.ErrInvalidStreakQuorum
 	}
 
 	return s.repo.Update(ctx, squadID, req)
@@ -71,15 +81,11 @@ func (s *SquadService) UpdateSquad(ctx context.Context, squadID, userID uuid.UUI
 
 // DeleteSquad deletes a squad (owner only)
 func (s *SquadService) DeleteSquad(ctx context.Context, squadID, userID uuid.UUID) error {
-	// Check ownership
-	squad, err := s.repo.GetByID(ctx, squadID)
+	_, role, err := s.memberRole(ctx, squadID, userID)
 	if err != nil {
 		return err
 	}
-	if squad == nil {
-		return domain.ErrSquadNotFound
-	}
-	if squad.OwnerID != userID {
+	if !role.CanDeleteSquad() {
 		return domain.ErrNotSquadOwner
 	}
 
@@ -93,51 +99,218 @@ func (s *SquadService) JoinSquad(ctx context.Context, userID uuid.UUID, inviteCo
 		return nil, err
 	}
 
+	s.publishMemberEvent(ctx, eventbus.SquadEventMemberJoined, squadID, userID, "")
+
 	return s.repo.GetByID(ctx, squadID)
 }
 
-// RemoveMember removes a member (owner kicks or member leaves)
+// GetSquadLeaderboard returns squads ranked by their current squad streak
+func (s *SquadService) GetSquadLeaderboard(ctx context.Context, limit int) ([]domain.SquadLeaderboardEntry, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50 // Default to 50
+	}
+	return s.repo.GetStreakLeaderboard(ctx, limit)
+}
+
+// RemoveMember removes a member (owner or admin kicks, or member leaves)
 func (s *SquadService) RemoveMember(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID) error {
-	// Get squad to check ownership
-	squad, err := s.repo.GetByID(ctx, squadID)
+	_, callerRole, err := s.memberRole(ctx, squadID, callerUserID)
 	if err != nil {
 		return err
 	}
-	if squad == nil {
-		return domain.ErrSquadNotFound
-	}
 
-	// Check permissions
-	isSelfLeave := targetUserID == callerUserID
-	isOwner := squad.OwnerID == callerUserID
-	targetIsOwner := squad.OwnerID == targetUserID
+	// Anyone can leave; kicking depends on both roles
+	if targetUserID == callerUserID {
+		return s.leaveSquad(ctx, squadID, callerUserID)
+	}
 
-	// Owner cannot be kicked
-	if targetIsOwner && !isSelfLeave {
+	targetRole, err := s.repo.GetMemberRole(ctx, squadID, targetUserID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case targetRole == "":
+		return domain.ErrNotSquadMember
+	case targetRole == domain.SquadRoleOwner:
 		return domain.ErrCannotKickOwner
+	case !callerRole.CanKick(targetRole):
+		return domain.ErrSquadRoleForbidden
 	}
 
-	// Must be owner or self
-	if !isOwner && !isSelfLeave {
-		return domain.ErrNotSquadOwner
+	if err := s.repo.RemoveMember(ctx, squadID, targetUserID); err != nil {
+		return err
+	}
+
+	s.publishMemberEvent(ctx, eventbus.SquadEventMemberLeft, squadID, targetUserID, "removed")
+
+	return nil
+}
+
+// leaveSquad removes a member who leaves. A leaving owner hands the squad
+// to the next in line, and the last one out archives it.
+func (s *SquadService) leaveSquad(ctx context.Context, squadID, userID uuid.UUID) error {
+	departure, err := s.repo.LeaveSquad(ctx, squadID, userID)
+	if err != nil {
+		return err
+	}
+
+	s.publishMemberEvent(ctx, eventbus.SquadEventMemberLeft, squadID, userID, "left")
+	switch {
+	case departure.Archived:
+		s.publishMemberEvent(ctx, eventbus.SquadEventArchived, squadID, userID, "")
+	case departure.NewOwnerID != nil:
+		s.announceOwner(ctx, squadID, userID, *departure.NewOwnerID, "owner_left")
+	}
+
+	return nil
+}
+
+// TransferOwnership hands the squad to another member (owner only). The
+// previous owner stays on as an admin.
+func (s *SquadService) TransferOwnership(ctx context.Context, squadID, callerUserID, newOwnerID uuid.UUID) (*domain.Squad, error) {
+	_, role, err := s.memberRole(ctx, squadID, callerUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanTransferOwnership() {
+		return nil, domain.ErrNotSquadOwner
+	}
+	if newOwnerID == callerUserID {
+		return nil, domain.ErrAlreadySquadOwner
+	}
+
+	targetRole, err := s.repo.GetMemberRole(ctx, squadID, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if targetRole == "" {
+		return nil, domain.ErrNotSquadMember
+	}
+
+	if err := s.repo.TransferOwnership(ctx, squadID, callerUserID, newOwnerID); err != nil {
+		return nil, err
+	}
+
+	s.announceOwner(ctx, squadID, callerUserID, newOwnerID, "transferred")
+
+	return s.repo.GetByID(ctx, squadID)
+}
+
+// announceOwner tells the squad's stream about its new owner and asks for
+// every member to be notified
+func (s *SquadService) announceOwner(ctx context.Context, squadID, previousOwnerID, ownerID uuid.UUID, reason string) {
+	s.publishMemberEvent(ctx, eventbus.SquadEventOwnerChanged, squadID, ownerID, reason)
+	if s.publisher == nil {
+		return
 	}
 
-	return s.repo.RemoveMember(ctx, squadID, targetUserID)
+	detail, err := s.repo.GetDetailByID(ctx, squadID)
+	if err != nil || detail == nil {
+		log.Printf("Failed to load squad %s for owner notifications: %v", squadID, err)
+		return
+	}
+
+	var ownerName string
+	recipients := make([]uuid.UUID, 0, len(detail.Members))
+	for _, member := range detail.Members {
+		recipients = append(recipients, member.UserID)
+		if member.UserID == ownerID {
+			ownerName = member.DisplayName
+		}
+	}
+
+	event := eventbus.NewSquadOwnerChangedEvent(squadID, detail.Name, ownerID, ownerName, previousOwnerID, reason, recipients)
+	if err := s.publisher.PublishSquadOwnerChanged(ctx, event); err != nil {
+		log.Printf("Failed to publish squad owner change: %v", err)
+	}
 }
 
-// RegenerateInviteCode generates a new invite code (owner only)
+// publishMemberEvent tells a squad's stream that its membership changed
+func (s *SquadService) publishMemberEvent(ctx context.Context, kind string, squadID, userID uuid.UUID, reason string) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := eventbus.NewSquadEvent(kind, squadID, userID)
+	event.Reason = reason
+	if err := s.publisher.PublishSquadEvent(ctx, event); err != nil {
+		log.Printf("Failed to publish squad event: %v", err)
+	}
+}
+
+// RegenerateInviteCode generates a new invite code (owner and admins)
 func (s *SquadService) RegenerateInviteCode(ctx context.Context, squadID, userID uuid.UUID) (string, error) {
-	// Check ownership
-	squad, err := s.repo.GetByID(ctx, squadID)
+	_, role, err := s.memberRole(ctx, squadID, userID)
 	if err != nil {
 		return "", err
 	}
-	if squad == nil {
-		return "", domain.ErrSquadNotFound
-	}
-	if squad.OwnerID != userID {
-		return "", domain.ErrNotSquadOwner
+	if !role.CanRegenerateCode() {
+		return "", domain.ErrSquadRoleForbidden
 	}
 
 	return s.repo.RegenerateInviteCode(ctx, squadID)
 }
+
+// UpdateMemberRole promotes a member to admin or demotes an admin to
+// member (owner only). The owner's own role can't be changed here.
+func (s *SquadService) UpdateMemberRole(ctx context.Context, squadID, targetUserID, callerUserID uuid.UUID, role domain.SquadRole) error {
+	if role != domain.SquadRoleAdmin && role != domain.SquadRoleMember {
+		return domain.ErrInvalidSquadRole
+	}
+
+	_, callerRole, err := s.memberRole(ctx, squadID, callerUserID)
+	if err != nil {
+		return err
+	}
+	if !callerRole.CanManageRoles() {
+		return domain.ErrNotSquadOwner
+	}
+
+	targetRole, err := s.repo.GetMemberRole(ctx, squadID, targetUserID)
+	if err != nil {
+		return err
+	}
+	switch targetRole {
+	case "":
+		return domain.ErrNotSquadMember
+	case domain.SquadRoleOwner:
+		return domain.ErrCannotChangeOwnerRole
+	case role:
+		return nil
+	}
+
+	if err := s.repo.SetMemberRole(ctx, squadID, targetUserID, role); err != nil {
+		return err
+	}
+
+	if s.publisher != nil {
+		event := eventbus.NewSquadEvent(eventbus.SquadEventMemberRole, squadID, targetUserID)
+		event.Role = string(role)
+		if err := s.publisher.PublishSquadEvent(ctx, event); err != nil {
+			log.Printf("Failed to publish squad event: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// memberRole loads a squad and the user's role in it, failing if the user
+// is not a member
+func (s *SquadService) memberRole(ctx context.Context, squadID, userID uuid.UUID) (*domain.Squad, domain.SquadRole, error) {
+	squad, err := s.repo.GetByID(ctx, squadID)
+	if err != nil {
+		return nil, "", err
+	}
+	if squad == nil {
+		return nil, "", domain.ErrSquadNotFound
+	}
+
+	role, err := s.repo.GetMemberRole(ctx, squadID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if role == "" {
+		return nil, "", domain.ErrNotSquadMember
+	}
+	return squad, role, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// stubSquadRepo serves one squad with fixed member roles
+type stubSquadRepo struct {
+	domain.SquadRepository
+	roles   map[uuid.UUID]domain.SquadRole
+	removed []uuid.UUID
+}
+
+func (r *stubSquadRepo) GetByID(ctx context.Context, squadID uuid.UUID) (*domain.Squad, error) {
+	return &domain.Squad{ID: squadID, MaxMembers: 8}, nil
+}
+
+func (r *stubSquadRepo) GetMemberRole(ctx context.Context, squadID, userID uuid.UUID) (domain.SquadRole, error) {
+	return r.roles[userID], nil
+}
+
+func (r *stubSquadRepo) SetMemberRole(ctx context.Context, squadID, userID uuid.UUID, role domain.SquadRole) error {
+	r.roles[userID] = role
+	return nil
+}
+
+func (r *stubSquadRepo) RemoveMember(ctx context.Context, squadID, userID uuid.UUID) error {
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+func (r *stubSquadRepo) LeaveSquad(ctx context.Context, squadID, userID uuid.UUID) (*domain.SquadDeparture, error) {
+	r.removed = append(r.removed, userID)
+	delete(r.roles, userID)
+	return &domain.SquadDeparture{PreviousOwnerID: userID}, nil
+}
+
+func (r *stubSquadRepo) TransferOwnership(ctx context.Context, squadID, fromUserID, toUserID uuid.UUID) error {
+	r.roles[fromUserID] = domain.SquadRoleAdmin
+	r.roles[toUserID] = domain.SquadRoleOwner
+	return nil
+}
+
+func TestSquadService_RemoveMember(t *testing.T) {
+	owner, admin, member, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		caller, target uuid.UUID
+		wantErr        error
+	}{
+		{"owner kicks admin", owner, admin, nil},
+		{"admin kicks member", admin, member, nil},
+		{"admin cannot kick admin", admin, other, domain.ErrSquadRoleForbidden},
+		{"member cannot kick", member, admin, domain.ErrSquadRoleForbidden},
+		{"owner cannot be kicked", admin, owner, domain.ErrCannotKickOwner},
+		{"member leaves", member, member, nil},
+		{"outsider cannot kick", uuid.New(), member, domain.ErrNotSquadMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubSquadRepo{roles: map[uuid.UUID]domain.SquadRole{
+				owner:  domain.SquadRoleOwner,
+				admin:  domain.SquadRoleAdmin,
+				other:  domain.SquadRoleAdmin,
+				member: domain.SquadRoleMember,
+			}}
+			s := NewSquadService(repo, nil)
+
+			err := s.RemoveMember(context.Background(), uuid.New(), tt.target, tt.caller)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if removed := len(repo.removed) == 1; removed != (tt.wantErr == nil) {
+				t.Errorf("expected removal %v, got %v", tt.wantErr == nil, repo.removed)
+			}
+		})
+	}
+}
+
+func TestSquadService_UpdateMemberRole(t *testing.T) {
+	owner, admin, member := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		caller, target uuid.UUID
+		role           domain.SquadRole
+		wantErr        error
+		wantRole       domain.SquadRole
+	}{
+		{"owner promotes member", owner, member, domain.SquadRoleAdmin, nil, domain.SquadRoleAdmin},
+		{"owner demotes admin", owner, admin, domain.SquadRoleMember, nil, domain.SquadRoleMember},
+		{"admin cannot promote", admin, member, domain.SquadRoleAdmin, domain.ErrNotSquadOwner, domain.SquadRoleMember},
+		{"owner role is fixed", owner, owner, domain.SquadRoleMember, domain.ErrCannotChangeOwnerRole, domain.SquadRoleOwner},
+		{"ownership is not granted here", owner, admin, domain.SquadRoleOwner, domain.ErrInvalidSquadRole, domain.SquadRoleAdmin},
+		{"target must be a member", owner, uuid.New(), domain.SquadRoleAdmin, domain.ErrNotSquadMember, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubSquadRepo{roles: map[uuid.UUID]domain.SquadRole{
+				owner:  domain.SquadRoleOwner,
+				admin:  domain.SquadRoleAdmin,
+				member: domain.SquadRoleMember,
+			}}
+			s := NewSquadService(repo, nil)
+
+			err := s.UpdateMemberRole(context.Background(), uuid.New(), tt.target, tt.caller, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := repo.roles[tt.target]; got != tt.wantRole {
+				t.Errorf("expected role %q, got %q", tt.wantRole, got)
+			}
+		})
+	}
+}
+
+func TestSquadService_TransferOwnership(t *testing.T) {
+	owner, admin, member := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		caller, target uuid.UUID
+		wantErr        error
+	}{
+		{"owner hands over to member", owner, member, nil},
+		{"admin cannot hand over", admin, member, domain.ErrNotSquadOwner},
+		{"owner to self", owner, owner, domain.ErrAlreadySquadOwner},
+		{"target must be a member", owner, uuid.New(), domain.ErrNotSquadMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubSquadRepo{roles: map[uuid.UUID]domain.SquadRole{
+				owner:  domain.SquadRoleOwner,
+				admin:  domain.SquadRoleAdmin,
+				member: domain.SquadRoleMember,
+			}}
+			s := NewSquadService(repo, nil)
+
+			_, err := s.TransferOwnership(context.Background(), uuid.New(), tt.caller, tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if repo.roles[tt.target] != domain.SquadRoleOwner || repo.roles[tt.caller] != domain.SquadRoleAdmin {
+				t.Errorf("expected %s to own the squad and %s to be an admin, got %v", tt.target, tt.caller, repo.roles)
+			}
+		})
+	}
+}
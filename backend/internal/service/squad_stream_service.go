@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/google/uuid"
+)
+
+var ErrStreamUnavailable = errors.New("real-time stream is unavailable")
+
+// squadStreamBuffer is how many events may queue for a slow client
+const squadStreamBuffer = 32
+
+// SquadStreamService streams a squad's real-time events to its members, and
+// each user's personal events to them
+type SquadStreamService struct {
+	bus       *eventbus.EventBus
+	squadRepo domain.SquadRepository
+}
+
+// NewSquadStreamService creates a new squad stream service. bus may be nil
+// when NATS is not configured, in which case streams are unavailable.
+func NewSquadStreamService(bus *eventbus.EventBus, squadRepo domain.SquadRepository) *SquadStreamService {
+	return &SquadStreamService{bus: bus, squadRepo: squadRepo}
+}
+
+// Subscribe streams the squad's events until ctx is cancelled. lastEventID
+// is the ID of the last event the client saw ("" = new events only).
+func (s *SquadStreamService) Subscribe(ctx context.Context, userID, squadID uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+	if s.bus == nil {
+		return nil, ErrStreamUnavailable
+	}
+
+	isMember, err := s.squadRepo.IsMember(ctx, squadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotSquadMember
+	}
+
+	// The stream ends once the subscriber leaves or is removed
+	return s.follow(ctx, eventbus.SquadSubjects(squadID), lastEventID, func(event eventbus.BaseEvent) bool {
+		return event.Type == eventbus.SquadEventMemberLeft && event.UserID == userID
+	})
+}
+
+// SubscribeUser streams the user's personal events (e.g. streak updates)
+// until ctx is cancelled. lastEventID works as in Subscribe.
+func (s *SquadStreamService) SubscribeUser(ctx context.Context, userID uuid.UUID, lastEventID string) (<-chan domain.SquadStreamEvent, error) {
+	if s.bus == nil {
+		return nil, ErrStreamUnavailable
+	}
+	return s.follow(ctx, eventbus.UserSubjects(userID), lastEventID, func(eventbus.BaseEvent) bool { return false })
+}
+
+// follow forwards the events on subjects from after lastEventID; final
+// marks the event after which the stream closes
+func (s *SquadStreamService) follow(ctx context.Context, subjects, lastEventID string, final func(eventbus.BaseEvent) bool) (<-chan domain.SquadStreamEvent, error) {
+	// An unparsable ID resumes nothing rather than failing the reconnect
+	afterSeq, _ := strconv.ParseUint(lastEventID, 10, 64)
+
+	events := make(chan domain.SquadStreamEvent, squadStreamBuffer)
+	stop, err := s.bus.Follow(ctx, "ANTIGRAVITY", subjects, afterSeq, func(seq uint64, data []byte) {
+		var event eventbus.BaseEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Printf("Failed to parse stream event: %v", err)
+			return
+		}
+
+		select {
+		case events <- domain.SquadStreamEvent{
+			ID:    strconv.FormatUint(seq, 10),
+			Type:  event.Type,
+			Data:  data,
+			Final: final(event),
+		}:
+		case <-ctx.Done():
+		}
+	})
+	if err != nil {
+		log.Printf("Failed to follow %s: %v", subjects, err)
+		return nil, ErrStreamUnavailable
+	}
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return events, nil
+}
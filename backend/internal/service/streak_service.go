@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/antigravity/backend/internal/domain"
 	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/streak"
 	"github.com/google/uuid"
 )
 
@@ -18,94 +20,803 @@ var (
 
 // StreakService handles business logic for streak management
 type StreakService struct {
-	repo      domain.StreakRepository
-	publisher *eventbus.Publisher
+	repo       domain.StreakRepository
+	publisher  *eventbus.Publisher
+	milestones []int // Streak lengths worth celebrating
+	now        func() time.Time
 }
 
-// NewStreakService creates a new streak service
-func NewStreakService(repo domain.StreakRepository, publisher *eventbus.Publisher) *StreakService {
-	return &StreakService{repo: repo, publisher: publisher}
+// NewStreakService creates a new streak service. Empty milestones fall
+// back to streak.DefaultMilestones.
+func NewStreakService(repo domain.StreakRepository, publisher *eventbus.Publisher, milestones []int) *StreakService {
+	if len(milestones) == 0 {
+		milestones = streak.DefaultMilestones
+	}
+	return &StreakService{repo: repo, publisher: publisher, milestones: milestones, now: time.Now}
 }
 
+// Streak view and recalculation settings
+const (
+	historyDays       = 30  // Days of history in the user's own view
+	freezeLedgerLimit = 20  // Ledger entries in the user's own view
+	achievementsLimit = 10  // Achievements in the user's own view
+	recalcPageSize    = 500 // Users fetched per recalculation batch
+	recalcWorkers     = 8   // Users evaluated in parallel per batch
+)
+
 // LogManualCheckin logs a manual check-in activity
 func (s *StreakService) LogManualCheckin(ctx context.Context, userID string) (*domain.LogActivityResponse, error) {
-	return s.repo.LogActivity(ctx, userID, domain.ActivityTypeManualCheckin, nil)
+	return s.logActivity(ctx, userID, domain.ActivityTypeManualCheckin, nil, nil)
 }
 
-// LogFocusSession logs a focus session activity (called by focus service)
-func (s *StreakService) LogFocusSession(ctx context.Context, userID string, sessionID string, duration int) (*domain.LogActivityResponse, error) {
+// LogFocusSession logs a focus session activity (called by focus service).
+// Backfilled sessions pass the local date they ended on; live ones nil.
+func (s *StreakService) LogFocusSession(ctx context.Context, userID string, sessionID string, duration int, activityDate *time.Time) (*domain.LogActivityResponse, error) {
 	metadata := map[string]interface{}{
 		"session_id": sessionID,
 		"duration":   duration,
 	}
-	return s.repo.LogActivity(ctx, userID, domain.ActivityTypeFocusSession, metadata)
+	return s.logActivity(ctx, userID, domain.ActivityTypeFocusSession, metadata, activityDate)
+}
+
+// logActivity records the day, then recomputes and stores the user's streak.
+// Completing a 7-day block earns a freeze token.
+func (s *StreakService) logActivity(ctx context.Context, userID string, activityType domain.ActivityType, metadata map[string]interface{}, activityDate *time.Time) (*domain.LogActivityResponse, error) {
+	response, err := s.repo.LogActivity(ctx, userID, activityType, metadata, activityDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// The activity is recorded; a failed recalculation is repaired by the nightly job
+	inputs, err := s.repo.GetStreakInputs(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load streak inputs for %s: %v", userID, err)
+		return response, nil
+	}
+
+	result := streak.Calculate(engineInput(inputs, s.now()))
+	response.CurrentStreak = result.CurrentStreak
+
+	if err := s.repo.SaveStreaks(ctx, []domain.StreakUpdate{streakUpdate(inputs, result)}); err != nil {
+		log.Printf("Failed to save streak for %s: %v", userID, err)
+	}
+	s.refreshSquadStreaks(ctx, userID)
+
+	// A redelivered focus session was already announced
+	if !response.Duplicate {
+		response.Achievements = s.recordAchievements(ctx, inputs, result)
+		s.publishStreakUpdated(ctx, userID, activityType, response, inputs.StreakMode)
+	}
+
+	// Tokens are earned per 7 streak days, so only daily streaks earn them
+	if response.IsNew && inputs.StreakMode.WeeklyTarget() == 0 && streak.EarnsFreezeToken(result.CurrentStreak) {
+		note := fmt.Sprintf("Reached %d-day streak", result.CurrentStreak)
+		if _, err := s.repo.EarnFreezeToken(ctx, userID, response.ActivityDate, note); err != nil {
+			log.Printf("Failed to award freeze token to %s: %v", userID, err)
+		}
+	}
+
+	return response, nil
+}
+
+// recordAchievements stores the milestones and personal record reached by
+// this activity and publishes one event for each that is new for the streak
+func (s *StreakService) recordAchievements(ctx context.Context, inputs *domain.StreakInputs, result streak.Result) []domain.StreakAchievement {
+	var recorded []domain.StreakAchievement
+	for _, achievement := range detectAchievements(inputs, result, s.milestones) {
+		isNew, err := s.repo.RecordAchievement(ctx, inputs.UserID, &achievement)
+		if err != nil {
+			log.Printf("Failed to record achievement for %s: %v", inputs.UserID, err)
+			continue
+		}
+		if !isNew {
+			continue // Already celebrated for this streak
+		}
+		recorded = append(recorded, achievement)
+		s.publishMilestone(ctx, inputs, achievement)
+	}
+	return recorded
+}
+
+// detectAchievements compares the stored streak with the new result.
+// A milestone is crossed when the streak grows past it; a personal record
+// when the current streak is now the longest ever and beats a stored one.
+func detectAchievements(inputs *domain.StreakInputs, result streak.Result, milestones []int) []domain.StreakAchievement {
+	if result.StreakStart == nil {
+		return nil
+	}
+
+	base := domain.StreakAchievement{
+		StreakMode:      inputs.StreakMode,
+		StreakUnit:      inputs.StreakMode.Unit(),
+		StreakStartedOn: *result.StreakStart,
+	}
+
+	var achievements []domain.StreakAchievement
+	for _, m := range streak.MilestonesCrossed(inputs.CurrentStreak, result.CurrentStreak, milestones) {
+		a := base
+		a.Kind = domain.AchievementMilestone
+		a.Value = m
+		achievements = append(achievements, a)
+	}
+
+	if inputs.LongestStreak > 0 && result.CurrentStreak > inputs.LongestStreak && result.CurrentStreak >= result.LongestStreak {
+		a := base
+		a.Kind = domain.AchievementPersonalRecord
+		a.Value = result.CurrentStreak
+		a.PreviousRecord = inputs.LongestStreak
+		achievements = append(achievements, a)
+	}
+
+	return achievements
+}
+
+// publishMilestone announces an achievement to the user and their squadmates
+func (s *StreakService) publishMilestone(ctx context.Context, inputs *domain.StreakInputs, achievement domain.StreakAchievement) {
+	if s.publisher == nil {
+		return
+	}
+	userID, err := uuid.Parse(inputs.UserID)
+	if err != nil {
+		return
+	}
+
+	teammates, err := s.repo.GetSquadmateIDs(ctx, inputs.UserID)
+	if err != nil {
+		log.Printf("Failed to load squadmates for %s: %v", inputs.UserID, err)
+	}
+
+	event := eventbus.NewStreakMilestoneEvent(userID, inputs.DisplayName, string(achievement.Kind), achievement.Value)
+	event.PreviousRecord = achievement.PreviousRecord
+	event.StreakMode = string(achievement.StreakMode)
+	event.Teammates = teammates
+	if err := s.publisher.PublishStreakMilestone(ctx, event); err != nil {
+		log.Printf("Failed to publish milestone for %s: %v", inputs.UserID, err)
+	}
+}
+
+// publishStreakUpdated pushes the new streak, so clients see activity
+// credited in the background (e.g. focus sessions) without polling
+func (s *StreakService) publishStreakUpdated(ctx context.Context, userID string, activityType domain.ActivityType, response *domain.LogActivityResponse, mode domain.StreakMode) {
+	if s.publisher == nil {
+		return
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return
+	}
+
+	event := eventbus.NewStreakUpdatedEvent(uid, string(activityType), response.CurrentStreak)
+	event.ActivityDate = response.ActivityDate.Format("2006-01-02")
+	event.FirstToday = response.IsNew
+	event.StreakMode = string(mode)
+	if err := s.publisher.PublishStreakUpdated(ctx, event); err != nil {
+		log.Printf("Failed to publish streak update for %s: %v", userID, err)
+	}
 }
 
 // GetMyStreak returns the authenticated user's streak data with history
 func (s *StreakService) GetMyStreak(ctx context.Context, userID string) (*domain.StreakData, error) {
-	// Include 30 days of history for the user's own view
-	return s.repo.GetUserStreak(ctx, userID, true, 30)
+	return s.getStreak(ctx, userID, true)
 }
 
 // GetUserPublicStreak returns another user's public streak data (no history)
 func (s *StreakService) GetUserPublicStreak(ctx context.Context, userID string) (*domain.StreakData, error) {
-	// Public view: no history included
-	return s.repo.GetUserStreak(ctx, userID, false, 0)
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, domain.ErrProfileNotFound
+	}
+	return s.getStreak(ctx, userID, false)
+}
+
+func (s *StreakService) getStreak(ctx context.Context, userID string, includeHistory bool) (*domain.StreakData, error) {
+	inputs, err := s.repo.GetStreakInputs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	in := engineInput(inputs, s.now())
+	result := streak.Calculate(in)
+
+	data := &domain.StreakData{
+		UserID:           userID,
+		StreakMode:       inputs.StreakMode,
+		StreakUnit:       inputs.StreakMode.Unit(),
+		WeeklyTarget:     in.WeeklyTarget,
+		CurrentStreak:    result.CurrentStreak,
+		LongestStreak:    result.LongestStreak,
+		LastActiveDate:   result.LastActiveDate,
+		ConsistencyScore: result.ConsistencyScore,
+		TotalActiveDays:  result.TotalActiveDays,
+		FreezeTokens:     inputs.FreezeTokens,
+		FrozenDays:       len(inputs.FrozenDates),
+	}
+	if in.WeeklyTarget > 0 {
+		data.WeekActiveDays = &result.WeekActiveDays
+	}
+
+	if includeHistory {
+		history := streak.History(in, historyDays)
+
+		// Per-day aggregates are a nice-to-have; the statuses stand on their own
+		aggregates := map[time.Time]domain.DailyActivity{}
+		daily, err := s.repo.GetDailyActivity(ctx, userID, history[len(history)-1].Date)
+		if err != nil {
+			log.Printf("Failed to load daily activity for %s: %v", userID, err)
+		}
+		for _, day := range daily {
+			aggregates[day.Date] = day
+		}
+
+		for _, day := range history {
+			agg := aggregates[day.Date]
+			data.StreakHistory = append(data.StreakHistory, domain.ActivityDay{
+				Date:          day.Date,
+				Active:        day.Status == streak.StatusActive,
+				Frozen:        day.Status == streak.StatusFrozen,
+				Status:        domain.DayStatus(day.Status),
+				ActivityCount: agg.ActivityCount,
+				FocusMinutes:  agg.FocusMinutes,
+				ActivityTypes: agg.ActivityTypes,
+			})
+		}
+
+		ledger, err := s.repo.GetFreezeLedger(ctx, userID, freezeLedgerLimit)
+		if err == nil {
+			data.FreezeLedger = ledger
+		}
+
+		achievements, err := s.repo.GetAchievements(ctx, userID, achievementsLimit)
+		if err == nil {
+			data.Achievements = achievements
+		}
+	}
+
+	return data, nil
 }
 
-// GetLeaderboard returns the global streak leaderboard
-func (s *StreakService) GetLeaderboard(ctx context.Context, limit int) ([]domain.LeaderboardEntry, error) {
+// GetLeaderboard returns the global streak leaderboard for a mode family:
+// "daily" (default) ranks day streaks, "weekly" ranks week streaks
+func (s *StreakService) GetLeaderboard(ctx context.Context, limit int, mode string) ([]domain.LeaderboardEntry, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 50 // Default to 50
 	}
-	return s.repo.GetLeaderboard(ctx, limit)
+
+	var weekly bool
+	switch mode {
+	case "", string(domain.StreakModeDaily):
+	case "weekly":
+		weekly = true
+	default:
+		return nil, domain.ErrInvalidStreakMode
+	}
+
+	return s.repo.GetLeaderboard(ctx, limit, weekly)
 }
 
-// TriggerRecalculation triggers streak recalculation for all users
-// This should only be called by admin or cron jobs
+// SetStreakMode switches the user between daily and weekly:N streaks.
+// History is untouched; the streak is recomputed in the new mode right away
+// so the profile and leaderboard reflect it.
+func (s *StreakService) SetStreakMode(ctx context.Context, userID string, req *domain.SetStreakModeRequest) (*domain.StreakData, error) {
+	mode, err := domain.ParseStreakMode(req.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetStreakMode(ctx, userID, mode); err != nil {
+		return nil, err
+	}
+
+	inputs, err := s.repo.GetStreakInputs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := streak.Calculate(engineInput(inputs, s.now()))
+	if err := s.repo.SaveStreaks(ctx, []domain.StreakUpdate{streakUpdate(inputs, result)}); err != nil {
+		return nil, err
+	}
+
+	return s.getStreak(ctx, userID, true)
+}
+
+// TriggerRecalculation runs RecalculateStreaks as a background job
 func (s *StreakService) TriggerRecalculation(ctx context.Context) error {
-	return s.repo.RecalculateAllStreaks(ctx)
+	_, err := s.RecalculateStreaks(ctx)
+	return err
+}
+
+// RecalculateStreaks recomputes every user's streak in batches.
+// Each batch spends freeze tokens where they can save a streak, evaluates
+// users in parallel and writes the results back in one statement.
+// Streaks that can no longer be saved publish StreakBrokenEvent.
+// This should only be called by admin or cron jobs.
+func (s *StreakService) RecalculateStreaks(ctx context.Context) (*domain.RecalculationReport, error) {
+	now := s.now()
+	report := &domain.RecalculationReport{StartedAt: now}
+	afterID := uuid.Nil.String()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := s.repo.ListStreakInputs(ctx, afterID, recalcPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		updates := s.recalculatePage(ctx, page, now)
+		if err := s.repo.SaveStreaks(ctx, updates); err != nil {
+			return nil, err
+		}
+		s.reportPage(ctx, page, updates, report)
+
+		afterID = page[len(page)-1].UserID
+		if len(page) < recalcPageSize {
+			break
+		}
+	}
+
+	squads, err := s.recalculateSquads(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	report.SquadsProcessed = squads
+	report.DurationMs = time.Since(now).Milliseconds()
+
+	log.Printf("Recalculated streaks for %d users (%d broken, %d new records) and %d squads",
+		report.UsersProcessed, report.StreaksBroken, report.NewRecords, report.SquadsProcessed)
+	return report, nil
+}
+
+// reportPage compares each user's stored streak with the recomputed one.
+// A drop to zero is a broken streak (recalculateUser only drops streaks
+// that can't be saved); a longest streak above the stored one is a new
+// personal record.
+func (s *StreakService) reportPage(ctx context.Context, page []domain.StreakInputs, updates []domain.StreakUpdate, report *domain.RecalculationReport) {
+	for i := range page {
+		before, after := &page[i], updates[i]
+		report.UsersProcessed++
+
+		if after.LongestStreak > before.LongestStreak {
+			report.NewRecords++
+		}
+
+		if before.CurrentStreak > 0 && after.CurrentStreak == 0 {
+			report.StreaksBroken++
+			if err := s.PublishStreakBrokenEvent(ctx, before, after.LongestStreak); err != nil {
+				log.Printf("Failed to publish streak broken event for %s: %v", before.UserID, err)
+			}
+		}
+	}
+}
+
+// recalculateSquads recomputes every squad streak in batches
+func (s *StreakService) recalculateSquads(ctx context.Context, now time.Time) (int, error) {
+	afterID := uuid.Nil
+	squads := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return squads, err
+		}
+
+		page, err := s.repo.ListSquadStreakInputs(ctx, afterID, recalcPageSize)
+		if err != nil {
+			return squads, err
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		updates := make([]domain.SquadStreakUpdate, len(page))
+		for i := range page {
+			updates[i] = squadStreakUpdate(&page[i], now)
+		}
+		if err := s.repo.SaveSquadStreaks(ctx, updates); err != nil {
+			return squads, err
+		}
+
+		squads += len(page)
+		afterID = page[len(page)-1].SquadID
+		if len(page) < recalcPageSize {
+			break
+		}
+	}
+
+	return squads, nil
+}
+
+// refreshSquadStreaks recomputes the streaks of every squad the user is in
+func (s *StreakService) refreshSquadStreaks(ctx context.Context, userID string) {
+	squadIDs, err := s.repo.GetUserSquadIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load squads for %s: %v", userID, err)
+		return
+	}
+
+	now := s.now()
+	updates := make([]domain.SquadStreakUpdate, 0, len(squadIDs))
+	for _, squadID := range squadIDs {
+		inputs, err := s.repo.GetSquadStreakInputs(ctx, squadID)
+		if err != nil {
+			log.Printf("Failed to load squad streak inputs for %s: %v", squadID, err)
+			continue
+		}
+		updates = append(updates, squadStreakUpdate(inputs, now))
+	}
+
+	if err := s.repo.SaveSquadStreaks(ctx, updates); err != nil {
+		log.Printf("Failed to save squad streaks for %s: %v", userID, err)
+	}
+}
+
+// squadStreakUpdate evaluates a squad streak. Members log days in their own
+// timezones, so the squad's "today" is the earliest local day among members:
+// the last day that is still open for everyone.
+func squadStreakUpdate(inputs *domain.SquadStreakInputs, now time.Time) domain.SquadStreakUpdate {
+	members := make([]streak.Member, len(inputs.Members))
+	today := streak.DateOf(now, time.UTC)
+	for i, m := range inputs.Members {
+		loc := loadLocation(m.Timezone)
+		members[i] = streak.Member{
+			JoinedOn:    streak.DateOf(m.JoinedAt, loc),
+			ActiveDates: m.ActiveDates,
+		}
+		if local := streak.DateOf(now, loc); i == 0 || local.Before(today) {
+			today = local
+		}
+	}
+
+	quorum := 0
+	if inputs.Quorum != nil {
+		quorum = *inputs.Quorum
+	}
+
+	result := streak.Calculate(streak.Input{
+		ActiveDates: streak.SquadDays(members, quorum, today),
+		Location:    time.UTC,
+		Now:         today,
+		Grace:       streak.DefaultGracePolicy,
+	})
+
+	return domain.SquadStreakUpdate{
+		SquadID:        inputs.SquadID,
+		CurrentStreak:  result.CurrentStreak,
+		LongestStreak:  result.LongestStreak,
+		LastStreakDate: result.LastActiveDate,
+	}
+}
+
+// recalculatePage evaluates a batch of users with a fixed worker pool
+func (s *StreakService) recalculatePage(ctx context.Context, page []domain.StreakInputs, now time.Time) []domain.StreakUpdate {
+	updates := make([]domain.StreakUpdate, len(page))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < recalcWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				updates[i] = s.recalculateUser(ctx, &page[i], now)
+			}
+		}()
+	}
+
+	for i := range page {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return updates
+}
+
+// recalculateUser spends freeze tokens on missed days if they keep the
+// streak alive, then evaluates the user. A streak that reads 0 but that
+// activity today would still continue keeps its stored length, so it only
+// breaks (and publishes StreakBrokenEvent) once it can't be saved.
+func (s *StreakService) recalculateUser(ctx context.Context, inputs *domain.StreakInputs, now time.Time) domain.StreakUpdate {
+	in := engineInput(inputs, now)
+	tokens := inputs.FreezeTokens
+
+	if inputs.CurrentStreak > 0 {
+		if dates := streak.PlanFreezes(in, tokens); len(dates) > 0 {
+			used, err := s.repo.UseFreezeTokens(ctx, inputs.UserID, dates)
+			if err != nil {
+				log.Printf("Failed to apply freeze tokens for %s: %v", inputs.UserID, err)
+			} else if used > 0 {
+				in.FrozenDates = append(in.FrozenDates, dates...)
+				tokens -= used
+				log.Printf("🧊 Applied %d streak freeze tokens for %s", used, inputs.UserID)
+			}
+		}
+	}
+
+	update := streakUpdate(inputs, streak.Calculate(in))
+	if update.CurrentStreak == 0 && inputs.CurrentStreak > 0 && streak.Rescuable(in, tokens) {
+		update.CurrentStreak = inputs.CurrentStreak
+	}
+	return update
+}
+
+// engineInput maps stored inputs to the streak engine input
+func engineInput(inputs *domain.StreakInputs, now time.Time) streak.Input {
+	loc := loadLocation(inputs.Timezone)
+
+	return streak.Input{
+		ActiveDates:  inputs.ActiveDates,
+		FrozenDates:  inputs.FrozenDates,
+		Location:     loc,
+		Now:          now,
+		JoinedAt:     inputs.JoinedAt,
+		Grace:        streak.DefaultGracePolicy,
+		FocusMinutes: inputs.FocusMinutes,
+		Effort:       streak.DefaultEffortPolicy,
+		WeeklyTarget: inputs.StreakMode.WeeklyTarget(),
+	}
+}
+
+// loadLocation resolves a stored timezone, falling back to UTC
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func streakUpdate(inputs *domain.StreakInputs, result streak.Result) domain.StreakUpdate {
+	return domain.StreakUpdate{
+		UserID:           inputs.UserID,
+		StreakMode:       inputs.StreakMode,
+		CurrentStreak:    result.CurrentStreak,
+		LongestStreak:    result.LongestStreak,
+		ConsistencyScore: result.ConsistencyScore,
+	}
+}
+
+// GrantFreezeTokens gives a user freeze tokens (admin only, capped at MaxFreezeTokens)
+func (s *StreakService) GrantFreezeTokens(ctx context.Context, userID string, req *domain.GrantFreezeRequest) (*domain.GrantFreezeResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, domain.ErrProfileNotFound
+	}
+	if req.Count < 1 || req.Count > domain.MaxFreezeTokens {
+		return nil, domain.ErrInvalidFreezeCount
+	}
+
+	balance, err := s.repo.GrantFreezeTokens(ctx, userID, req.Count, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.GrantFreezeResponse{
+		UserID:       userID,
+		FreezeTokens: balance,
+	}, nil
 }
 
-// PublishStreakAtRiskEvents checks for at-risk users and publishes events
-// This is called by the cron job
+// DefaultRiskThresholds are the graded streak warnings, tightest first.
+// Each fires at most once per user per local day.
+var DefaultRiskThresholds = []domain.RiskThreshold{
+	{Name: "2h", Before: 2 * time.Hour},
+	{Name: "6h", Before: 6 * time.Hour},
+}
+
+// PublishStreakAtRiskEvents checks for at-risk users and publishes events.
+// Risk is measured against each user's local midnight, so the job must run
+// frequently (e.g. every 15 minutes) to catch every timezone.
+// This is called by the scheduler.
 func (s *StreakService) PublishStreakAtRiskEvents(ctx context.Context) error {
 	if s.publisher == nil {
 		log.Println("Warning: Publisher is nil, skipping streak risk events")
 		return nil
 	}
 
-	// Get users at risk (no activity in last 20 hours with active streaks)
-	atRiskUsers, err := s.repo.GetAtRiskUsers(ctx)
+	// Get users inactive today whose deadline is within the widest window
+	atRiskUsers, err := s.repo.GetAtRiskUsers(ctx, widestThreshold(DefaultRiskThresholds))
 	if err != nil {
 		return err
 	}
 
 	log.Printf("Found %d users with at-risk streaks", len(atRiskUsers))
 
+	now := s.now()
 	for _, user := range atRiskUsers {
+		loc, err := time.LoadLocation(user.Timezone)
+		if err != nil {
+			log.Printf("Invalid timezone %q for %s, using UTC", user.Timezone, user.UserID)
+			loc = time.UTC
+		}
+
+		threshold, deadline, ok := evaluateRisk(now, loc, DefaultRiskThresholds)
+		if !ok {
+			continue
+		}
+
+		// A weekly streak is only at risk on the last day the target is still reachable
+		riskFactor := "approaching_deadline"
+		if target := user.StreakMode.WeeklyTarget(); target > 0 {
+			if !weeklyTargetDueToday(now.In(loc), target, user.WeekActiveDays) {
+				continue
+			}
+			riskFactor = "weekly_target"
+		}
+
+		// Claim the alert slot first so replicas and reruns never double-send
+		localDate := now.In(loc)
+		claimed, err := s.repo.RecordRiskAlert(ctx, user.UserID, localDate, threshold.Name)
+		if err != nil {
+			log.Printf("Failed to record risk alert for %s: %v", user.UserID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
 		userID, _ := uuid.Parse(user.UserID)
 		event := eventbus.NewStreakRiskEvent(
 			userID,
 			user.DisplayName,
 			user.CurrentStreak,
 			user.LastActivityDate,
-			"inactive_20h",
+			riskFactor,
 		)
+		event.Threshold = threshold.Name
+		event.StreakMode = string(user.StreakMode)
+		event.Timezone = loc.String()
+		event.LocalDate = localDate.Format("2006-01-02")
+		event.Deadline = deadline
+		event.MinutesLeft = int(deadline.Sub(now).Minutes())
 		if err := s.publisher.PublishStreakRisk(ctx, event); err != nil {
 			log.Printf("Failed to publish risk event for %s: %v", user.UserID, err)
+			// Free the slot so the next run retries
+			if err := s.repo.ReleaseRiskAlert(ctx, user.UserID, localDate, threshold.Name); err != nil {
+				log.Printf("Failed to release risk alert for %s: %v", user.UserID, err)
+			}
 		}
 	}
 
 	return nil
 }
 
-// PublishStreakBrokenEvent publishes when a streak breaks (called by recalculation)
-func (s *StreakService) PublishStreakBrokenEvent(ctx context.Context, userID uuid.UUID, userName string, brokenStreak, longestStreak int) error {
+// PublishSquadStreakRiskEvents finds squads that don't yet have enough
+// members active today and publishes an event for each lagging member whose
+// local midnight is approaching, so teammates can nudge them.
+// This is called by the scheduler.
+func (s *StreakService) PublishSquadStreakRiskEvents(ctx context.Context) error {
 	if s.publisher == nil {
+		log.Println("Warning: Publisher is nil, skipping squad streak risk events")
 		return nil
 	}
-	event := eventbus.NewStreakBrokenEvent(userID, userName, brokenStreak, longestStreak)
+
+	squads, err := s.repo.ListActiveSquadStreaks(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Found %d squads with at-risk streaks", len(squads))
+
+	now := s.now()
+	for _, squad := range squads {
+		active := 0
+		for _, m := range squad.Members {
+			if m.ActiveToday {
+				active++
+			}
+		}
+		needed := domain.EffectiveQuorum(squad.Quorum, len(squad.Members)) - active
+		if needed <= 0 {
+			continue
+		}
+
+		for _, member := range squad.Members {
+			if member.ActiveToday {
+				continue
+			}
+
+			loc := loadLocation(member.Timezone)
+			threshold, deadline, ok := evaluateRisk(now, loc, DefaultRiskThresholds)
+			if !ok {
+				continue
+			}
+
+			localDate := now.In(loc)
+			claimed, err := s.repo.RecordSquadRiskAlert(ctx, squad.SquadID, member.UserID, localDate, threshold.Name)
+			if err != nil {
+				log.Printf("Failed to record squad risk alert for %s in %s: %v", member.UserID, squad.SquadID, err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			teammates := make([]uuid.UUID, 0, len(squad.Members)-1)
+			for _, m := range squad.Members {
+				if m.UserID != member.UserID {
+					teammates = append(teammates, m.UserID)
+				}
+			}
+
+			event := eventbus.NewSquadStreakRiskEvent(
+				squad.SquadID,
+				squad.Name,
+				squad.CurrentStreak,
+				member.UserID,
+				member.DisplayName,
+				teammates,
+				needed,
+			)
+			event.Threshold = threshold.Name
+			event.Deadline = deadline
+			event.MinutesLeft = int(deadline.Sub(now).Minutes())
+			if err := s.publisher.PublishSquadStreakRisk(ctx, event); err != nil {
+				log.Printf("Failed to publish squad risk event for %s: %v", squad.SquadID, err)
+				// Free the slot so the next run retries
+				if err := s.repo.ReleaseSquadRiskAlert(ctx, squad.SquadID, member.UserID, localDate, threshold.Name); err != nil {
+					log.Printf("Failed to release squad risk alert for %s in %s: %v", member.UserID, squad.SquadID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// evaluateRisk returns the tightest threshold that the user's local
+// midnight falls within, along with that deadline
+func evaluateRisk(now time.Time, loc *time.Location, thresholds []domain.RiskThreshold) (domain.RiskThreshold, time.Time, bool) {
+	local := now.In(loc)
+	year, month, day := local.Date()
+	deadline := time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	left := deadline.Sub(now)
+
+	var match domain.RiskThreshold
+	found := false
+	for _, t := range thresholds {
+		if left <= t.Before && (!found || t.Before < match.Before) {
+			match = t
+			found = true
+		}
+	}
+
+	return match, deadline, found
+}
+
+// weeklyTargetDueToday reports whether missing today makes this ISO week's
+// target unreachable: the days still needed equal the days left (incl. today).
+// Weeks that are already met, or already lost, are not at risk.
+func weeklyTargetDueToday(local time.Time, target, activeThisWeek int) bool {
+	needed := target - activeThisWeek
+	daysLeft := 7 - (int(local.Weekday())+6)%7
+	return needed > 0 && needed == daysLeft
+}
+
+func widestThreshold(thresholds []domain.RiskThreshold) time.Duration {
+	var widest time.Duration
+	for _, t := range thresholds {
+		if t.Before > widest {
+			widest = t.Before
+		}
+	}
+	return widest
+}
+
+// PublishStreakBrokenEvent publishes when a streak breaks (called by recalculation).
+// inputs holds the streak as stored before it broke.
+func (s *StreakService) PublishStreakBrokenEvent(ctx context.Context, inputs *domain.StreakInputs, longestStreak int) error {
+	if s.publisher == nil {
+		return nil
+	}
+	userID, err := uuid.Parse(inputs.UserID)
+	if err != nil {
+		return err
+	}
+	event := eventbus.NewStreakBrokenEvent(userID, inputs.DisplayName, inputs.CurrentStreak, longestStreak)
+	event.StreakMode = string(inputs.StreakMode)
 	return s.publisher.PublishStreakBroken(ctx, event)
 }
 
@@ -116,11 +827,3 @@ func (s *StreakService) ValidateActivityType(activityType domain.ActivityType) e
 	}
 	return nil
 }
-
-// AtRiskUser represents a user whose streak is at risk
-type AtRiskUser struct {
-	UserID           string
-	DisplayName      string
-	CurrentStreak    int
-	LastActivityDate time.Time
-}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/antigravity/backend/internal/streak"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+func TestEvaluateRisk(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	// 2024-03-10 16:30 UTC = 22:00 Kolkata, 17:30 Berlin, 12:30 New York (DST day)
+	now := time.Date(2024, 3, 10, 16, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		loc           *time.Location
+		wantThreshold string
+		wantOK        bool
+	}{
+		{"Kolkata 2h before midnight", now, kolkata, "2h", true},
+		{"Berlin 6.5h before midnight", now, berlin, "", false},
+		{"Berlin 6h before midnight", now.Add(30 * time.Minute), berlin, "6h", true},
+		{"New York midday", now, newYork, "", false},
+		{"New York 5h before midnight", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), newYork, "6h", true},
+		{"UTC 1 minute before midnight", time.Date(2024, 3, 10, 23, 59, 0, 0, time.UTC), time.UTC, "2h", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, deadline, ok := evaluateRisk(tt.now, tt.loc, DefaultRiskThresholds)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if threshold.Name != tt.wantThreshold {
+				t.Errorf("expected threshold %q, got %q", tt.wantThreshold, threshold.Name)
+			}
+
+			local := deadline.In(tt.loc)
+			if local.Hour() != 0 || local.Minute() != 0 || !deadline.After(tt.now) {
+				t.Errorf("expected deadline at next local midnight, got %v", local)
+			}
+		})
+	}
+}
+
+func TestWeeklyTargetDueToday(t *testing.T) {
+	// 2024-06-13 is a Thursday: 4 days left in the ISO week, including today
+	thursday := time.Date(2024, 6, 13, 21, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 6, 16, 21, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		local  time.Time
+		target int
+		active int
+		want   bool
+	}{
+		{"plenty of days left", thursday, 3, 1, false},
+		{"every remaining day needed", thursday, 5, 1, true},
+		{"target already met", thursday, 3, 3, false},
+		{"target already out of reach", thursday, 7, 1, false},
+		{"last day of week, one short", sunday, 3, 2, true},
+		{"last day of week, met", sunday, 3, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weeklyTargetDueToday(tt.local, tt.target, tt.active); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSquadStreakUpdate(t *testing.T) {
+	// 2024-06-15 20:00 UTC is already 2024-06-16 in Kolkata
+	now := time.Date(2024, 6, 15, 20, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	joined := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	two := 2
+
+	inputs := &domain.SquadStreakInputs{
+		Members: []domain.SquadMemberActivity{
+			{Timezone: "Asia/Kolkata", JoinedAt: joined, ActiveDates: []time.Time{day(14), day(15), day(16)}},
+			{Timezone: "America/New_York", JoinedAt: joined, ActiveDates: []time.Time{day(14), day(15)}},
+			{Timezone: "UTC", JoinedAt: joined, ActiveDates: []time.Time{day(14)}},
+		},
+	}
+
+	if got := squadStreakUpdate(inputs, now); got.CurrentStreak != 1 || got.LongestStreak != 1 {
+		t.Errorf("everyone: expected 1/1, got %d/%d", got.CurrentStreak, got.LongestStreak)
+	}
+
+	inputs.Quorum = &two
+	got := squadStreakUpdate(inputs, now)
+	if got.CurrentStreak != 2 {
+		t.Errorf("quorum of two: expected 2, got %d", got.CurrentStreak)
+	}
+	if got.LastStreakDate == nil || !got.LastStreakDate.Equal(day(15)) {
+		t.Errorf("quorum of two: expected last streak date %v, got %v", day(15), got.LastStreakDate)
+	}
+}
+
+func TestReportPage(t *testing.T) {
+	page := []domain.StreakInputs{
+		{UserID: "broken", CurrentStreak: 5, LongestStreak: 10},
+		{UserID: "record", CurrentStreak: 10, LongestStreak: 10},
+		{UserID: "steady", CurrentStreak: 3, LongestStreak: 8},
+		{UserID: "idle", CurrentStreak: 0, LongestStreak: 2},
+	}
+	updates := []domain.StreakUpdate{
+		{UserID: "broken", CurrentStreak: 0, LongestStreak: 10},
+		{UserID: "record", CurrentStreak: 11, LongestStreak: 11},
+		{UserID: "steady", CurrentStreak: 4, LongestStreak: 8},
+		{UserID: "idle", CurrentStreak: 0, LongestStreak: 2},
+	}
+
+	report := &domain.RecalculationReport{}
+	s := &StreakService{}
+	s.reportPage(context.Background(), page, updates, report)
+
+	if report.UsersProcessed != 4 || report.StreaksBroken != 1 || report.NewRecords != 1 {
+		t.Errorf("expected 4 processed, 1 broken, 1 record; got %+v", report)
+	}
+}
+
+func TestRecalculation_BreaksOnlyUnsavableStreaks(t *testing.T) {
+	now := time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC) // Nightly run
+	today := streak.DateOf(now, time.UTC)
+	daysAgo := func(offsets ...int) []time.Time {
+		dates := make([]time.Time, 0, len(offsets))
+		for _, o := range offsets {
+			dates = append(dates, today.AddDate(0, 0, -o))
+		}
+		return dates
+	}
+
+	page := []domain.StreakInputs{
+		// Logging today still bridges yesterday under the grace rule
+		{UserID: "savable", Timezone: "UTC", StreakMode: domain.StreakModeDaily, CurrentStreak: 3, LongestStreak: 3, ActiveDates: daysAgo(2, 3, 4)},
+		{UserID: "broken", Timezone: "UTC", StreakMode: domain.StreakModeDaily, CurrentStreak: 3, LongestStreak: 3, ActiveDates: daysAgo(3, 4, 5)},
+	}
+
+	s := NewStreakService(nil, nil, nil)
+	updates := s.recalculatePage(context.Background(), page, now)
+	report := &domain.RecalculationReport{}
+	s.reportPage(context.Background(), page, updates, report)
+
+	if updates[0].CurrentStreak != 3 {
+		t.Errorf("expected the savable streak to stay at 3, got %d", updates[0].CurrentStreak)
+	}
+	if updates[1].CurrentStreak != 0 {
+		t.Errorf("expected the unsavable streak to break, got %d", updates[1].CurrentStreak)
+	}
+	if report.StreaksBroken != 1 {
+		t.Errorf("expected 1 broken streak, got %d", report.StreaksBroken)
+	}
+}
+
+func TestDetectAchievements(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	milestones := []int{7, 30, 100}
+
+	tests := []struct {
+		name      string
+		stored    domain.StreakInputs
+		result    streak.Result
+		wantKinds []domain.AchievementKind
+	}{
+		{
+			name:      "crosses a milestone",
+			stored:    domain.StreakInputs{CurrentStreak: 6, LongestStreak: 20},
+			result:    streak.Result{CurrentStreak: 7, LongestStreak: 20, StreakStart: &start},
+			wantKinds: []domain.AchievementKind{domain.AchievementMilestone},
+		},
+		{
+			name:   "same day, no new milestone",
+			stored: domain.StreakInputs{CurrentStreak: 7, LongestStreak: 20},
+			result: streak.Result{CurrentStreak: 7, LongestStreak: 20, StreakStart: &start},
+		},
+		{
+			name:      "beats the longest streak",
+			stored:    domain.StreakInputs{CurrentStreak: 12, LongestStreak: 12},
+			result:    streak.Result{CurrentStreak: 13, LongestStreak: 13, StreakStart: &start},
+			wantKinds: []domain.AchievementKind{domain.AchievementPersonalRecord},
+		},
+		{
+			name:      "milestone and record together",
+			stored:    domain.StreakInputs{CurrentStreak: 29, LongestStreak: 29},
+			result:    streak.Result{CurrentStreak: 30, LongestStreak: 30, StreakStart: &start},
+			wantKinds: []domain.AchievementKind{domain.AchievementMilestone, domain.AchievementPersonalRecord},
+		},
+		{
+			name:   "first streak is not a record",
+			stored: domain.StreakInputs{CurrentStreak: 0, LongestStreak: 0},
+			result: streak.Result{CurrentStreak: 1, LongestStreak: 1, StreakStart: &start},
+		},
+		{
+			name:   "older run is still longer",
+			stored: domain.StreakInputs{CurrentStreak: 4, LongestStreak: 4},
+			result: streak.Result{CurrentStreak: 5, LongestStreak: 9, StreakStart: &start},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stored.StreakMode = domain.StreakModeDaily
+			got := detectAchievements(&tt.stored, tt.result, milestones)
+			if len(got) != len(tt.wantKinds) {
+				t.Fatalf("expected %v, got %+v", tt.wantKinds, got)
+			}
+			for i, a := range got {
+				if a.Kind != tt.wantKinds[i] {
+					t.Errorf("achievement %d: expected %s, got %s", i, tt.wantKinds[i], a.Kind)
+				}
+				if !a.StreakStartedOn.Equal(start) || a.StreakUnit != domain.StreakUnitDays {
+					t.Errorf("achievement %d: unexpected streak key %+v", i, a)
+				}
+			}
+		})
+	}
+}
+
+// failingPublisher returns a publisher whose every publish fails: its NATS
+// server has no JetStream, so no stream acknowledges the message
+func failingPublisher(t *testing.T) *eventbus.Publisher {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("nats server: %v", err)
+	}
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	bus, err := eventbus.NewEventBus(ns.ClientURL())
+	if err != nil {
+		t.Fatalf("event bus: %v", err)
+	}
+	t.Cleanup(bus.Close)
+	return eventbus.NewPublisher(bus)
+}
+
+// riskAlertRepo has one at-risk user and tracks claimed alert slots
+type riskAlertRepo struct {
+	domain.StreakRepository
+	user     domain.AtRiskUser
+	claimed  map[string]bool
+	released int
+}
+
+func (r *riskAlertRepo) GetAtRiskUsers(ctx context.Context, within time.Duration) ([]domain.AtRiskUser, error) {
+	return []domain.AtRiskUser{r.user}, nil
+}
+
+func (r *riskAlertRepo) RecordRiskAlert(ctx context.Context, userID string, localDate time.Time, threshold string) (bool, error) {
+	key := userID + localDate.Format("2006-01-02") + threshold
+	if r.claimed[key] {
+		return false, nil
+	}
+	r.claimed[key] = true
+	return true, nil
+}
+
+func (r *riskAlertRepo) ReleaseRiskAlert(ctx context.Context, userID string, localDate time.Time, threshold string) error {
+	delete(r.claimed, userID+localDate.Format("2006-01-02")+threshold)
+	r.released++
+	return nil
+}
+
+func TestPublishStreakAtRiskEvents_RetriesFailedPublish(t *testing.T) {
+	repo := &riskAlertRepo{
+		user:    domain.AtRiskUser{UserID: uuid.NewString(), CurrentStreak: 6, Timezone: "UTC", StreakMode: domain.StreakModeDaily},
+		claimed: map[string]bool{},
+	}
+	s := NewStreakService(repo, failingPublisher(t), nil)
+	s.now = func() time.Time { return time.Date(2026, 3, 10, 21, 30, 0, 0, time.UTC) } // 6h threshold
+
+	for run := 1; run <= 2; run++ {
+		if err := s.PublishStreakAtRiskEvents(context.Background()); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		if repo.released != run {
+			t.Errorf("run %d: expected the failed alert to be released and claimed again, got %d releases", run, repo.released)
+		}
+	}
+	if len(repo.claimed) != 0 {
+		t.Errorf("expected no alert slot left claimed, got %v", repo.claimed)
+	}
+}
+
+// squadRiskAlertRepo has one squad with a lagging member and tracks claimed
+// alert slots
+type squadRiskAlertRepo struct {
+	domain.StreakRepository
+	squad    domain.SquadStreakInputs
+	claimed  map[string]bool
+	released int
+}
+
+func (r *squadRiskAlertRepo) ListActiveSquadStreaks(ctx context.Context) ([]domain.SquadStreakInputs, error) {
+	return []domain.SquadStreakInputs{r.squad}, nil
+}
+
+func (r *squadRiskAlertRepo) RecordSquadRiskAlert(ctx context.Context, squadID, userID uuid.UUID, localDate time.Time, threshold string) (bool, error) {
+	key := squadID.String() + userID.String() + localDate.Format("2006-01-02") + threshold
+	if r.claimed[key] {
+		return false, nil
+	}
+	r.claimed[key] = true
+	return true, nil
+}
+
+func (r *squadRiskAlertRepo) ReleaseSquadRiskAlert(ctx context.Context, squadID, userID uuid.UUID, localDate time.Time, threshold string) error {
+	delete(r.claimed, squadID.String()+userID.String()+localDate.Format("2006-01-02")+threshold)
+	r.released++
+	return nil
+}
+
+func TestPublishSquadStreakRiskEvents_RetriesFailedPublish(t *testing.T) {
+	repo := &squadRiskAlertRepo{
+		squad: domain.SquadStreakInputs{
+			SquadID:       uuid.New(),
+			Name:          "Night Owls",
+			CurrentStreak: 4,
+			Members: []domain.SquadMemberActivity{
+				{UserID: uuid.New(), Timezone: "UTC", ActiveToday: true},
+				{UserID: uuid.New(), Timezone: "UTC"},
+			},
+		},
+		claimed: map[string]bool{},
+	}
+	s := NewStreakService(repo, failingPublisher(t), nil)
+	s.now = func() time.Time { return time.Date(2026, 3, 10, 21, 30, 0, 0, time.UTC) }
+
+	if err := s.PublishSquadStreakRiskEvents(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if repo.released != 1 || len(repo.claimed) != 0 {
+		t.Errorf("expected the failed alert to be released, got %d releases and %v claimed", repo.released, repo.claimed)
+	}
+}
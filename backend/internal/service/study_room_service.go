@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antigravity/backend/internal/domain"
+	"github.com/antigravity/backend/internal/eventbus"
+	"github.com/google/uuid"
+)
+
+// Study room bounds
+const (
+	maxStudyRoomTopic    = 120 // Characters
+	minStudyRoomDuration = 5   // Minutes
+	maxStudyRoomDuration = maxPlannedMinutes
+	maxStudyRoomLead     = 30 * 24 * time.Hour // How far ahead a room may be scheduled
+	studyRoomEarlyJoin   = 5 * time.Minute     // How long before the start members may join
+)
+
+// StudyRoomService handles business logic for scheduled study rooms
+type StudyRoomService struct {
+	repo         domain.StudyRoomRepository
+	squadRepo    domain.SquadRepository
+	focus        domain.FocusService
+	publisher    *eventbus.Publisher
+	reminderLead time.Duration // How long before the start reminders go out
+}
+
+// NewStudyRoomService creates a new study room service
+func NewStudyRoomService(repo domain.StudyRoomRepository, squadRepo domain.SquadRepository, focus domain.FocusService, publisher *eventbus.Publisher, reminderLead time.Duration) *StudyRoomService {
+	return &StudyRoomService{
+		repo:         repo,
+		squadRepo:    squadRepo,
+		focus:        focus,
+		publisher:    publisher,
+		reminderLead: reminderLead,
+	}
+}
+
+// CreateRoom schedules a study room in a squad. The host is RSVP'd as going.
+func (s *StudyRoomService) CreateRoom(ctx context.Context, userID, squadID uuid.UUID, req *domain.CreateStudyRoomRequest) (*domain.StudyRoom, error) {
+	normalized := *req
+	normalized.Topic = strings.TrimSpace(req.Topic)
+	if err := validateStudyRoom(&normalized, time.Now()); err != nil {
+		return nil, err
+	}
+
+	role, err := s.squadRepo.GetMemberRole(ctx, squadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, domain.ErrNotSquadMember
+	}
+	if !role.CanSchedule() {
+		return nil, domain.ErrSquadRoleForbidden
+	}
+
+	roomID, err := s.repo.Create(ctx, squadID, userID, &normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.getRoom(ctx, userID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishSquadEvent(ctx, eventbus.SquadEventStudyRoomScheduled, room, userID)
+
+	return room, nil
+}
+
+// validateStudyRoom checks a create request with a trimmed topic
+func validateStudyRoom(req *domain.CreateStudyRoomRequest, now time.Time) error {
+	topicLength := utf8.RuneCountInString(req.Topic)
+	if topicLength < 1 || topicLength > maxStudyRoomTopic {
+		return domain.ErrInvalidStudyRoom
+	}
+	if !req.StartsAt.After(now) || req.StartsAt.After(now.Add(maxStudyRoomLead)) {
+		return domain.ErrInvalidStudyRoom
+	}
+	if req.DurationMinutes < minStudyRoomDuration || req.DurationMinutes > maxStudyRoomDuration {
+		return domain.ErrInvalidStudyRoom
+	}
+	return nil
+}
+
+// ListRooms returns a squad's upcoming and live study rooms
+func (s *StudyRoomService) ListRooms(ctx context.Context, userID, squadID uuid.UUID) ([]domain.StudyRoom, error) {
+	if err := s.checkMember(ctx, squadID, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rooms, err := s.repo.ListBySquad(ctx, squadID, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rooms {
+		rooms[i].Status = studyRoomStatus(&rooms[i], now)
+	}
+	return rooms, nil
+}
+
+// GetRoom returns a study room with its members' RSVPs and attendance
+func (s *StudyRoomService) GetRoom(ctx context.Context, userID, roomID uuid.UUID) (*domain.StudyRoomDetail, error) {
+	room, err := s.getRoom(ctx, userID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetMembers(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.StudyRoomDetail{StudyRoom: *room, Members: members}, nil
+}
+
+// RSVP records the user's answer to a study room that has not ended
+func (s *StudyRoomService) RSVP(ctx context.Context, userID, roomID uuid.UUID, status string) (*domain.StudyRoom, error) {
+	switch status {
+	case domain.RSVPGoing, domain.RSVPMaybe, domain.RSVPDeclined:
+	default:
+		return nil, domain.ErrInvalidRSVP
+	}
+
+	room, err := s.getRoom(ctx, userID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStudyRoomOpen(room); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetRSVP(ctx, roomID, userID, status); err != nil {
+		return nil, err
+	}
+
+	return s.getRoom(ctx, userID, roomID)
+}
+
+// JoinRoom starts a focus session linked to the room, planned to end with
+// it; the session is stored together with the user's attendance. Members
+// may join from shortly before the start until the room ends; any active
+// session is ended first.
+func (s *StudyRoomService) JoinRoom(ctx context.Context, userID, roomID uuid.UUID) (*domain.JoinStudyRoomResponse, error) {
+	room, err := s.getRoom(ctx, userID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStudyRoomOpen(room); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Before(room.StartsAt.Add(-studyRoomEarlyJoin)) {
+		return nil, domain.ErrStudyRoomNotOpen
+	}
+
+	planned := int((room.EndsAt.Sub(now) + time.Minute - 1) / time.Minute)
+	if planned > maxPlannedMinutes {
+		planned = maxPlannedMinutes
+	}
+
+	session, err := s.focus.StartFocus(ctx, userID, &domain.StartFocusRequest{
+		SquadID:        &room.SquadID,
+		PlannedMinutes: planned,
+		Goal:           room.Topic,
+		StudyRoomID:    &room.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.JoinStudyRoomResponse{RoomID: roomID, Session: session}, nil
+}
+
+// CancelRoom cancels a study room that has not started. The host can, and
+// so can the squad's owner and admins.
+func (s *StudyRoomService) CancelRoom(ctx context.Context, userID, roomID uuid.UUID) error {
+	room, err := s.getRoom(ctx, userID, roomID)
+	if err != nil {
+		return err
+	}
+	if room.HostID != userID {
+		role, err := s.squadRepo.GetMemberRole(ctx, room.SquadID, userID)
+		if err != nil {
+			return err
+		}
+		if !role.CanCancelRooms() {
+			return domain.ErrNotStudyRoomHost
+		}
+	}
+	switch room.Status {
+	case domain.StudyRoomCancelled:
+		return domain.ErrStudyRoomCancelled
+	case domain.StudyRoomLive, domain.StudyRoomEnded:
+		return domain.ErrStudyRoomStarted
+	}
+
+	if err := s.repo.Cancel(ctx, roomID); err != nil {
+		return err
+	}
+
+	s.publishSquadEvent(ctx, eventbus.SquadEventStudyRoomCancelled, room, userID)
+	return nil
+}
+
+// SendReminders publishes a reminder for every study room about to start
+// to the members who are going or maybe coming. Each room is reminded once;
+// reminders that fail to publish are retried on the next run.
+func (s *StudyRoomService) SendReminders(ctx context.Context) error {
+	if s.publisher == nil {
+		log.Println("Warning: Publisher is nil, skipping study room reminders")
+		return nil
+	}
+
+	reminders, err := s.repo.ClaimDueReminders(ctx, s.reminderLead)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, r := range reminders {
+		if len(r.Recipients) == 0 {
+			continue
+		}
+		event := eventbus.NewStudyRoomReminderEvent(r.RoomID, r.SquadID, r.SquadName, r.Topic, r.StartsAt, r.DurationMinutes, r.Recipients)
+		if err := s.publisher.PublishStudyRoomReminder(ctx, event); err != nil {
+			log.Printf("Failed to publish reminder for study room %s: %v", r.RoomID, err)
+			if err := s.repo.ReleaseReminder(ctx, r.RoomID); err != nil {
+				log.Printf("Failed to release reminder for study room %s: %v", r.RoomID, err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	if len(reminders) > 0 {
+		log.Printf("⏰ Sent reminders for %d of %d upcoming study rooms", sent, len(reminders))
+	}
+	return nil
+}
+
+// getRoom loads a study room the user can see, with its status
+func (s *StudyRoomService) getRoom(ctx context.Context, userID, roomID uuid.UUID) (*domain.StudyRoom, error) {
+	room, err := s.repo.GetByID(ctx, roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, domain.ErrStudyRoomNotFound
+	}
+	if err := s.checkMember(ctx, room.SquadID, userID); err != nil {
+		return nil, err
+	}
+
+	room.Status = studyRoomStatus(room, time.Now())
+	return room, nil
+}
+
+func (s *StudyRoomService) checkMember(ctx context.Context, squadID, userID uuid.UUID) error {
+	isMember, err := s.squadRepo.IsMember(ctx, squadID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return domain.ErrNotSquadMember
+	}
+	return nil
+}
+
+// studyRoomStatus derives where a room stands at now
+func studyRoomStatus(room *domain.StudyRoom, now time.Time) domain.StudyRoomStatus {
+	switch {
+	case room.CancelledAt != nil:
+		return domain.StudyRoomCancelled
+	case now.Before(room.StartsAt):
+		return domain.StudyRoomScheduled
+	case now.Before(room.EndsAt):
+		return domain.StudyRoomLive
+	default:
+		return domain.StudyRoomEnded
+	}
+}
+
+// checkStudyRoomOpen rejects rooms that were cancelled or have ended
+func checkStudyRoomOpen(room *domain.StudyRoom) error {
+	switch room.Status {
+	case domain.StudyRoomCancelled:
+		return domain.ErrStudyRoomCancelled
+	case domain.StudyRoomEnded:
+		return domain.ErrStudyRoomEnded
+	}
+	return nil
+}
+
+func (s *StudyRoomService) publishSquadEvent(ctx context.Context, kind string, room *domain.StudyRoom, userID uuid.UUID) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := eventbus.NewSquadEvent(kind, room.SquadID, userID)
+	event.StudyRoomID = room.ID.String()
+	event.Title = room.Topic
+	if err := s.publisher.PublishSquadEvent(ctx, event); err != nil {
+		log.Printf("Failed to publish squad event: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antigravity/backend/internal/domain"
+)
+
+func TestValidateStudyRoom(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     domain.CreateStudyRoomRequest
+		wantErr error
+	}{
+		{"valid", domain.CreateStudyRoomRequest{Topic: "Calculus", StartsAt: now.Add(time.Hour), DurationMinutes: 50}, nil},
+		{"empty topic", domain.CreateStudyRoomRequest{StartsAt: now.Add(time.Hour), DurationMinutes: 50}, domain.ErrInvalidStudyRoom},
+		{"topic too long", domain.CreateStudyRoomRequest{Topic: strings.Repeat("a", 121), StartsAt: now.Add(time.Hour), DurationMinutes: 50}, domain.ErrInvalidStudyRoom},
+		{"starts in the past", domain.CreateStudyRoomRequest{Topic: "Calculus", StartsAt: now.Add(-time.Minute), DurationMinutes: 50}, domain.ErrInvalidStudyRoom},
+		{"starts too far ahead", domain.CreateStudyRoomRequest{Topic: "Calculus", StartsAt: now.Add(31 * 24 * time.Hour), DurationMinutes: 50}, domain.ErrInvalidStudyRoom},
+		{"too short", domain.CreateStudyRoomRequest{Topic: "Calculus", StartsAt: now.Add(time.Hour), DurationMinutes: 4}, domain.ErrInvalidStudyRoom},
+		{"too long", domain.CreateStudyRoomRequest{Topic: "Calculus", StartsAt: now.Add(time.Hour), DurationMinutes: 721}, domain.ErrInvalidStudyRoom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateStudyRoom(&tt.req, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStudyRoomStatus(t *testing.T) {
+	startsAt := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	room := &domain.StudyRoom{StartsAt: startsAt, EndsAt: startsAt.Add(50 * time.Minute)}
+	cancelled := &domain.StudyRoom{StartsAt: room.StartsAt, EndsAt: room.EndsAt, CancelledAt: &startsAt}
+
+	tests := []struct {
+		name string
+		room *domain.StudyRoom
+		now  time.Time
+		want domain.StudyRoomStatus
+	}{
+		{"before start", room, startsAt.Add(-time.Minute), domain.StudyRoomScheduled},
+		{"at start", room, startsAt, domain.StudyRoomLive},
+		{"at end", room, room.EndsAt, domain.StudyRoomEnded},
+		{"cancelled", cancelled, startsAt.Add(-time.Hour), domain.StudyRoomCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := studyRoomStatus(tt.room, tt.now); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// claimRepo fails the test if a reminder is claimed
+type claimRepo struct {
+	domain.StudyRoomRepository
+	t *testing.T
+}
+
+func (r claimRepo) ClaimDueReminders(ctx context.Context, lead time.Duration) ([]domain.StudyRoomReminder, error) {
+	r.t.Error("expected no reminders to be claimed")
+	return nil, nil
+}
+
+func TestSendReminders_LeavesRemindersUnclaimedWithoutPublisher(t *testing.T) {
+	s := NewStudyRoomService(claimRepo{t: t}, nil, nil, nil, 15*time.Minute)
+
+	if err := s.SendReminders(context.Background()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
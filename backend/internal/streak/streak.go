@@ -0,0 +1,475 @@
+// Package streak is the streak engine: current and longest streaks, grace
+// periods, freeze-token coverage and the consistency score.
+//
+// Streaks are counted in days (daily mode) or in consecutive ISO weeks that
+// reach a target number of active days (weekly mode).
+//
+// It is pure (no I/O). Callers fetch a user's activity dates and pass them
+// in together with the user's timezone and a grace policy.
+//
+// All dates are calendar days in the user's timezone, represented as
+// midnight UTC (the way lib/pq scans a Postgres DATE).
+package streak
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// FreezeEarnEvery is the streak length that earns a freeze token
+const FreezeEarnEvery = 7
+
+// DefaultMilestones are the streak lengths worth celebrating
+var DefaultMilestones = []int{7, 30, 100}
+
+// GracePolicy controls how forgiving the engine is about missed days
+type GracePolicy struct {
+	// MaxGapDays is the longest run of missed days a single grace can bridge
+	MaxGapDays int
+	// CurrentGraces is how many gaps the current streak may bridge.
+	// An unfinished "today" uses one when yesterday was the last active day.
+	CurrentGraces int
+}
+
+// DefaultGracePolicy matches the original engine: one 1-day grace for the
+// current streak, and any 1-day gap bridged in historical streaks
+var DefaultGracePolicy = GracePolicy{MaxGapDays: 1, CurrentGraces: 1}
+
+// EffortPolicy controls how much each active day adds to the consistency score
+type EffortPolicy struct {
+	// FullMinutes is the focus time that earns a day full credit.
+	// 0 = every active day counts fully, regardless of effort.
+	FullMinutes int
+	// MinCredit is the share an active day earns with no focus time
+	// (e.g. a bare check-in). Credit grows linearly up to 1 at FullMinutes.
+	MinCredit float64
+}
+
+// DefaultEffortPolicy gives a check-in half a day and one pomodoro a full day
+var DefaultEffortPolicy = EffortPolicy{FullMinutes: 25, MinCredit: 0.5}
+
+// Input is everything the engine needs to evaluate one user
+type Input struct {
+	ActiveDates []time.Time    // Days with activity (any order, duplicates ignored)
+	FrozenDates []time.Time    // Missed days covered by a freeze token
+	Location    *time.Location // User's timezone (nil = UTC)
+	Now         time.Time
+	JoinedAt    time.Time // Profile creation, for the consistency score
+	Window      int       // Consistency window in days (0 = lifetime)
+	Grace       GracePolicy
+	// FocusMinutes is the focus time per active day, for effort weighting
+	FocusMinutes map[time.Time]int
+	Effort       EffortPolicy
+	// WeeklyTarget switches to weekly mode: streaks count consecutive ISO
+	// weeks with at least this many covered days. 0 = daily mode.
+	WeeklyTarget int
+}
+
+// Result is the outcome of a streak evaluation
+type Result struct {
+	CurrentStreak    int // Days, or weeks in weekly mode
+	LongestStreak    int
+	LastActiveDate   *time.Time
+	StreakStart      *time.Time // First day (or week) of the current streak
+	GraceUsed        int        // Graces consumed by the current streak
+	FrozenInStreak   int        // Frozen days bridged by the current streak
+	TotalActiveDays  int
+	ConsistencyScore int
+	WeekActiveDays   int // Covered days in the current ISO week (weekly mode)
+}
+
+// DayStatus classifies a single day for history views
+type DayStatus string
+
+const (
+	StatusActive DayStatus = "active"
+	StatusFrozen DayStatus = "frozen"
+	StatusMissed DayStatus = "missed"
+)
+
+// Day is one entry of a user's history
+type Day struct {
+	Date   time.Time
+	Status DayStatus
+}
+
+// DateOf returns the calendar day of t in loc, as midnight UTC
+func DateOf(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the user's current calendar day
+func (in Input) Today() time.Time {
+	return DateOf(in.Now, in.Location)
+}
+
+// Calculate evaluates streaks and consistency for one user
+func Calculate(in Input) Result {
+	today := in.Today()
+	active := normalize(in.ActiveDates, today)
+	frozen := toSet(normalize(in.FrozenDates, today))
+	for _, d := range active {
+		delete(frozen, d) // A day that was active is never "frozen"
+	}
+
+	result := Result{
+		TotalActiveDays:  len(active),
+		ConsistencyScore: consistency(active, in, today),
+	}
+	if len(active) == 0 {
+		return result
+	}
+
+	last := active[len(active)-1]
+	result.LastActiveDate = &last
+
+	covered := merge(active, frozen) // descending
+	if in.WeeklyTarget > 0 {
+		var start time.Time
+		result.CurrentStreak, result.LongestStreak, result.WeekActiveDays, start = weekly(covered, today, in.WeeklyTarget)
+		if result.CurrentStreak > 0 {
+			result.StreakStart = &start
+		}
+		return result
+	}
+
+	var start time.Time
+	result.CurrentStreak, result.GraceUsed, result.FrozenInStreak, start = current(covered, frozen, today, in.Grace)
+	if result.CurrentStreak > 0 {
+		result.StreakStart = &start
+	}
+	result.LongestStreak = longest(covered, frozen, in.Grace)
+
+	return result
+}
+
+// current walks back from today over covered days. Exact-day matches extend
+// the streak; a short gap consumes a grace; anything else ends it.
+// Frozen days keep the chain alive without adding to the count.
+// start is the earliest day in the chain.
+func current(covered []time.Time, frozen map[time.Time]bool, today time.Time, grace GracePolicy) (streak, gracesUsed, frozenUsed int, start time.Time) {
+	expected := today
+	for _, d := range covered {
+		gap := daysBetween(d, expected)
+		switch {
+		case gap == 0:
+		case gap <= grace.MaxGapDays && gracesUsed < grace.CurrentGraces:
+			gracesUsed++
+		default:
+			return streak, gracesUsed, frozenUsed, start
+		}
+
+		if frozen[d] {
+			frozenUsed++
+		} else {
+			streak++
+		}
+		start = d
+		expected = d.AddDate(0, 0, -1)
+	}
+	return streak, gracesUsed, frozenUsed, start
+}
+
+// longest finds the best historical run, bridging every gap up to MaxGapDays
+func longest(covered []time.Time, frozen map[time.Time]bool, grace GracePolicy) int {
+	best, run := 0, 0
+	for i, d := range covered {
+		if i > 0 && daysBetween(d, covered[i-1])-1 > grace.MaxGapDays {
+			run = 0
+		}
+		if !frozen[d] {
+			run++
+		}
+		if run > best {
+			best = run
+		}
+	}
+	return best
+}
+
+// weekly counts consecutive ISO weeks reaching the target. The current week
+// counts once the target is met; until then it is pending and doesn't break
+// the streak. start is the Monday of the earliest week in the streak.
+func weekly(covered []time.Time, today time.Time, target int) (streak, best, thisWeek int, start time.Time) {
+	counts := map[time.Time]int{}
+	for _, d := range covered {
+		counts[WeekStart(d)]++
+	}
+	met := func(week time.Time) bool { return counts[week] >= target }
+
+	week := WeekStart(today)
+	thisWeek = counts[week]
+	if met(week) {
+		streak++
+		start = week
+	}
+	for week = week.AddDate(0, 0, -7); met(week); week = week.AddDate(0, 0, -7) {
+		streak++
+		start = week
+	}
+
+	weeks := make([]time.Time, 0, len(counts))
+	for w := range counts {
+		if met(w) {
+			weeks = append(weeks, w)
+		}
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Before(weeks[j]) })
+
+	run := 0
+	for i, w := range weeks {
+		if i > 0 && daysBetween(weeks[i-1], w) != 7 {
+			run = 0
+		}
+		run++
+		if run > best {
+			best = run
+		}
+	}
+
+	return streak, best, thisWeek, start
+}
+
+// MilestonesCrossed returns the milestones reached when a streak grows
+// from before to after, in ascending order
+func MilestonesCrossed(before, after int, milestones []int) []int {
+	var crossed []int
+	for _, m := range milestones {
+		if m > before && m <= after {
+			crossed = append(crossed, m)
+		}
+	}
+	sort.Ints(crossed)
+	return crossed
+}
+
+// WeekStart returns the Monday of d's ISO week
+func WeekStart(d time.Time) time.Time {
+	offset := (int(d.Weekday()) + 6) % 7
+	return d.AddDate(0, 0, -offset)
+}
+
+// consistency is (credited days / days considered) * 100, capped at 100.
+// Days considered run from the join date (or the window) up to today.
+// Each active day is credited by the effort policy.
+func consistency(active []time.Time, in Input, today time.Time) int {
+	total := 1
+	if !in.JoinedAt.IsZero() {
+		if days := int(in.Now.Sub(in.JoinedAt).Hours() / 24); days > total {
+			total = days
+		}
+	}
+	if in.Window > 0 && in.Window < total {
+		total = in.Window
+	}
+
+	from := today.AddDate(0, 0, -(total - 1))
+	credit := 0.0
+	for _, d := range active {
+		if !d.Before(from) {
+			credit += in.Effort.credit(in.FocusMinutes[d])
+		}
+	}
+
+	score := int(math.Round(credit / float64(total) * 100))
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
+
+// credit is the share of a day earned by an active day with the given focus time
+func (p EffortPolicy) credit(minutes int) float64 {
+	if p.FullMinutes <= 0 || minutes >= p.FullMinutes {
+		return 1
+	}
+	if minutes < 0 {
+		minutes = 0
+	}
+	return p.MinCredit + (1-p.MinCredit)*float64(minutes)/float64(p.FullMinutes)
+}
+
+// History returns the status of each of the last days+1 days, most recent first
+func History(in Input, days int) []Day {
+	today := in.Today()
+	active := toSet(normalize(in.ActiveDates, today))
+	frozen := toSet(normalize(in.FrozenDates, today))
+
+	history := make([]Day, 0, days+1)
+	for i := 0; i <= days; i++ {
+		d := today.AddDate(0, 0, -i)
+		status := StatusMissed
+		switch {
+		case active[d]:
+			status = StatusActive
+		case frozen[d]:
+			status = StatusFrozen
+		}
+		history = append(history, Day{Date: d, Status: status})
+	}
+	return history
+}
+
+// PlanFreezes returns the missed days, up to yesterday, that freeze tokens
+// should cover. Tokens are only spent when the user still has a streak and
+// the tokens (plus one grace gap) can actually bridge the missed run.
+// Weekly mode already tolerates missed days, so it never spends tokens.
+func PlanFreezes(in Input, tokens int) []time.Time {
+	if tokens <= 0 || in.WeeklyTarget > 0 {
+		return nil
+	}
+
+	today := in.Today()
+	covered := merge(normalize(in.ActiveDates, today), toSet(normalize(in.FrozenDates, today)))
+	if len(covered) == 0 {
+		return nil
+	}
+
+	lastCovered := covered[0]
+	yesterday := today.AddDate(0, 0, -1)
+	if !lastCovered.Before(yesterday) {
+		return nil
+	}
+
+	// Streak as it stood the day after the last covered day
+	asOf := in
+	asOf.Now = lastCovered.AddDate(0, 0, 1)
+	asOf.Location = time.UTC
+	if Calculate(asOf).CurrentStreak == 0 {
+		return nil
+	}
+
+	missed := daysBetween(lastCovered, yesterday)
+	if missed > tokens+in.Grace.MaxGapDays {
+		return nil
+	}
+
+	use := missed
+	if tokens < use {
+		use = tokens
+	}
+	dates := make([]time.Time, 0, use)
+	for i := 1; i <= use; i++ {
+		dates = append(dates, lastCovered.AddDate(0, 0, i))
+	}
+	return dates
+}
+
+// Rescuable reports whether activity today would still continue the
+// user's last streak, with tokens spent as PlanFreezes would. A streak can
+// read 0 (an unfinished today can't use the grace once yesterday was
+// missed too) and yet not be over: logging today bridges the gap.
+func Rescuable(in Input, tokens int) bool {
+	rescued := in
+	rescued.FrozenDates = append(append([]time.Time{}, in.FrozenDates...), PlanFreezes(in, tokens)...)
+	rescued.ActiveDates = append(append([]time.Time{}, in.ActiveDates...), in.Today())
+
+	// Today alone makes a streak of 1; more means the old streak carried on
+	return Calculate(rescued).CurrentStreak > 1
+}
+
+// Member is one squad member's activity, for squad streaks
+type Member struct {
+	JoinedOn    time.Time   // Calendar day the member joined
+	ActiveDates []time.Time // Days with activity (member's timezone)
+}
+
+// SquadDays returns the days, up to today, on which enough members were
+// active. Days after today are ignored (not clamped): they are still ahead
+// for the squad. quorum is the number of active members needed (0 = everyone);
+// it is capped at the number of members who had joined by that day, so
+// new members never break a streak retroactively.
+func SquadDays(members []Member, quorum int, today time.Time) []time.Time {
+	active := map[time.Time]int{}
+	for _, m := range members {
+		joined := DateOf(m.JoinedOn, time.UTC)
+		seen := map[time.Time]bool{}
+		for _, d := range m.ActiveDates {
+			day := DateOf(d, time.UTC)
+			if seen[day] || day.Before(joined) || day.After(today) {
+				continue
+			}
+			seen[day] = true
+			active[day]++
+		}
+	}
+
+	days := []time.Time{}
+	for d, count := range active {
+		eligible := 0
+		for _, m := range members {
+			if !DateOf(m.JoinedOn, time.UTC).After(d) {
+				eligible++
+			}
+		}
+
+		required := eligible
+		if quorum > 0 && quorum < required {
+			required = quorum
+		}
+		if required > 0 && count >= required {
+			days = append(days, d)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// EarnsFreezeToken reports whether reaching this streak earns a token
+func EarnsFreezeToken(currentStreak int) bool {
+	return currentStreak > 0 && currentStreak%FreezeEarnEvery == 0
+}
+
+// normalize truncates to calendar days, clamps future days (e.g. after a
+// timezone change) to today, dedupes and sorts ascending
+func normalize(dates []time.Time, today time.Time) []time.Time {
+	set := map[time.Time]bool{}
+	for _, d := range dates {
+		day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		if day.After(today) {
+			day = today
+		}
+		set[day] = true
+	}
+
+	out := make([]time.Time, 0, len(set))
+	for d := range set {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+func toSet(dates []time.Time) map[time.Time]bool {
+	set := make(map[time.Time]bool, len(dates))
+	for _, d := range dates {
+		set[d] = true
+	}
+	return set
+}
+
+// merge returns active and frozen days together, most recent first
+func merge(active []time.Time, frozen map[time.Time]bool) []time.Time {
+	set := toSet(active)
+	for d := range frozen {
+		set[d] = true
+	}
+
+	out := make([]time.Time, 0, len(set))
+	for d := range set {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].After(out[j]) })
+	return out
+}
+
+// daysBetween returns whole days from a to b (positive if b is later)
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
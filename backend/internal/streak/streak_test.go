@@ -0,0 +1,481 @@
+package streak
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+var today = time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+// daysAgo builds calendar dates relative to today
+func daysAgo(offsets ...int) []time.Time {
+	dates := make([]time.Time, 0, len(offsets))
+	for _, o := range offsets {
+		dates = append(dates, today.AddDate(0, 0, -o))
+	}
+	return dates
+}
+
+func input(active, frozen []time.Time) Input {
+	return Input{
+		ActiveDates: active,
+		FrozenDates: frozen,
+		Location:    time.UTC,
+		Now:         today.Add(15 * time.Hour),
+		Grace:       DefaultGracePolicy,
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name        string
+		active      []time.Time
+		frozen      []time.Time
+		wantCurrent int
+		wantLongest int
+		wantGrace   int
+		wantFrozen  int
+	}{
+		{"no activity", nil, nil, 0, 0, 0, 0},
+		{"active today only", daysAgo(0), nil, 1, 1, 0, 0},
+		{"three days ending today", daysAgo(0, 1, 2), nil, 3, 3, 0, 0},
+		{"today pending uses grace", daysAgo(1, 2, 3), nil, 3, 3, 1, 0},
+		{"one missed day bridged by grace", daysAgo(0, 2, 3), nil, 3, 3, 1, 0},
+		{"second gap breaks current streak", daysAgo(0, 2, 4, 5), nil, 2, 4, 1, 0},
+		{"two missed days break streak", daysAgo(3, 4, 5), nil, 0, 3, 0, 0},
+		{"duplicates and order ignored", daysAgo(2, 0, 1, 0, 2), nil, 3, 3, 0, 0},
+		{"historical run beats current", daysAgo(0, 10, 11, 12, 13), nil, 1, 4, 0, 0},
+		{"frozen day bridges without counting", daysAgo(0, 2, 3), daysAgo(1), 3, 3, 0, 1},
+		{"frozen plus grace", daysAgo(0, 3, 4), daysAgo(1), 3, 3, 1, 1},
+		{"frozen day that was active counts as active", daysAgo(0, 1), daysAgo(1), 2, 2, 0, 0},
+		{"future day is clamped to today", []time.Time{today.AddDate(0, 0, 1), today.AddDate(0, 0, -1)}, nil, 2, 2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(input(tt.active, tt.frozen))
+			if got.CurrentStreak != tt.wantCurrent {
+				t.Errorf("current streak: expected %d, got %d", tt.wantCurrent, got.CurrentStreak)
+			}
+			if got.LongestStreak != tt.wantLongest {
+				t.Errorf("longest streak: expected %d, got %d", tt.wantLongest, got.LongestStreak)
+			}
+			if got.GraceUsed != tt.wantGrace {
+				t.Errorf("grace used: expected %d, got %d", tt.wantGrace, got.GraceUsed)
+			}
+			if got.FrozenInStreak != tt.wantFrozen {
+				t.Errorf("frozen in streak: expected %d, got %d", tt.wantFrozen, got.FrozenInStreak)
+			}
+		})
+	}
+}
+
+func TestCalculate_Weekly(t *testing.T) {
+	// today (2024-06-15) is a Saturday: days 0-5 are this ISO week,
+	// 6-12 the previous week and 13-19 the week before
+	tests := []struct {
+		name         string
+		active       []time.Time
+		frozen       []time.Time
+		target       int
+		wantCurrent  int
+		wantLongest  int
+		wantThisWeek int
+	}{
+		{"no activity", nil, nil, 3, 0, 0, 0},
+		{"current week met", daysAgo(0, 1, 2), nil, 3, 1, 1, 3},
+		{"current week pending keeps streak", daysAgo(0, 6, 7, 8, 13, 14, 15), nil, 3, 2, 2, 1},
+		{"week below target breaks streak", daysAgo(6, 7, 13, 14, 15), nil, 3, 0, 1, 0},
+		{"frozen day counts toward target", daysAgo(6, 7), daysAgo(8), 3, 1, 1, 0},
+		{"longest run in history", daysAgo(6, 20, 27, 34), nil, 1, 1, 3, 0},
+		{"duplicates count once", daysAgo(1, 1, 1), nil, 2, 0, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := input(tt.active, tt.frozen)
+			in.WeeklyTarget = tt.target
+			got := Calculate(in)
+			if got.CurrentStreak != tt.wantCurrent {
+				t.Errorf("current streak: expected %d, got %d", tt.wantCurrent, got.CurrentStreak)
+			}
+			if got.LongestStreak != tt.wantLongest {
+				t.Errorf("longest streak: expected %d, got %d", tt.wantLongest, got.LongestStreak)
+			}
+			if got.WeekActiveDays != tt.wantThisWeek {
+				t.Errorf("week active days: expected %d, got %d", tt.wantThisWeek, got.WeekActiveDays)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		if got := WeekStart(monday.AddDate(0, 0, i)); !got.Equal(monday) {
+			t.Errorf("day %d: expected %v, got %v", i, monday, got)
+		}
+	}
+	if got := WeekStart(monday.AddDate(0, 0, 7)); got.Equal(monday) {
+		t.Error("next Monday should start a new week")
+	}
+}
+
+func TestCalculate_Timezone(t *testing.T) {
+	// 2024-06-15 20:00 UTC is already 2024-06-16 in Kolkata
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	in := Input{
+		ActiveDates: []time.Time{today.AddDate(0, 0, 1), today},
+		Location:    kolkata,
+		Now:         today.Add(20 * time.Hour),
+		Grace:       DefaultGracePolicy,
+	}
+
+	got := Calculate(in)
+	if got.CurrentStreak != 2 || got.GraceUsed != 0 {
+		t.Errorf("expected 2-day streak without grace, got %d (grace %d)", got.CurrentStreak, got.GraceUsed)
+	}
+}
+
+func TestCalculate_StreakStart(t *testing.T) {
+	firstWeek := WeekStart(today.AddDate(0, 0, -14))
+
+	tests := []struct {
+		name   string
+		active []time.Time
+		frozen []time.Time
+		weekly int
+		want   *time.Time
+	}{
+		{"no streak", daysAgo(5), nil, 0, nil},
+		{"run ending today", daysAgo(0, 1, 2, 9), nil, 0, &daysAgo(2)[0]},
+		{"grace and freeze extend the run", daysAgo(1, 3, 4), daysAgo(2), 0, &daysAgo(4)[0]},
+		{"weekly starts on a monday", daysAgo(0, 7, 14), nil, 1, &firstWeek},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := input(tt.active, tt.frozen)
+			in.WeeklyTarget = tt.weekly
+			got := Calculate(in).StreakStart
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("expected no start, got %v", *got)
+			case tt.want != nil && (got == nil || !got.Equal(*tt.want)):
+				t.Errorf("expected %v, got %v", *tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMilestonesCrossed(t *testing.T) {
+	tests := []struct {
+		before, after int
+		want          []int
+	}{
+		{6, 7, []int{7}},
+		{7, 7, nil},
+		{7, 8, nil},
+		{0, 45, []int{7, 30}},
+		{99, 100, []int{100}},
+		{10, 3, nil},
+	}
+
+	for _, tt := range tests {
+		got := MilestonesCrossed(tt.before, tt.after, DefaultMilestones)
+		if len(got) != len(tt.want) {
+			t.Errorf("%d -> %d: expected %v, got %v", tt.before, tt.after, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%d -> %d: expected %v, got %v", tt.before, tt.after, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		active   []time.Time
+		joined   time.Time
+		window   int
+		expected int
+	}{
+		{"joined today, active", daysAgo(0), today, 0, 100},
+		{"half of ten days", daysAgo(0, 2, 4, 6, 8), today.AddDate(0, 0, -10), 0, 50},
+		{"window limits days considered", daysAgo(0, 1, 2), today.AddDate(0, 0, -100), 3, 100},
+		{"activity before window ignored", daysAgo(0, 50), today.AddDate(0, 0, -100), 10, 10},
+		{"capped at 100", daysAgo(0, 1, 2, 3), today.AddDate(0, 0, -1), 0, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := input(tt.active, nil)
+			in.JoinedAt = tt.joined
+			in.Window = tt.window
+			if got := Calculate(in).ConsistencyScore; got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConsistency_Effort(t *testing.T) {
+	minutes := func(m ...int) map[time.Time]int {
+		out := map[time.Time]int{}
+		for i, v := range m {
+			out[today.AddDate(0, 0, -i)] = v
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		minutes  map[time.Time]int
+		policy   EffortPolicy
+		expected int
+	}{
+		{"no policy counts every day fully", minutes(0, 0), EffortPolicy{}, 100},
+		{"check-ins earn min credit", minutes(0, 0), DefaultEffortPolicy, 50},
+		{"full focus earns full credit", minutes(25, 90), DefaultEffortPolicy, 100},
+		{"partial focus is linear", minutes(10, 10), EffortPolicy{FullMinutes: 20, MinCredit: 0.5}, 75},
+		{"mixed days", minutes(25, 0), DefaultEffortPolicy, 75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := input(daysAgo(0, 1), nil)
+			in.JoinedAt = today.AddDate(0, 0, -2)
+			in.FocusMinutes = tt.minutes
+			in.Effort = tt.policy
+			if got := Calculate(in).ConsistencyScore; got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	history := History(input(daysAgo(0, 2), daysAgo(1)), 3)
+
+	want := []DayStatus{StatusActive, StatusFrozen, StatusActive, StatusMissed}
+	if len(history) != len(want) {
+		t.Fatalf("expected %d days, got %d", len(want), len(history))
+	}
+	for i, day := range history {
+		if day.Status != want[i] {
+			t.Errorf("day %d: expected %s, got %s", i, want[i], day.Status)
+		}
+		if !day.Date.Equal(today.AddDate(0, 0, -i)) {
+			t.Errorf("day %d: unexpected date %v", i, day.Date)
+		}
+	}
+}
+
+func TestPlanFreezes(t *testing.T) {
+	tests := []struct {
+		name   string
+		active []time.Time
+		frozen []time.Time
+		tokens int
+		want   []time.Time
+	}{
+		{"no tokens", daysAgo(2, 3), nil, 0, nil},
+		{"active yesterday", daysAgo(1, 2), nil, 2, nil},
+		{"covers missed yesterday", daysAgo(2, 3), nil, 1, daysAgo(1)},
+		{"covers two missed days", daysAgo(3, 4), nil, 2, daysAgo(2, 1)},
+		{"one token plus grace", daysAgo(3, 4), nil, 1, daysAgo(2)},
+		{"too many missed days", daysAgo(5, 6), nil, 2, nil},
+		{"continues after earlier freeze", daysAgo(3), daysAgo(2), 1, daysAgo(1)},
+		{"no activity", nil, nil, 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanFreezes(input(tt.active, tt.frozen), tt.tokens)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRescuable(t *testing.T) {
+	tests := []struct {
+		name   string
+		active []time.Time
+		frozen []time.Time
+		tokens int
+		want   bool
+	}{
+		{"missed yesterday and the day before", daysAgo(2, 3, 4), nil, 0, true},
+		{"missed three days", daysAgo(3, 4), nil, 0, false},
+		{"token covers a missed day", daysAgo(3, 4), nil, 1, true},
+		{"frozen day bridges", daysAgo(3, 4), daysAgo(2), 0, true},
+		{"single old day", daysAgo(10), nil, 2, false},
+		{"no activity", nil, nil, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rescuable(input(tt.active, tt.frozen), tt.tokens); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEarnsFreezeToken(t *testing.T) {
+	for streak, want := range map[int]bool{0: false, 1: false, 6: false, 7: true, 8: false, 14: true} {
+		if got := EarnsFreezeToken(streak); got != want {
+			t.Errorf("streak %d: expected %v, got %v", streak, want, got)
+		}
+	}
+}
+
+func TestSquadDays(t *testing.T) {
+	joined := today.AddDate(0, 0, -30)
+	alice := Member{JoinedOn: joined, ActiveDates: daysAgo(0, 1, 2, 3)}
+	bob := Member{JoinedOn: joined, ActiveDates: daysAgo(0, 2, 3)}
+	carol := Member{JoinedOn: joined, ActiveDates: daysAgo(1, 3)}
+	late := Member{JoinedOn: today.AddDate(0, 0, -1), ActiveDates: daysAgo(0, 1)}
+
+	tests := []struct {
+		name    string
+		members []Member
+		quorum  int
+		want    []time.Time
+	}{
+		{"everyone", []Member{alice, bob, carol}, 0, daysAgo(3)},
+		{"quorum of two", []Member{alice, bob, carol}, 2, daysAgo(3, 2, 1, 0)},
+		{"quorum above member count means everyone", []Member{alice, bob}, 5, daysAgo(3, 2, 0)},
+		{"new member only counts from joining", []Member{alice, bob, late}, 0, daysAgo(3, 2, 0)},
+		{"no members", nil, 0, []time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SquadDays(tt.members, tt.quorum, today); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// randomHistory is a quick.Generator producing activity within the last 60 days
+type randomHistory struct {
+	Active []time.Time
+	Frozen []time.Time
+}
+
+func (randomHistory) Generate(r *rand.Rand, size int) reflect.Value {
+	h := randomHistory{}
+	for i := 0; i < 60; i++ {
+		switch r.Intn(4) {
+		case 0, 1:
+			h.Active = append(h.Active, today.AddDate(0, 0, -i))
+		case 2:
+			if r.Intn(4) == 0 {
+				h.Frozen = append(h.Frozen, today.AddDate(0, 0, -i))
+			}
+		}
+	}
+	r.Shuffle(len(h.Active), func(i, j int) { h.Active[i], h.Active[j] = h.Active[j], h.Active[i] })
+	return reflect.ValueOf(h)
+}
+
+func TestCalculate_Properties(t *testing.T) {
+	config := &quick.Config{MaxCount: 500}
+
+	t.Run("LongestAtLeastCurrent", func(t *testing.T) {
+		f := func(h randomHistory) bool {
+			got := Calculate(input(h.Active, h.Frozen))
+			return got.LongestStreak >= got.CurrentStreak
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("StreaksBoundedByActiveDays", func(t *testing.T) {
+		f := func(h randomHistory) bool {
+			got := Calculate(input(h.Active, h.Frozen))
+			return got.CurrentStreak <= got.TotalActiveDays && got.LongestStreak <= got.TotalActiveDays
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("ActivityTodayNeverLowersStreak", func(t *testing.T) {
+		f := func(h randomHistory) bool {
+			before := Calculate(input(h.Active, h.Frozen))
+			after := Calculate(input(append(h.Active, today), h.Frozen))
+			return after.CurrentStreak >= before.CurrentStreak && after.LongestStreak >= before.LongestStreak
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("FreezesNeverLowerStreak", func(t *testing.T) {
+		f := func(h randomHistory) bool {
+			without := Calculate(input(h.Active, nil))
+			with := Calculate(input(h.Active, h.Frozen))
+			return with.CurrentStreak >= without.CurrentStreak && with.LongestStreak >= without.LongestStreak
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("PlannedFreezesKeepStreakAlive", func(t *testing.T) {
+		f := func(h randomHistory, tokens uint8) bool {
+			in := input(h.Active, h.Frozen)
+			planned := PlanFreezes(in, int(tokens%3))
+			if len(planned) == 0 {
+				return true
+			}
+			in.FrozenDates = append(in.FrozenDates, planned...)
+			// Once tomorrow's activity lands, the streak must still be running
+			in.ActiveDates = append(in.ActiveDates, today)
+			return Calculate(in).CurrentStreak > 1
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("WeeklyLongestAtLeastCurrent", func(t *testing.T) {
+		f := func(h randomHistory, target uint8) bool {
+			in := input(h.Active, h.Frozen)
+			in.WeeklyTarget = int(target%7) + 1
+			got := Calculate(in)
+			return got.LongestStreak >= got.CurrentStreak && got.WeekActiveDays <= 7
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("ConsistencyInRange", func(t *testing.T) {
+		f := func(h randomHistory, joinedDaysAgo uint8, window uint8) bool {
+			in := input(h.Active, h.Frozen)
+			in.JoinedAt = today.AddDate(0, 0, -int(joinedDaysAgo))
+			in.Window = int(window % 60)
+			score := Calculate(in).ConsistencyScore
+			return score >= 0 && score <= 100
+		}
+		if err := quick.Check(f, config); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+-- ============================================================
+-- 007_create_job_scheduler.sql
+-- Background Job Scheduler - Job registry and run history
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. SCHEDULED JOBS TABLE
+-- One row per named job registered by the Go scheduler.
+-- paused is shared by all replicas.
+-- ============================================================
+
+CREATE TABLE public.scheduled_jobs (
+    name TEXT PRIMARY KEY,
+    schedule TEXT NOT NULL,
+    paused BOOLEAN DEFAULT FALSE NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+COMMENT ON TABLE public.scheduled_jobs IS 'Background jobs registered by the in-process scheduler';
+COMMENT ON COLUMN public.scheduled_jobs.schedule IS 'Cron expression (UTC), e.g. 0 * * * *';
+
+-- ============================================================
+-- 2. JOB RUNS TABLE
+-- History of every execution (scheduled or manual)
+-- ============================================================
+
+CREATE TABLE public.job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name TEXT NOT NULL REFERENCES public.scheduled_jobs(name) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    trigger TEXT NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    instance TEXT NOT NULL,
+    started_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    finished_at TIMESTAMPTZ,
+    error TEXT
+);
+
+COMMENT ON TABLE public.job_runs IS 'Execution history of scheduled jobs';
+COMMENT ON COLUMN public.job_runs.instance IS 'Replica (hostname-pid) that executed the run';
+
+-- Run history per job (most recent first)
+CREATE INDEX idx_job_runs_job_started ON public.job_runs(job_name, started_at DESC);
+
+-- ============================================================
+-- 3. RLS POLICIES
+-- Backend-only tables: RLS enabled with no policies,
+-- so only the service role can read or write.
+-- ============================================================
+
+ALTER TABLE public.scheduled_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.job_runs ENABLE ROW LEVEL SECURITY;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,36 @@
+-- ============================================================
+-- 008_create_streak_risk_alerts.sql
+-- The Nudge System - Timezone-aware streak risk alert ledger
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. STREAK RISK ALERTS TABLE
+-- One row per graded alert sent for a user's local day.
+-- The primary key guarantees a user is never alerted twice
+-- for the same threshold on the same local day.
+-- ============================================================
+
+CREATE TABLE public.streak_risk_alerts (
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    local_date DATE NOT NULL,
+    threshold TEXT NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (user_id, local_date, threshold)
+);
+
+COMMENT ON TABLE public.streak_risk_alerts IS 'Ledger of streak risk alerts, used to dedupe nudges per local day';
+COMMENT ON COLUMN public.streak_risk_alerts.local_date IS 'Day at risk in user timezone (from profiles.timezone)';
+COMMENT ON COLUMN public.streak_risk_alerts.threshold IS 'Alert grade, e.g. 6h or 2h before local midnight';
+
+-- ============================================================
+-- 2. RLS POLICIES
+-- Backend-only table: RLS enabled with no policies.
+-- ============================================================
+
+ALTER TABLE public.streak_risk_alerts ENABLE ROW LEVEL SECURITY;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,334 @@
+-- ============================================================
+-- 009_create_streak_freezes.sql
+-- The Streak Engine - Freeze tokens that protect a missed day
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. PROFILE TOKEN BALANCE
+-- ============================================================
+
+ALTER TABLE public.profiles
+    ADD COLUMN freeze_tokens INTEGER DEFAULT 0 NOT NULL
+        CHECK (freeze_tokens >= 0 AND freeze_tokens <= 2);
+
+COMMENT ON COLUMN public.profiles.freeze_tokens IS 'Streak freeze tokens held (max 2). Earned every 7 streak days or granted.';
+
+-- ============================================================
+-- 2. FREEZE LEDGER
+-- Every token earned, granted or used.
+-- freeze_date is the local day the event applies to:
+--   earned  -> activity day that completed the 7-day block
+--   granted -> day of the grant
+--   used    -> missed day covered by the token
+-- ============================================================
+
+CREATE TABLE public.streak_freezes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    event TEXT NOT NULL CHECK (event IN ('earned', 'granted', 'used')),
+    freeze_date DATE NOT NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+COMMENT ON TABLE public.streak_freezes IS 'Ledger of streak freeze tokens (earned, granted, used)';
+COMMENT ON COLUMN public.streak_freezes.freeze_date IS 'Day in user timezone the event applies to';
+
+-- A day can only be frozen once, and a token is earned at most once per day
+CREATE UNIQUE INDEX idx_streak_freezes_used_day
+    ON public.streak_freezes(user_id, freeze_date)
+    WHERE event = 'used';
+CREATE UNIQUE INDEX idx_streak_freezes_earned_day
+    ON public.streak_freezes(user_id, freeze_date)
+    WHERE event = 'earned';
+
+-- Ledger lookups per user (most recent first)
+CREATE INDEX idx_streak_freezes_user_date ON public.streak_freezes(user_id, freeze_date DESC);
+
+-- ============================================================
+-- 3. RLS POLICIES
+-- ============================================================
+
+ALTER TABLE public.streak_freezes ENABLE ROW LEVEL SECURITY;
+
+-- Users can read their own ledger; writes happen via backend functions only
+CREATE POLICY "Users can read own streak freezes"
+    ON public.streak_freezes
+    FOR SELECT
+    TO authenticated
+    USING (auth.uid() = user_id);
+
+-- ============================================================
+-- 4. STREAK CALCULATION (replaces 005 version)
+-- Frozen days bridge a streak without adding to it.
+-- The 1-day grace period still applies on top of freezes.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.calculate_streak(p_user_id UUID)
+RETURNS TABLE(
+    current_streak INTEGER,
+    longest_streak INTEGER,
+    last_active_date DATE
+)
+LANGUAGE plpgsql
+STABLE
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_timezone TEXT;
+    v_today DATE;
+    v_dates DATE[];
+    v_frozen DATE[];
+    v_current_streak INT := 0;
+    v_longest_streak INT := 0;
+    v_temp_streak INT := 0;
+    v_last_date DATE;
+    v_grace_used BOOLEAN := FALSE;
+    v_day DATE;
+    v_is_frozen BOOLEAN;
+    v_last_active DATE;
+BEGIN
+    -- Get user's timezone
+    v_timezone := get_user_timezone(p_user_id);
+    v_today := (NOW() AT TIME ZONE v_timezone)::DATE;
+
+    -- Last real activity (frozen days don't count as activity)
+    SELECT MAX(activity_date) INTO v_last_active
+    FROM activity_logs
+    WHERE user_id = p_user_id;
+
+    -- No activity = all zeros
+    IF v_last_active IS NULL THEN
+        RETURN QUERY SELECT 0, 0, NULL::DATE;
+        RETURN;
+    END IF;
+
+    -- Frozen days
+    SELECT COALESCE(ARRAY_AGG(freeze_date), '{}')
+    INTO v_frozen
+    FROM streak_freezes
+    WHERE user_id = p_user_id AND event = 'used';
+
+    -- All covered days (active or frozen) sorted descending
+    SELECT ARRAY_AGG(d ORDER BY d DESC)
+    INTO v_dates
+    FROM (
+        SELECT activity_date AS d FROM activity_logs WHERE user_id = p_user_id
+        UNION
+        SELECT freeze_date FROM streak_freezes WHERE user_id = p_user_id AND event = 'used'
+    ) covered;
+
+    -- Calculate current streak (from today backwards)
+    v_last_date := v_today;
+
+    FOREACH v_day IN ARRAY v_dates LOOP
+        v_is_frozen := v_day = ANY(v_frozen);
+
+        IF v_day = v_last_date THEN
+            IF NOT v_is_frozen THEN
+                v_current_streak := v_current_streak + 1;
+            END IF;
+            v_last_date := v_last_date - 1;
+        ELSIF v_day = v_last_date - 1 AND NOT v_grace_used THEN
+            -- 1-day grace period
+            IF NOT v_is_frozen THEN
+                v_current_streak := v_current_streak + 1;
+            END IF;
+            v_grace_used := TRUE;
+            v_last_date := v_day - 1;
+        ELSE
+            -- Streak broken
+            EXIT;
+        END IF;
+    END LOOP;
+
+    -- Calculate longest streak (any time in history)
+    v_temp_streak := CASE WHEN v_dates[1] = ANY(v_frozen) THEN 0 ELSE 1 END;
+    v_longest_streak := v_temp_streak;
+
+    FOR i IN 2..COALESCE(ARRAY_LENGTH(v_dates, 1), 1) LOOP
+        v_is_frozen := v_dates[i] = ANY(v_frozen);
+
+        IF v_dates[i] = v_dates[i-1] - 1 OR v_dates[i] = v_dates[i-1] - 2 THEN
+            -- Consecutive, or within the historical grace period
+            IF NOT v_is_frozen THEN
+                v_temp_streak := v_temp_streak + 1;
+            END IF;
+        ELSE
+            v_temp_streak := CASE WHEN v_is_frozen THEN 0 ELSE 1 END;
+        END IF;
+
+        IF v_temp_streak > v_longest_streak THEN
+            v_longest_streak := v_temp_streak;
+        END IF;
+    END LOOP;
+
+    RETURN QUERY SELECT v_current_streak, v_longest_streak, v_last_active;
+END;
+$$;
+
+COMMENT ON FUNCTION public.calculate_streak IS 'Calculates current and longest streak. Frozen days bridge gaps; 1-day grace period applies.';
+
+-- ============================================================
+-- 5. APPLY FREEZES
+-- Spends tokens on missed local days since the last covered day,
+-- up to yesterday. Tokens are only spent if they (plus the grace
+-- day) can actually save the streak.
+-- Returns the number of tokens used.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.apply_streak_freezes(p_user_id UUID)
+RETURNS INTEGER
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_timezone TEXT;
+    v_yesterday DATE;
+    v_tokens INT;
+    v_streak INT;
+    v_last_covered DATE;
+    v_missed INT;
+    v_to_use INT;
+BEGIN
+    SELECT COALESCE(timezone, 'UTC'), freeze_tokens, current_streak
+    INTO v_timezone, v_tokens, v_streak
+    FROM public.profiles
+    WHERE id = p_user_id
+    FOR UPDATE;
+
+    IF v_tokens IS NULL OR v_tokens = 0 OR v_streak = 0 THEN
+        RETURN 0;
+    END IF;
+
+    v_yesterday := (NOW() AT TIME ZONE v_timezone)::DATE - 1;
+
+    SELECT MAX(d) INTO v_last_covered
+    FROM (
+        SELECT activity_date AS d FROM activity_logs WHERE user_id = p_user_id
+        UNION ALL
+        SELECT freeze_date FROM streak_freezes WHERE user_id = p_user_id AND event = 'used'
+    ) covered;
+
+    IF v_last_covered IS NULL OR v_last_covered >= v_yesterday THEN
+        RETURN 0;
+    END IF;
+
+    v_missed := v_yesterday - v_last_covered;
+
+    -- Too many missed days: tokens + grace can't save it
+    IF v_missed > v_tokens + 1 THEN
+        RETURN 0;
+    END IF;
+
+    v_to_use := LEAST(v_missed, v_tokens);
+
+    INSERT INTO streak_freezes (user_id, event, freeze_date, note)
+    SELECT p_user_id, 'used', v_last_covered + g, 'Auto-applied to missed day'
+    FROM generate_series(1, v_to_use) AS g
+    ON CONFLICT DO NOTHING;
+
+    UPDATE public.profiles
+    SET freeze_tokens = freeze_tokens - v_to_use,
+        updated_at = NOW()
+    WHERE id = p_user_id;
+
+    RETURN v_to_use;
+END;
+$$;
+
+COMMENT ON FUNCTION public.apply_streak_freezes IS 'Spends freeze tokens on missed days up to yesterday (user timezone).';
+
+-- ============================================================
+-- 6. GRANT FREEZES
+-- Adds tokens (capped at 2) and records the grant.
+-- Returns the new balance.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.grant_streak_freezes(
+    p_user_id UUID,
+    p_count INTEGER,
+    p_note TEXT DEFAULT NULL
+)
+RETURNS INTEGER
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_balance INT;
+BEGIN
+    UPDATE public.profiles
+    SET freeze_tokens = LEAST(2, freeze_tokens + GREATEST(p_count, 0)),
+        updated_at = NOW()
+    WHERE id = p_user_id
+    RETURNING freeze_tokens INTO v_balance;
+
+    IF v_balance IS NULL THEN
+        RAISE EXCEPTION 'Profile not found';
+    END IF;
+
+    INSERT INTO streak_freezes (user_id, event, freeze_date, note)
+    VALUES (p_user_id, 'granted', (NOW() AT TIME ZONE get_user_timezone(p_user_id))::DATE, p_note);
+
+    RETURN v_balance;
+END;
+$$;
+
+-- ============================================================
+-- 7. DAILY STREAK UPDATE TRIGGER (replaces 005 version)
+-- Also earns one token for every completed 7-day block.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.update_streak_on_activity()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_streak_data RECORD;
+    v_consistency INT;
+BEGIN
+    -- Calculate latest streak data
+    SELECT * INTO v_streak_data
+    FROM calculate_streak(NEW.user_id);
+
+    -- Calculate consistency score (lifetime)
+    v_consistency := get_consistency_score(NEW.user_id, 0);
+
+    -- Update profiles table
+    UPDATE public.profiles
+    SET
+        current_streak = v_streak_data.current_streak,
+        longest_streak = GREATEST(longest_streak, v_streak_data.longest_streak),
+        consistency_score = v_consistency,
+        updated_at = NOW()
+    WHERE id = NEW.user_id;
+
+    -- Earn a freeze token every 7 streak days (max 2 held)
+    IF v_streak_data.current_streak > 0 AND v_streak_data.current_streak % 7 = 0 THEN
+        INSERT INTO streak_freezes (user_id, event, freeze_date, note)
+        SELECT NEW.user_id, 'earned', NEW.activity_date,
+               'Reached ' || v_streak_data.current_streak || '-day streak'
+        FROM public.profiles
+        WHERE id = NEW.user_id AND freeze_tokens < 2
+        ON CONFLICT DO NOTHING;
+
+        IF FOUND THEN
+            UPDATE public.profiles
+            SET freeze_tokens = freeze_tokens + 1
+            WHERE id = NEW.user_id;
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
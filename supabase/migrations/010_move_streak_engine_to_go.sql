@@ -0,0 +1,29 @@
+-- ============================================================
+-- 010_move_streak_engine_to_go.sql
+-- The Streak Engine - Calculation moves to the backend
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+-- Streaks, grace periods, freeze coverage and consistency are now
+-- computed by the backend streak package. The database only stores
+-- activity dates, the freeze ledger and the computed profile columns.
+
+-- ============================================================
+-- 1. DROP STREAK TRIGGER
+-- The backend recomputes the streak after logging activity.
+-- ============================================================
+
+DROP TRIGGER IF EXISTS on_activity_logged ON public.activity_logs;
+DROP FUNCTION IF EXISTS public.update_streak_on_activity();
+
+-- ============================================================
+-- 2. DROP CALCULATION FUNCTIONS
+-- Replaced by the backend engine and its batched recalculation job.
+-- ============================================================
+
+DROP FUNCTION IF EXISTS public.apply_streak_freezes(UUID);
+DROP FUNCTION IF EXISTS public.calculate_streak(UUID);
+DROP FUNCTION IF EXISTS public.get_consistency_score(UUID, INTEGER);
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,44 @@
+-- ============================================================
+-- 011_add_streak_modes.sql
+-- The Streak Engine - Daily or weekly-target streak modes
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. PROFILE STREAK MODE
+-- 'daily'    -> consecutive active days
+-- 'weekly:N' -> consecutive ISO weeks (user timezone) with >= N active days
+-- current_streak is stored in the unit of the current mode.
+-- ============================================================
+
+ALTER TABLE public.profiles
+    ADD COLUMN streak_mode TEXT DEFAULT 'daily' NOT NULL
+        CHECK (streak_mode ~ '^(daily|weekly:[1-7])$');
+
+COMMENT ON COLUMN public.profiles.streak_mode IS 'Streak mode: daily, or weekly:N (N active days per ISO week)';
+
+-- ============================================================
+-- 2. LONGEST STREAK PER UNIT
+-- Day and week streaks are kept apart so switching modes never
+-- overwrites (or inflates) the other mode's record.
+-- ============================================================
+
+ALTER TABLE public.profiles
+    ADD COLUMN longest_weekly_streak INTEGER DEFAULT 0 NOT NULL
+        CHECK (longest_weekly_streak >= 0);
+
+COMMENT ON COLUMN public.profiles.longest_streak IS 'Longest daily streak (days)';
+COMMENT ON COLUMN public.profiles.longest_weekly_streak IS 'Longest weekly streak (weeks)';
+
+-- ============================================================
+-- 3. INDEXES
+-- Leaderboards rank each mode family separately.
+-- ============================================================
+
+CREATE INDEX idx_profiles_streak_mode_current
+    ON public.profiles((streak_mode LIKE 'weekly:%'), current_streak DESC)
+    WHERE current_streak > 0;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
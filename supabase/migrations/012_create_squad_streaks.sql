@@ -0,0 +1,52 @@
+-- ============================================================
+-- 012_create_squad_streaks.sql
+-- The Streak Engine - Shared squad streaks
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. SQUAD STREAK COLUMNS
+-- A squad day counts when every member (or the quorum) logged
+-- activity. Computed by the backend on activity and nightly.
+-- ============================================================
+
+ALTER TABLE public.squads
+    ADD COLUMN streak_quorum INTEGER CHECK (streak_quorum IS NULL OR streak_quorum > 0),
+    ADD COLUMN current_streak INTEGER DEFAULT 0 NOT NULL,
+    ADD COLUMN longest_streak INTEGER DEFAULT 0 NOT NULL,
+    ADD COLUMN last_streak_date DATE;
+
+COMMENT ON COLUMN public.squads.streak_quorum IS 'Active members needed per day (NULL = every member)';
+COMMENT ON COLUMN public.squads.current_streak IS 'Consecutive days the squad met its quorum';
+COMMENT ON COLUMN public.squads.last_streak_date IS 'Last day the squad met its quorum';
+
+-- Squad leaderboard
+CREATE INDEX idx_squads_current_streak ON public.squads(current_streak DESC)
+    WHERE current_streak > 0;
+
+-- ============================================================
+-- 2. SQUAD RISK ALERT LOG
+-- One alert per (squad, lagging member, member's local day, threshold)
+-- ============================================================
+
+CREATE TABLE public.squad_streak_risk_alerts (
+    squad_id UUID NOT NULL REFERENCES public.squads(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    local_date DATE NOT NULL,
+    threshold TEXT NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (squad_id, user_id, local_date, threshold)
+);
+
+COMMENT ON TABLE public.squad_streak_risk_alerts IS 'Dedupe log for squad streak risk events';
+
+-- ============================================================
+-- 3. RLS POLICIES
+-- Backend-only table: RLS on, no policies.
+-- ============================================================
+
+ALTER TABLE public.squad_streak_risk_alerts ENABLE ROW LEVEL SECURITY;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
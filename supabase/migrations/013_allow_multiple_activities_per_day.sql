@@ -0,0 +1,82 @@
+-- ============================================================
+-- 013_allow_multiple_activities_per_day.sql
+-- The Streak Engine - One row per activity, not per day
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. DROP THE ONE-ROW-PER-DAY CONSTRAINT
+-- Every activity is kept (with its metadata). The streak engine
+-- still counts distinct activity days.
+-- ============================================================
+
+ALTER TABLE public.activity_logs
+    DROP CONSTRAINT IF EXISTS activity_logs_user_id_activity_date_key;
+
+COMMENT ON TABLE public.activity_logs IS 'Records every user activity for streak calculation. Timezone-aware.';
+
+-- ============================================================
+-- 2. FOCUS MINUTES
+-- Extracted from metadata.duration so per-day effort can be
+-- summed without parsing JSON in every query.
+-- ============================================================
+
+ALTER TABLE public.activity_logs
+    ADD COLUMN focus_minutes INTEGER GENERATED ALWAYS AS (
+        CASE
+            WHEN jsonb_typeof(metadata->'duration') = 'number'
+                THEN GREATEST(0, (metadata->>'duration')::NUMERIC)::INTEGER
+            ELSE 0
+        END
+    ) STORED;
+
+COMMENT ON COLUMN public.activity_logs.focus_minutes IS 'Focus minutes of this activity (metadata.duration, 0 if absent)';
+
+-- ============================================================
+-- 3. ACTIVITY LOGGING FUNCTION
+-- Always inserts. is_new is TRUE for the first activity of the
+-- user's local day (the one that can extend the streak).
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.log_daily_activity(
+    p_user_id UUID,
+    p_activity_type TEXT,
+    p_metadata JSONB DEFAULT '{}'::jsonb
+)
+RETURNS TABLE(
+    success BOOLEAN,
+    activity_id UUID,
+    activity_date DATE,
+    is_new BOOLEAN
+)
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_activity_date DATE;
+    v_activity_id UUID;
+    v_first_today BOOLEAN;
+BEGIN
+    -- Date in the user's timezone
+    v_activity_date := (NOW() AT TIME ZONE get_user_timezone(p_user_id))::DATE;
+
+    v_first_today := NOT EXISTS (
+        SELECT 1 FROM activity_logs al
+        WHERE al.user_id = p_user_id
+          AND al.activity_date = v_activity_date
+    );
+
+    INSERT INTO activity_logs (user_id, activity_type, activity_date, metadata)
+    VALUES (p_user_id, p_activity_type, v_activity_date, COALESCE(p_metadata, '{}'::jsonb))
+    RETURNING id INTO v_activity_id;
+
+    RETURN QUERY SELECT TRUE, v_activity_id, v_activity_date, v_first_today;
+END;
+$$;
+
+COMMENT ON FUNCTION public.log_daily_activity IS 'Logs an activity. is_new is TRUE for the first activity of the local day.';
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
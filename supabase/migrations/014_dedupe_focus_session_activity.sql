@@ -0,0 +1,81 @@
+-- ============================================================
+-- 014_dedupe_focus_session_activity.sql
+-- The Streak Engine - Idempotent focus session credit
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. ONE ACTIVITY PER FOCUS SESSION
+-- Focus sessions are credited from redelivered events, so the
+-- session id in metadata is the idempotency key.
+-- ============================================================
+
+CREATE UNIQUE INDEX idx_activity_focus_session_id
+    ON public.activity_logs((metadata->>'session_id'))
+    WHERE activity_type = 'focus_session' AND metadata ? 'session_id';
+
+-- ============================================================
+-- 2. ACTIVITY LOGGING FUNCTION
+-- Adds is_duplicate: TRUE when the session was already logged
+-- (the existing row is returned and nothing is inserted).
+-- ============================================================
+
+DROP FUNCTION IF EXISTS public.log_daily_activity(UUID, TEXT, JSONB);
+
+CREATE FUNCTION public.log_daily_activity(
+    p_user_id UUID,
+    p_activity_type TEXT,
+    p_metadata JSONB DEFAULT '{}'::jsonb
+)
+RETURNS TABLE(
+    success BOOLEAN,
+    activity_id UUID,
+    activity_date DATE,
+    is_new BOOLEAN,
+    is_duplicate BOOLEAN
+)
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_activity_date DATE;
+    v_activity_id UUID;
+    v_first_today BOOLEAN;
+BEGIN
+    -- Date in the user's timezone
+    v_activity_date := (NOW() AT TIME ZONE get_user_timezone(p_user_id))::DATE;
+
+    v_first_today := NOT EXISTS (
+        SELECT 1 FROM activity_logs al
+        WHERE al.user_id = p_user_id
+          AND al.activity_date = v_activity_date
+    );
+
+    INSERT INTO activity_logs (user_id, activity_type, activity_date, metadata)
+    VALUES (p_user_id, p_activity_type, v_activity_date, COALESCE(p_metadata, '{}'::jsonb))
+    ON CONFLICT ((metadata->>'session_id'))
+        WHERE activity_type = 'focus_session' AND metadata ? 'session_id'
+        DO NOTHING
+    RETURNING id INTO v_activity_id;
+
+    IF v_activity_id IS NULL THEN
+        -- Session already credited
+        RETURN QUERY
+        SELECT TRUE, al.id, al.activity_date, FALSE, TRUE
+        FROM activity_logs al
+        WHERE al.activity_type = 'focus_session'
+          AND al.metadata ? 'session_id'
+          AND al.metadata->>'session_id' = p_metadata->>'session_id';
+        RETURN;
+    END IF;
+
+    RETURN QUERY SELECT TRUE, v_activity_id, v_activity_date, v_first_today, FALSE;
+END;
+$$;
+
+COMMENT ON FUNCTION public.log_daily_activity IS 'Logs an activity. is_new is TRUE for the first activity of the local day; is_duplicate when the focus session was already logged.';
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,21 @@
+-- ============================================================
+-- 015_add_streak_broken_notifications.sql
+-- The Streak Engine - "Start again" notifications
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. NOTIFICATION TYPE
+-- Sent when the nightly recalculation finds a streak dropped to zero.
+-- ============================================================
+
+ALTER TABLE public.notifications
+    DROP CONSTRAINT IF EXISTS notifications_type_check;
+
+ALTER TABLE public.notifications
+    ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('nudge', 'streak_alert', 'streak_broken', 'squad_invite', 'squad_streak_alert'));
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
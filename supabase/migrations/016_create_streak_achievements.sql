@@ -0,0 +1,68 @@
+-- ============================================================
+-- 016_create_streak_achievements.sql
+-- The Streak Engine - Milestones and personal records
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. TABLE DEFINITION
+-- A streak is identified by the day (or week) it started, so each
+-- milestone and record is celebrated once per streak.
+-- ============================================================
+
+CREATE TABLE public.streak_achievements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('milestone', 'personal_record')),
+    streak_mode TEXT NOT NULL,
+    streak_unit TEXT NOT NULL CHECK (streak_unit IN ('days', 'weeks')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    previous_record INTEGER CHECK (previous_record IS NULL OR previous_record > 0),
+    streak_started_on DATE NOT NULL,
+    achieved_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+COMMENT ON TABLE public.streak_achievements IS 'Streak milestones and personal records, one per streak';
+COMMENT ON COLUMN public.streak_achievements.value IS 'Milestone reached, or the new record length';
+COMMENT ON COLUMN public.streak_achievements.previous_record IS 'Longest streak that was beaten (personal records only)';
+
+-- ============================================================
+-- 2. INDEXES
+-- ============================================================
+
+CREATE UNIQUE INDEX idx_streak_achievements_milestone
+    ON public.streak_achievements(user_id, streak_unit, streak_started_on, value)
+    WHERE kind = 'milestone';
+
+CREATE UNIQUE INDEX idx_streak_achievements_record
+    ON public.streak_achievements(user_id, streak_unit, streak_started_on)
+    WHERE kind = 'personal_record';
+
+CREATE INDEX idx_streak_achievements_user
+    ON public.streak_achievements(user_id, achieved_at DESC);
+
+-- ============================================================
+-- 3. NOTIFICATION TYPE
+-- ============================================================
+
+ALTER TABLE public.notifications
+    DROP CONSTRAINT IF EXISTS notifications_type_check;
+
+ALTER TABLE public.notifications
+    ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('nudge', 'streak_alert', 'streak_broken', 'streak_milestone', 'squad_invite', 'squad_streak_alert'));
+
+-- ============================================================
+-- 4. RLS POLICIES
+-- ============================================================
+
+ALTER TABLE public.streak_achievements ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can read own streak achievements"
+    ON public.streak_achievements
+    FOR SELECT
+    USING (auth.uid() = user_id);
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
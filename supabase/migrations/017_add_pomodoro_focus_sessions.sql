@@ -0,0 +1,41 @@
+-- ============================================================
+-- 017_add_pomodoro_focus_sessions.sql
+-- Real-time Presence - Pomodoro mode
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. POMODORO SCHEDULE
+-- All NULL for a plain session. Sets of pomodoro_cycles work blocks
+-- are separated by short breaks and end in a long break. The backend
+-- derives the current phase from started_at and this schedule.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN pomodoro_work_minutes INTEGER CHECK (pomodoro_work_minutes BETWEEN 1 AND 180),
+    ADD COLUMN pomodoro_short_break_minutes INTEGER CHECK (pomodoro_short_break_minutes BETWEEN 1 AND 60),
+    ADD COLUMN pomodoro_long_break_minutes INTEGER CHECK (pomodoro_long_break_minutes BETWEEN 1 AND 120),
+    ADD COLUMN pomodoro_cycles INTEGER CHECK (pomodoro_cycles BETWEEN 1 AND 12),
+    ADD CONSTRAINT focus_sessions_pomodoro_complete CHECK (
+        (pomodoro_work_minutes IS NULL) = (pomodoro_short_break_minutes IS NULL)
+        AND (pomodoro_work_minutes IS NULL) = (pomodoro_long_break_minutes IS NULL)
+        AND (pomodoro_work_minutes IS NULL) = (pomodoro_cycles IS NULL)
+    );
+
+COMMENT ON COLUMN public.focus_sessions.pomodoro_work_minutes IS 'Pomodoro work block length (NULL = plain session)';
+COMMENT ON COLUMN public.focus_sessions.pomodoro_cycles IS 'Work blocks per set, before the long break';
+
+-- ============================================================
+-- 2. COMPLETED POMODOROS
+-- Stored when the session ends, for history and stats.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN completed_pomodoros INTEGER DEFAULT 0 NOT NULL
+        CHECK (completed_pomodoros >= 0);
+
+COMMENT ON COLUMN public.focus_sessions.completed_pomodoros IS 'Work blocks finished before the session ended';
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
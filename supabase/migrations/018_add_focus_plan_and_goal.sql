@@ -0,0 +1,46 @@
+-- ============================================================
+-- 018_add_focus_plan_and_goal.sql
+-- Real-time Presence - Planned duration, goal and auto-stop
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. PLAN AND GOAL
+-- Set when the session starts. Sessions without a plan are
+-- open-ended and never auto-stopped.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN planned_minutes INTEGER CHECK (planned_minutes BETWEEN 1 AND 720),
+    ADD COLUMN goal TEXT CHECK (char_length(goal) BETWEEN 1 AND 200);
+
+COMMENT ON COLUMN public.focus_sessions.planned_minutes IS 'Planned session length (NULL = open-ended)';
+COMMENT ON COLUMN public.focus_sessions.goal IS 'What the user wants to get done in this session';
+
+-- ============================================================
+-- 2. OUTCOME
+-- auto_ended marks sessions closed by the sweeper once they ran
+-- past their plan plus a grace period. goal_met stays NULL until
+-- the user says whether the goal was met.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN auto_ended BOOLEAN DEFAULT FALSE NOT NULL,
+    ADD COLUMN goal_met BOOLEAN,
+    ADD CONSTRAINT focus_sessions_goal_met_needs_goal CHECK (goal_met IS NULL OR goal IS NOT NULL);
+
+COMMENT ON COLUMN public.focus_sessions.auto_ended IS 'TRUE if the session was ended by the auto-stop sweeper';
+COMMENT ON COLUMN public.focus_sessions.goal_met IS 'Whether the goal was met (NULL = not answered)';
+
+-- ============================================================
+-- 3. INDEXES
+-- ============================================================
+
+-- Sweeper scan: active sessions that have a plan
+CREATE INDEX idx_focus_sessions_planned_active
+    ON public.focus_sessions(started_at)
+    WHERE ended_at IS NULL AND planned_minutes IS NOT NULL;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,38 @@
+-- ============================================================
+-- 019_add_focus_heartbeats.sql
+-- Real-time Presence - Heartbeats
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. LAST HEARTBEAT
+-- Clients ping POST /api/v1/focus/heartbeat while a session is
+-- open. Sessions that go quiet are closed at their last heartbeat,
+-- so a crashed tab is credited only for the time it was present.
+-- Existing rows are backfilled with the best known presence.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN last_heartbeat_at TIMESTAMPTZ;
+
+UPDATE public.focus_sessions
+SET last_heartbeat_at = COALESCE(ended_at, started_at);
+
+ALTER TABLE public.focus_sessions
+    ALTER COLUMN last_heartbeat_at SET DEFAULT NOW(),
+    ALTER COLUMN last_heartbeat_at SET NOT NULL;
+
+COMMENT ON COLUMN public.focus_sessions.last_heartbeat_at IS 'Last time the client confirmed the user is still present';
+
+-- ============================================================
+-- 2. INDEXES
+-- ============================================================
+
+-- Sweeper scan: active sessions by last heartbeat
+CREATE INDEX idx_focus_sessions_heartbeat_active
+    ON public.focus_sessions(last_heartbeat_at)
+    WHERE ended_at IS NULL;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
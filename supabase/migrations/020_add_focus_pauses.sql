@@ -0,0 +1,112 @@
+-- ============================================================
+-- 020_add_focus_pauses.sql
+-- Real-time Presence - Pause and resume
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. PAUSE STATE
+-- paused_at is set while the session is paused. paused_seconds
+-- totals the finished pauses, so net focus time is wall time
+-- minus paused_seconds.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN paused_at TIMESTAMPTZ,
+    ADD COLUMN paused_seconds INTEGER DEFAULT 0 NOT NULL CHECK (paused_seconds >= 0);
+
+COMMENT ON COLUMN public.focus_sessions.paused_at IS 'Start of the current pause (NULL = not paused)';
+COMMENT ON COLUMN public.focus_sessions.paused_seconds IS 'Total length of finished pauses';
+
+-- ============================================================
+-- 2. PAUSE INTERVALS
+-- One row per pause. resumed_at = NULL is the open pause.
+-- ============================================================
+
+CREATE TABLE public.focus_session_pauses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES public.focus_sessions(id) ON DELETE CASCADE,
+    paused_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    resumed_at TIMESTAMPTZ,
+    CHECK (resumed_at IS NULL OR resumed_at >= paused_at)
+);
+
+COMMENT ON TABLE public.focus_session_pauses IS 'Pause intervals of focus sessions';
+
+-- At most one open pause per session
+CREATE UNIQUE INDEX idx_focus_session_pauses_open
+    ON public.focus_session_pauses(session_id)
+    WHERE resumed_at IS NULL;
+
+CREATE INDEX idx_focus_session_pauses_session
+    ON public.focus_session_pauses(session_id, paused_at);
+
+-- ============================================================
+-- 3. NET DURATION
+-- duration_minutes becomes net focused time of ended sessions.
+-- Active sessions have no stored duration; the backend derives it
+-- from the last heartbeat.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions DROP COLUMN duration_minutes;
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN duration_minutes INTEGER GENERATED ALWAYS AS (
+        CASE WHEN ended_at IS NULL THEN NULL
+        ELSE GREATEST(0, EXTRACT(EPOCH FROM (ended_at - started_at))::INTEGER - paused_seconds) / 60
+        END
+    ) STORED;
+
+COMMENT ON COLUMN public.focus_sessions.duration_minutes IS 'Net focused minutes (pauses excluded), NULL while active';
+
+-- ============================================================
+-- 4. CLOSE THE OPEN PAUSE ON END
+-- However a session ends (stop, heartbeat timeout, auto-stop),
+-- a pause still open is closed at ended_at and counted.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.close_focus_pause_on_end()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW.paused_seconds := NEW.paused_seconds
+        + GREATEST(0, EXTRACT(EPOCH FROM (NEW.ended_at - OLD.paused_at)))::INTEGER;
+    NEW.paused_at := NULL;
+
+    UPDATE public.focus_session_pauses
+    SET resumed_at = GREATEST(paused_at, NEW.ended_at)
+    WHERE session_id = NEW.id AND resumed_at IS NULL;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER on_focus_session_end_close_pause
+    BEFORE UPDATE OF ended_at ON public.focus_sessions
+    FOR EACH ROW
+    WHEN (OLD.ended_at IS NULL AND NEW.ended_at IS NOT NULL AND OLD.paused_at IS NOT NULL)
+    EXECUTE FUNCTION public.close_focus_pause_on_end();
+
+-- ============================================================
+-- 5. RLS POLICIES
+-- ============================================================
+
+ALTER TABLE public.focus_session_pauses ENABLE ROW LEVEL SECURITY;
+
+-- Users can view the pauses of their own sessions
+CREATE POLICY "Users can view own focus pauses"
+    ON public.focus_session_pauses
+    FOR SELECT
+    TO authenticated
+    USING (
+        EXISTS (
+            SELECT 1 FROM public.focus_sessions fs
+            WHERE fs.id = focus_session_pauses.session_id
+            AND fs.user_id = auth.uid()
+        )
+    );
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,152 @@
+-- ============================================================
+-- 021_create_study_rooms.sql
+-- Real-time Presence - Scheduled study rooms
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. STUDY ROOMS TABLE
+-- A group focus block scheduled within a squad. Members RSVP and,
+-- once it starts, join with a focus session linked to the room.
+-- cancelled_at = NULL indicates the room is still on.
+-- ============================================================
+
+CREATE TABLE public.study_rooms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    squad_id UUID NOT NULL REFERENCES public.squads(id) ON DELETE CASCADE,
+    host_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    topic TEXT NOT NULL CHECK (char_length(topic) BETWEEN 1 AND 120),
+    starts_at TIMESTAMPTZ NOT NULL,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes BETWEEN 5 AND 720),
+    cancelled_at TIMESTAMPTZ,
+    reminder_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+COMMENT ON TABLE public.study_rooms IS 'Scheduled group focus blocks within a squad';
+COMMENT ON COLUMN public.study_rooms.host_id IS 'Member who scheduled the room';
+COMMENT ON COLUMN public.study_rooms.cancelled_at IS 'NULL indicates the room is still on';
+COMMENT ON COLUMN public.study_rooms.reminder_sent_at IS 'When the pre-start reminder went out (NULL = not yet)';
+
+-- ============================================================
+-- 2. RSVPS
+-- One answer per member and room, changeable until the room ends.
+-- ============================================================
+
+CREATE TABLE public.study_room_rsvps (
+    room_id UUID NOT NULL REFERENCES public.study_rooms(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('going', 'maybe', 'declined')),
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (room_id, user_id)
+);
+
+COMMENT ON TABLE public.study_room_rsvps IS 'Member answers to study room invitations';
+
+-- ============================================================
+-- 3. ATTENDANCE
+-- One row per member who joined. joined_at is the first join;
+-- session_id is the latest focus session started from the room.
+-- ============================================================
+
+CREATE TABLE public.study_room_attendance (
+    room_id UUID NOT NULL REFERENCES public.study_rooms(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    session_id UUID REFERENCES public.focus_sessions(id) ON DELETE SET NULL,
+    joined_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (room_id, user_id)
+);
+
+COMMENT ON TABLE public.study_room_attendance IS 'Members who joined a study room';
+
+-- ============================================================
+-- 4. LINKED FOCUS SESSIONS
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN study_room_id UUID REFERENCES public.study_rooms(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN public.focus_sessions.study_room_id IS 'Study room the session was started from (NULL = solo)';
+
+-- ============================================================
+-- 5. INDEXES
+-- ============================================================
+
+-- Upcoming rooms of a squad
+CREATE INDEX idx_study_rooms_squad_starts
+    ON public.study_rooms(squad_id, starts_at)
+    WHERE cancelled_at IS NULL;
+
+-- Reminder sweep: rooms still waiting for their reminder
+CREATE INDEX idx_study_rooms_reminder_pending
+    ON public.study_rooms(starts_at)
+    WHERE cancelled_at IS NULL AND reminder_sent_at IS NULL;
+
+-- Time focused in a room
+CREATE INDEX idx_focus_sessions_study_room
+    ON public.focus_sessions(study_room_id)
+    WHERE study_room_id IS NOT NULL;
+
+-- ============================================================
+-- 6. NOTIFICATION TYPE
+-- Sent to members who RSVP'd shortly before a room starts.
+-- ============================================================
+
+ALTER TABLE public.notifications
+    DROP CONSTRAINT IF EXISTS notifications_type_check;
+
+ALTER TABLE public.notifications
+    ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('nudge', 'streak_alert', 'streak_broken', 'streak_milestone', 'squad_invite', 'squad_streak_alert', 'study_room_reminder'));
+
+-- ============================================================
+-- 7. RLS POLICIES
+-- Squad members can view their squad's rooms, RSVPs and
+-- attendance. Writes go through the backend.
+-- ============================================================
+
+ALTER TABLE public.study_rooms ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.study_room_rsvps ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.study_room_attendance ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Members can view squad study rooms"
+    ON public.study_rooms
+    FOR SELECT
+    TO authenticated
+    USING (
+        EXISTS (
+            SELECT 1 FROM public.squad_members sm
+            WHERE sm.squad_id = study_rooms.squad_id
+            AND sm.user_id = auth.uid()
+        )
+    );
+
+CREATE POLICY "Members can view squad study room RSVPs"
+    ON public.study_room_rsvps
+    FOR SELECT
+    TO authenticated
+    USING (
+        EXISTS (
+            SELECT 1 FROM public.study_rooms sr
+            JOIN public.squad_members sm ON sm.squad_id = sr.squad_id
+            WHERE sr.id = study_room_rsvps.room_id
+            AND sm.user_id = auth.uid()
+        )
+    );
+
+CREATE POLICY "Members can view squad study room attendance"
+    ON public.study_room_attendance
+    FOR SELECT
+    TO authenticated
+    USING (
+        EXISTS (
+            SELECT 1 FROM public.study_rooms sr
+            JOIN public.squad_members sm ON sm.squad_id = sr.squad_id
+            WHERE sr.id = study_room_attendance.room_id
+            AND sm.user_id = auth.uid()
+        )
+    );
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,34 @@
+-- ============================================================
+-- 022_create_calendar_feeds.sql
+-- Real-time Presence - iCalendar feeds
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. CALENDAR FEEDS TABLE
+-- One secret feed URL per user. Only a SHA-256 hash of the token
+-- is stored; the token itself is shown once, when generated.
+-- Regenerating replaces the hash, which revokes the old URL.
+-- ============================================================
+
+CREATE TABLE public.calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES public.profiles(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    last_fetched_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE public.calendar_feeds IS 'Secret iCalendar feed tokens, one per user';
+COMMENT ON COLUMN public.calendar_feeds.token_hash IS 'Hex SHA-256 of the feed token';
+COMMENT ON COLUMN public.calendar_feeds.last_fetched_at IS 'Last time a calendar client fetched the feed';
+
+-- ============================================================
+-- 2. RLS POLICIES
+-- Backend-only table: RLS on, no policies.
+-- ============================================================
+
+ALTER TABLE public.calendar_feeds ENABLE ROW LEVEL SECURITY;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
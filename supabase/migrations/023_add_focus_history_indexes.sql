@@ -0,0 +1,24 @@
+-- ============================================================
+-- 023_add_focus_history_indexes.sql
+-- Real-time Presence - Paginated focus history
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. KEYSET INDEXES
+-- History pages are ordered by (started_at, id) newest first and
+-- continue after the last row of the previous page, in one squad
+-- or across all of a user's sessions.
+-- ============================================================
+
+CREATE INDEX idx_focus_sessions_squad_history
+    ON public.focus_sessions(squad_id, started_at DESC, id DESC)
+    WHERE ended_at IS NOT NULL;
+
+CREATE INDEX idx_focus_sessions_user_history
+    ON public.focus_sessions(user_id, started_at DESC, id DESC)
+    WHERE ended_at IS NOT NULL;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
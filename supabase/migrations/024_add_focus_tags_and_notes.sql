@@ -0,0 +1,55 @@
+-- ============================================================
+-- 024_add_focus_tags_and_notes.sql
+-- Real-time Presence - Session tags, subjects and notes
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. TAGS AND NOTE
+-- tags are what was studied, normalized by the backend
+-- (lowercase, single spaces, deduplicated). note is written
+-- when the session stops and is only shown to its owner.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN tags TEXT[] DEFAULT '{}' NOT NULL CHECK (cardinality(tags) <= 5),
+    ADD COLUMN note TEXT CHECK (char_length(note) BETWEEN 1 AND 1000);
+
+COMMENT ON COLUMN public.focus_sessions.tags IS 'Subjects studied in the session, normalized';
+COMMENT ON COLUMN public.focus_sessions.note IS 'Post-session note, private to the session owner';
+
+-- History tag filters and per-subject breakdowns
+CREATE INDEX idx_focus_sessions_tags
+    ON public.focus_sessions USING GIN (tags);
+
+-- ============================================================
+-- 2. FAVOURITE TAGS
+-- Per-user shortlist offered when starting a session
+-- ============================================================
+
+CREATE TABLE public.favourite_tags (
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL CHECK (char_length(tag) BETWEEN 1 AND 32),
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (user_id, tag)
+);
+
+COMMENT ON TABLE public.favourite_tags IS 'Tags a user pinned for quick selection';
+COMMENT ON COLUMN public.favourite_tags.position IS 'Display order, 0 first';
+
+-- ============================================================
+-- 3. RLS POLICIES
+-- ============================================================
+
+ALTER TABLE public.favourite_tags ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can view own favourite tags"
+    ON public.favourite_tags
+    FOR SELECT
+    TO authenticated
+    USING (favourite_tags.user_id = auth.uid());
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
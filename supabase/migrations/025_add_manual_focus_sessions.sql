@@ -0,0 +1,119 @@
+-- ============================================================
+-- 025_add_manual_focus_sessions.sql
+-- Real-time Presence - Backfilled focus sessions
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. MANUAL FLAG
+-- Sessions logged after the fact with explicit start and end
+-- times, shown as such to the squad.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ADD COLUMN manual BOOLEAN DEFAULT FALSE NOT NULL;
+
+COMMENT ON COLUMN public.focus_sessions.manual IS 'TRUE if the session was backfilled rather than tracked live';
+
+-- ============================================================
+-- 2. AUDIT TRAIL
+-- One row per backfill, keeping what the user submitted and
+-- when, even if the session is edited later.
+-- ============================================================
+
+CREATE TABLE public.focus_session_audit (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES public.focus_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('backfilled')),
+    details JSONB DEFAULT '{}'::jsonb NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+COMMENT ON TABLE public.focus_session_audit IS 'Changes made to focus sessions outside live tracking';
+
+CREATE INDEX idx_focus_session_audit_session
+    ON public.focus_session_audit(session_id);
+
+CREATE INDEX idx_focus_session_audit_user_time
+    ON public.focus_session_audit(user_id, created_at DESC);
+
+-- ============================================================
+-- 3. DATED ACTIVITY LOGGING
+-- Backfilled sessions credit the local day they ended on, not
+-- the day they were logged. p_activity_date = NULL keeps
+-- crediting today.
+-- ============================================================
+
+DROP FUNCTION IF EXISTS public.log_daily_activity(UUID, TEXT, JSONB);
+
+CREATE FUNCTION public.log_daily_activity(
+    p_user_id UUID,
+    p_activity_type TEXT,
+    p_metadata JSONB DEFAULT '{}'::jsonb,
+    p_activity_date DATE DEFAULT NULL
+)
+RETURNS TABLE(
+    success BOOLEAN,
+    activity_id UUID,
+    activity_date DATE,
+    is_new BOOLEAN,
+    is_duplicate BOOLEAN
+)
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_activity_date DATE;
+    v_activity_id UUID;
+    v_first_today BOOLEAN;
+BEGIN
+    -- Date in the user's timezone
+    v_activity_date := COALESCE(p_activity_date, (NOW() AT TIME ZONE get_user_timezone(p_user_id))::DATE);
+
+    v_first_today := NOT EXISTS (
+        SELECT 1 FROM activity_logs al
+        WHERE al.user_id = p_user_id
+          AND al.activity_date = v_activity_date
+    );
+
+    INSERT INTO activity_logs (user_id, activity_type, activity_date, metadata)
+    VALUES (p_user_id, p_activity_type, v_activity_date, COALESCE(p_metadata, '{}'::jsonb))
+    ON CONFLICT ((metadata->>'session_id'))
+        WHERE activity_type = 'focus_session' AND metadata ? 'session_id'
+        DO NOTHING
+    RETURNING id INTO v_activity_id;
+
+    IF v_activity_id IS NULL THEN
+        -- Session already credited
+        RETURN QUERY
+        SELECT TRUE, al.id, al.activity_date, FALSE, TRUE
+        FROM activity_logs al
+        WHERE al.activity_type = 'focus_session'
+          AND al.metadata ? 'session_id'
+          AND al.metadata->>'session_id' = p_metadata->>'session_id';
+        RETURN;
+    END IF;
+
+    RETURN QUERY SELECT TRUE, v_activity_id, v_activity_date, v_first_today, FALSE;
+END;
+$$;
+
+COMMENT ON FUNCTION public.log_daily_activity IS 'Logs an activity on p_activity_date, or today in the user''s timezone. is_new is TRUE for the first activity of that day; is_duplicate when the focus session was already logged.';
+
+-- ============================================================
+-- 4. RLS POLICIES
+-- ============================================================
+
+ALTER TABLE public.focus_session_audit ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Users can view own focus session audit"
+    ON public.focus_session_audit
+    FOR SELECT
+    TO authenticated
+    USING (focus_session_audit.user_id = auth.uid());
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,78 @@
+-- ============================================================
+-- 026_add_solo_focus_sessions.sql
+-- Real-time Presence - Solo focus sessions
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. OPTIONAL SQUAD
+-- squad_id = NULL is a solo session. It counts toward the
+-- owner's stats and streak. With broadcast set, it also shows
+-- in every squad the owner belongs to.
+-- ============================================================
+
+ALTER TABLE public.focus_sessions
+    ALTER COLUMN squad_id DROP NOT NULL,
+    ADD COLUMN broadcast BOOLEAN DEFAULT FALSE NOT NULL,
+    ADD CONSTRAINT focus_sessions_broadcast_is_solo CHECK (NOT broadcast OR squad_id IS NULL);
+
+COMMENT ON COLUMN public.focus_sessions.squad_id IS 'Squad the session is shared with (NULL = solo)';
+COMMENT ON COLUMN public.focus_sessions.broadcast IS 'Solo session shown to all of the owner''s squads';
+
+-- Active broadcast sessions, listed per squad through the owner
+CREATE INDEX idx_focus_sessions_broadcast_active
+    ON public.focus_sessions(user_id)
+    WHERE ended_at IS NULL AND broadcast;
+
+-- ============================================================
+-- 2. RLS POLICIES
+-- Owners see all their sessions; squadmates see the squad's
+-- sessions and broadcast solo sessions of fellow members.
+-- ============================================================
+
+DROP POLICY IF EXISTS "Squad members can view focus sessions" ON public.focus_sessions;
+
+CREATE POLICY "Squad members can view focus sessions"
+    ON public.focus_sessions
+    FOR SELECT
+    TO authenticated
+    USING (
+        focus_sessions.user_id = auth.uid()
+        OR EXISTS (
+            SELECT 1 FROM public.squad_members sm
+            WHERE sm.squad_id = focus_sessions.squad_id
+            AND sm.user_id = auth.uid()
+        )
+        OR (
+            focus_sessions.broadcast
+            AND EXISTS (
+                SELECT 1
+                FROM public.squad_members mine
+                JOIN public.squad_members theirs ON theirs.squad_id = mine.squad_id
+                WHERE mine.user_id = auth.uid()
+                AND theirs.user_id = focus_sessions.user_id
+            )
+        )
+    );
+
+DROP POLICY IF EXISTS "Users can start own focus sessions" ON public.focus_sessions;
+
+CREATE POLICY "Users can start own focus sessions"
+    ON public.focus_sessions
+    FOR INSERT
+    TO authenticated
+    WITH CHECK (
+        focus_sessions.user_id = auth.uid()
+        AND (
+            focus_sessions.squad_id IS NULL
+            OR EXISTS (
+                SELECT 1 FROM public.squad_members sm
+                WHERE sm.squad_id = focus_sessions.squad_id
+                AND sm.user_id = auth.uid()
+            )
+        )
+    );
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
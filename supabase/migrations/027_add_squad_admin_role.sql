@@ -0,0 +1,168 @@
+-- ============================================================
+-- 027_add_squad_admin_role.sql
+-- Real-time Presence - Squad admins
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. ADMIN ROLE
+-- Admins co-lead a squad: they can edit it, regenerate the
+-- invite code, kick members and cancel study rooms. Only the
+-- owner can delete the squad or change roles.
+-- ============================================================
+
+ALTER TABLE public.squad_members
+    DROP CONSTRAINT IF EXISTS squad_members_role_check;
+
+ALTER TABLE public.squad_members
+    ALTER COLUMN role SET NOT NULL,
+    ADD CONSTRAINT squad_members_role_check CHECK (role IN ('owner', 'admin', 'member'));
+
+COMMENT ON COLUMN public.squad_members.role IS 'owner (one per squad), admin (co-lead) or member';
+
+-- ============================================================
+-- 2. RLS POLICIES
+-- ============================================================
+
+DROP POLICY IF EXISTS "Owners can update their squads" ON public.squads;
+
+CREATE POLICY "Owners and admins can update their squads"
+    ON public.squads
+    FOR UPDATE
+    TO authenticated
+    USING (
+        EXISTS (
+            SELECT 1 FROM public.squad_members sm
+            WHERE sm.squad_id = squads.id
+            AND sm.user_id = auth.uid()
+            AND sm.role IN ('owner', 'admin')
+        )
+    )
+    WITH CHECK (
+        EXISTS (
+            SELECT 1 FROM public.squad_members sm
+            WHERE sm.squad_id = squads.id
+            AND sm.user_id = auth.uid()
+            AND sm.role IN ('owner', 'admin')
+        )
+    );
+
+DROP POLICY IF EXISTS "Members can leave or owners can kick" ON public.squad_members;
+
+-- Owners kick anyone else; admins kick members only
+CREATE POLICY "Members can leave or leads can kick"
+    ON public.squad_members
+    FOR DELETE
+    TO authenticated
+    USING (
+        squad_members.user_id = auth.uid()
+        OR EXISTS (
+            SELECT 1 FROM public.squad_members caller
+            WHERE caller.squad_id = squad_members.squad_id
+            AND caller.user_id = auth.uid()
+            AND (
+                caller.role = 'owner'
+                OR (caller.role = 'admin' AND squad_members.role = 'member')
+            )
+        )
+    );
+
+-- ============================================================
+-- 3. FUNCTION: Kick Member (Owner or Admin)
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.kick_member(p_squad_id UUID, p_user_id UUID)
+RETURNS BOOLEAN
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_caller_id UUID;
+    v_caller_role TEXT;
+    v_target_role TEXT;
+BEGIN
+    v_caller_id := auth.uid();
+    IF v_caller_id IS NULL THEN
+        RAISE EXCEPTION 'Not authenticated';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM public.squads WHERE id = p_squad_id) THEN
+        RAISE EXCEPTION 'Squad not found';
+    END IF;
+
+    SELECT role INTO v_caller_role
+    FROM public.squad_members
+    WHERE squad_id = p_squad_id AND user_id = v_caller_id;
+
+    SELECT role INTO v_target_role
+    FROM public.squad_members
+    WHERE squad_id = p_squad_id AND user_id = p_user_id;
+
+    IF v_target_role IS NULL THEN
+        RAISE EXCEPTION 'User is not a member of this squad';
+    END IF;
+
+    IF v_target_role = 'owner' THEN
+        RAISE EXCEPTION 'Owner cannot be kicked';
+    END IF;
+
+    IF NOT (
+        v_caller_role = 'owner'
+        OR (v_caller_role = 'admin' AND v_target_role = 'member')
+    ) THEN
+        RAISE EXCEPTION 'Squad role does not allow kicking this member';
+    END IF;
+
+    DELETE FROM public.squad_members
+    WHERE squad_id = p_squad_id AND user_id = p_user_id;
+
+    RETURN TRUE;
+END;
+$$;
+
+-- ============================================================
+-- 4. FUNCTION: Regenerate Invite Code (Owner or Admin)
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.regenerate_invite_code(p_squad_id UUID)
+RETURNS TEXT
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_caller_id UUID;
+    v_new_code TEXT;
+BEGIN
+    v_caller_id := auth.uid();
+    IF v_caller_id IS NULL THEN
+        RAISE EXCEPTION 'Not authenticated';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM public.squads WHERE id = p_squad_id) THEN
+        RAISE EXCEPTION 'Squad not found';
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM public.squad_members
+        WHERE squad_id = p_squad_id
+        AND user_id = v_caller_id
+        AND role IN ('owner', 'admin')
+    ) THEN
+        RAISE EXCEPTION 'Only squad owner or admins can regenerate invite code';
+    END IF;
+
+    v_new_code := public.generate_invite_code();
+
+    UPDATE public.squads
+    SET invite_code = v_new_code
+    WHERE id = p_squad_id;
+
+    RETURN v_new_code;
+END;
+$$;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,116 @@
+-- ============================================================
+-- 028_add_squad_archiving.sql
+-- Real-time Presence - Ownership hand-over and archived squads
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. ARCHIVED SQUADS
+-- A squad whose last member left is archived rather than
+-- deleted: its history stays, but it can't be joined and
+-- drops off the leaderboard.
+-- ============================================================
+
+ALTER TABLE public.squads
+    ADD COLUMN archived_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN public.squads.archived_at IS 'When the last member left (NULL = active)';
+
+CREATE INDEX idx_squads_active_streak
+    ON public.squads(current_streak DESC, longest_streak DESC)
+    WHERE archived_at IS NULL;
+
+-- ============================================================
+-- 2. REPAIR ORPHANED SQUADS
+-- Owners could leave without handing over, leaving owner_id
+-- pointing at a non-member. Pass those squads to the
+-- longest-tenured admin, else member; archive empty ones.
+-- ============================================================
+
+WITH successors AS (
+    SELECT DISTINCT ON (sm.squad_id) sm.squad_id, sm.user_id
+    FROM public.squad_members sm
+    JOIN public.squads s ON s.id = sm.squad_id
+    WHERE NOT EXISTS (
+        SELECT 1 FROM public.squad_members owner_sm
+        WHERE owner_sm.squad_id = s.id
+        AND owner_sm.user_id = s.owner_id
+    )
+    ORDER BY sm.squad_id, (sm.role = 'admin') DESC, sm.joined_at ASC
+),
+new_owners AS (
+    UPDATE public.squads s
+    SET owner_id = successors.user_id
+    FROM successors
+    WHERE s.id = successors.squad_id
+    RETURNING s.id, s.owner_id
+)
+UPDATE public.squad_members sm
+SET role = 'owner'
+FROM new_owners
+WHERE sm.squad_id = new_owners.id
+AND sm.user_id = new_owners.owner_id;
+
+UPDATE public.squads s
+SET archived_at = NOW()
+WHERE NOT EXISTS (
+    SELECT 1 FROM public.squad_members sm
+    WHERE sm.squad_id = s.id
+);
+
+-- ============================================================
+-- 3. FUNCTION: Join Squad via Invite Code
+-- Archived squads can't be joined.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.join_squad(p_invite_code TEXT)
+RETURNS UUID
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_squad_id UUID;
+    v_max_members INTEGER;
+    v_current_count INTEGER;
+    v_user_id UUID;
+BEGIN
+    v_user_id := auth.uid();
+    IF v_user_id IS NULL THEN
+        RAISE EXCEPTION 'Not authenticated';
+    END IF;
+
+    SELECT id, max_members INTO v_squad_id, v_max_members
+    FROM public.squads
+    WHERE invite_code = UPPER(p_invite_code)
+    AND archived_at IS NULL;
+
+    IF v_squad_id IS NULL THEN
+        RAISE EXCEPTION 'Invalid invite code';
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM public.squad_members
+        WHERE squad_id = v_squad_id AND user_id = v_user_id
+    ) THEN
+        RAISE EXCEPTION 'Already a member of this squad';
+    END IF;
+
+    SELECT COUNT(*) INTO v_current_count
+    FROM public.squad_members
+    WHERE squad_id = v_squad_id;
+
+    IF v_current_count >= v_max_members THEN
+        RAISE EXCEPTION 'Squad is full (% members max)', v_max_members;
+    END IF;
+
+    INSERT INTO public.squad_members (squad_id, user_id, role)
+    VALUES (v_squad_id, v_user_id, 'member');
+
+    RETURN v_squad_id;
+END;
+$$;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,25 @@
+-- ============================================================
+-- 029_allow_squad_streak_alert_notifications.sql
+-- Squad Streaks - Alert notification type
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. NOTIFICATION TYPE
+-- Squad streak alerts go to the lagging member and teammates.
+-- 012 shipped without allowing them, so databases that applied
+-- it before 015 rejected every alert. Restated here with the
+-- full list so each database converges on it, whatever it
+-- applied before.
+-- ============================================================
+
+ALTER TABLE public.notifications
+    DROP CONSTRAINT IF EXISTS notifications_type_check;
+
+ALTER TABLE public.notifications
+    ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('nudge', 'streak_alert', 'streak_broken', 'streak_milestone', 'squad_invite', 'squad_streak_alert', 'study_room_reminder'));
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,22 @@
+-- ============================================================
+-- 030_add_squad_owner_notifications.sql
+-- Real-time Presence - Squad owner change notifications
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. NOTIFICATION TYPE
+-- Sent to every member when a squad is handed over or its
+-- owner leaves.
+-- ============================================================
+
+ALTER TABLE public.notifications
+    DROP CONSTRAINT IF EXISTS notifications_type_check;
+
+ALTER TABLE public.notifications
+    ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('nudge', 'streak_alert', 'streak_broken', 'streak_milestone', 'squad_invite', 'squad_streak_alert', 'study_room_reminder', 'squad_owner_changed'));
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,38 @@
+-- ============================================================
+-- 031_key_weekly_streak_records_by_target.sql
+-- The Streak Engine - Longest weekly streak per weekly target
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. LONGEST WEEKLY STREAK PER TARGET
+-- A week reaching 1 active day is far easier than one reaching
+-- 5, so each weekly:N target keeps its own record:
+-- longest_weekly_streaks[N] is the longest weekly:N streak.
+-- Switching targets never carries a record over, and switching
+-- back finds the old one again.
+-- ============================================================
+
+ALTER TABLE public.profiles
+    ADD COLUMN longest_weekly_streaks INTEGER[] DEFAULT '{0,0,0,0,0,0,0}' NOT NULL
+        CHECK (array_length(longest_weekly_streaks, 1) = 7 AND 0 <= ALL(longest_weekly_streaks));
+
+COMMENT ON COLUMN public.profiles.longest_weekly_streaks IS 'Longest weekly streak (weeks) per target; element N is for weekly:N';
+
+-- ============================================================
+-- 2. BACKFILL
+-- The shared record can't be split by target. It goes to the
+-- user's current target; users now in daily mode start their
+-- weekly records over.
+-- ============================================================
+
+UPDATE public.profiles
+SET longest_weekly_streaks[split_part(streak_mode, ':', 2)::INTEGER] = longest_weekly_streak
+WHERE streak_mode LIKE 'weekly:%';
+
+ALTER TABLE public.profiles
+    DROP COLUMN longest_weekly_streak;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================
@@ -0,0 +1,73 @@
+-- ============================================================
+-- 032_record_granted_freeze_counts.sql
+-- The Streak Engine - Ledger grants match the token balance
+-- Agent Alpha | Project Antigravity
+-- ============================================================
+
+-- ============================================================
+-- 1. TOKENS PER LEDGER ENTRY
+-- A grant can add more than one token, or fewer than asked
+-- when the balance hits the cap. Earned and used entries are
+-- always one token.
+-- Older grants keep 1: what the cap let through can't be told
+-- apart afterwards.
+-- ============================================================
+
+ALTER TABLE public.streak_freezes
+    ADD COLUMN count INTEGER DEFAULT 1 NOT NULL
+        CHECK (count > 0);
+
+COMMENT ON COLUMN public.streak_freezes.count IS 'Tokens the entry added or spent';
+
+-- ============================================================
+-- 2. GRANT FREEZES (replaces 009 version)
+-- Adds tokens (capped at 2) and records what was actually
+-- granted. A grant the cap turns into nothing leaves no entry.
+-- Returns the new balance.
+-- ============================================================
+
+CREATE OR REPLACE FUNCTION public.grant_streak_freezes(
+    p_user_id UUID,
+    p_count INTEGER,
+    p_note TEXT DEFAULT NULL
+)
+RETURNS INTEGER
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $$
+DECLARE
+    v_before INT;
+    v_balance INT;
+BEGIN
+    SELECT freeze_tokens INTO v_before
+    FROM public.profiles
+    WHERE id = p_user_id
+    FOR UPDATE;
+
+    IF v_before IS NULL THEN
+        RAISE EXCEPTION 'Profile not found';
+    END IF;
+
+    v_balance := LEAST(2, v_before + GREATEST(p_count, 0));
+
+    IF v_balance = v_before THEN
+        RETURN v_balance;
+    END IF;
+
+    UPDATE public.profiles
+    SET freeze_tokens = v_balance,
+        updated_at = NOW()
+    WHERE id = p_user_id;
+
+    INSERT INTO streak_freezes (user_id, event, freeze_date, count, note)
+    VALUES (p_user_id, 'granted', (NOW() AT TIME ZONE get_user_timezone(p_user_id))::DATE,
+            v_balance - v_before, p_note);
+
+    RETURN v_balance;
+END;
+$$;
+
+-- ============================================================
+-- END OF MIGRATION
+-- ============================================================